@@ -1,13 +1,20 @@
 package main
 
 import (
-	"database/sql"
 	"fmt"
 	"log"
 	"net/http"
+	_ "time/tzdata" // Store timezone without system zoneinfo
 
+	"kasir-api/internal/auth"
+	"kasir-api/internal/businessday"
 	"kasir-api/internal/config"
 	"kasir-api/internal/handler"
+	"kasir-api/internal/invoice"
+	"kasir-api/internal/middleware"
+	"kasir-api/internal/migration"
+	"kasir-api/internal/notify"
+	"kasir-api/internal/receipt"
 	"kasir-api/internal/repository"
 	"kasir-api/internal/service"
 	"kasir-api/pkg/database"
@@ -30,6 +37,15 @@ import (
 
 // @BasePath /
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Type "Bearer" followed by a space and the token from /auth/login
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+
 func main() {
 	// Load Config
 	cfg, err := config.LoadConfig()
@@ -37,6 +53,22 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Business days in the store timezone, ending at the day cutoff
+	calendar, err := businessday.New(cfg.Store.Timezone, cfg.Store.DayCutoff)
+	if err != nil {
+		log.Fatalf("Invalid store timezone or day cutoff: %v", err)
+	}
+
+	invoices, err := invoice.New(cfg.Store.InvoiceFormat, cfg.Store.Code)
+	if err != nil {
+		log.Fatalf("Invalid invoice number format: %v", err)
+	}
+
+	receiptRenderer, err := receipt.New(cfg.Receipt.Header, cfg.Receipt.Footer, cfg.Receipt.Width)
+	if err != nil {
+		log.Fatalf("Invalid receipt template or width: %v", err)
+	}
+
 	// Initialize Database
 	dbCfg := database.Config{
 		Host:     cfg.Database.Host,
@@ -51,26 +83,105 @@ func main() {
 	}
 	defer db.Close()
 
-	// Run Migrations (for simplicity, running it here)
-	if err := runMigrations(db); err != nil {
+	// Apply pending schema migrations; refuses to start on a dirty schema
+	migrator, err := migration.New(db)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	applied, err := migrator.Up()
+	if err != nil {
 		log.Fatalf("Migration failed: %v", err)
 	}
+	if applied > 0 {
+		fmt.Printf("Applied %d migration(s)\n", applied)
+	}
 
 	// Dependency Injection Wiring
 	// Repositories
 	categoryRepo := repository.NewCategoryRepository(db)
 	productRepo := repository.NewProductRepository(db)
-	transactionRepo := repository.NewTransactionRepository(db)
+	transactionRepo := repository.NewTransactionRepository(db, calendar, invoices)
+	userRepo := repository.NewUserRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	apiClientRepo := repository.NewAPIClientRepository(db)
+	shiftRepo := repository.NewShiftRepository(db)
+	taxRateRepo := repository.NewTaxRateRepository(db)
+	promotionRepo := repository.NewPromotionRepository(db)
+	voucherRepo := repository.NewVoucherRepository(db)
+	customerRepo := repository.NewCustomerRepository(db)
+	loyaltyRuleRepo := repository.NewLoyaltyRuleRepository(db)
+	stockMovementRepo := repository.NewStockMovementRepository(db, calendar)
+	supplierRepo := repository.NewSupplierRepository(db)
+	purchaseOrderRepo := repository.NewPurchaseOrderRepository(db)
+	stockTakeRepo := repository.NewStockTakeRepository(db)
+	dayReportRepo := repository.NewDayReportRepository(db, calendar)
+	receiptRepo := repository.NewReceiptRepository(db)
 
 	// Services
-	categoryService := service.NewCategoryService(categoryRepo)
-	productService := service.NewProductService(productRepo, categoryRepo)
-	transactionService := service.NewTransactionService(transactionRepo)
+	categoryService := service.NewCategoryService(categoryRepo, taxRateRepo)
+	productService := service.NewProductService(productRepo, categoryRepo, taxRateRepo)
+	var lowStockNotifier notify.Notifier = notify.NewLogNotifier()
+	if cfg.Alerts.LowStockWebhookURL != "" {
+		lowStockNotifier = notify.NewWebhookNotifier(cfg.Alerts.LowStockWebhookURL)
+	}
+	lowStockChecker := service.NewLowStockChecker(stockMovementRepo, lowStockNotifier)
+	lowStockChecker.Start()
+	transactionService := service.NewTransactionService(transactionRepo, lowStockChecker, calendar)
+	userService := service.NewUserService(userRepo, roleRepo)
+	roleService := service.NewRoleService(roleRepo)
+	apiClientService := service.NewAPIClientService(apiClientRepo, roleRepo)
+	shiftService := service.NewShiftService(shiftRepo)
+	taxRateService := service.NewTaxRateService(taxRateRepo)
+	promotionService := service.NewPromotionService(promotionRepo)
+	voucherService := service.NewVoucherService(voucherRepo)
+	customerService := service.NewCustomerService(customerRepo)
+	loyaltyRuleService := service.NewLoyaltyRuleService(loyaltyRuleRepo, categoryRepo)
+	inventoryService := service.NewInventoryService(stockMovementRepo, productRepo)
+	supplierService := service.NewSupplierService(supplierRepo)
+	purchaseOrderService := service.NewPurchaseOrderService(purchaseOrderRepo)
+	stockTakeService := service.NewStockTakeService(stockTakeRepo)
+	dayReportService := service.NewDayReportService(dayReportRepo)
+	receiptService := service.NewReceiptService(transactionRepo, receiptRepo, receiptRenderer, calendar)
+	authService := service.NewAuthService(userRepo, roleRepo, apiClientRepo, auth.NewTokenManager(cfg.Auth.Secret, cfg.Auth.TokenTTL))
+
+	created, err := userService.EnsureAdmin(cfg.Auth.AdminUsername, cfg.Auth.AdminPassword)
+	if err != nil {
+		log.Fatalf("Failed to create initial admin: %v", err)
+	}
+	if created {
+		fmt.Printf("Created initial admin account %q\n", cfg.Auth.AdminUsername)
+	}
 
 	// Handlers
 	categoryHandler := handler.NewCategoryHandler(categoryService)
-	productHandler := handler.NewProductHandler(productService)
-	transactionHandler := handler.NewTransactionHandler(transactionService)
+	productHandler := handler.NewProductHandler(productService, inventoryService)
+	transactionHandler := handler.NewTransactionHandler(transactionService, receiptService)
+	authHandler := handler.NewAuthHandler(authService)
+	userHandler := handler.NewUserHandler(userService)
+	roleHandler := handler.NewRoleHandler(roleService)
+	apiClientHandler := handler.NewAPIClientHandler(apiClientService)
+	shiftHandler := handler.NewShiftHandler(shiftService)
+	taxRateHandler := handler.NewTaxRateHandler(taxRateService)
+	promotionHandler := handler.NewPromotionHandler(promotionService)
+	voucherHandler := handler.NewVoucherHandler(voucherService)
+	customerHandler := handler.NewCustomerHandler(customerService, transactionService)
+	loyaltyRuleHandler := handler.NewLoyaltyRuleHandler(loyaltyRuleService)
+	supplierHandler := handler.NewSupplierHandler(supplierService)
+	purchaseOrderHandler := handler.NewPurchaseOrderHandler(purchaseOrderService)
+	stockTakeHandler := handler.NewStockTakeHandler(stockTakeService)
+	dayReportHandler := handler.NewDayReportHandler(dayReportService)
+
+	// Middleware
+	authenticated := middleware.RequireAuth(authService)
+	protected := func(h http.HandlerFunc) http.Handler {
+		return authenticated(h)
+	}
+	authorized := func(perms middleware.Permissions, h http.HandlerFunc) http.Handler {
+		return authenticated(middleware.RequirePermissions(perms)(h))
+	}
+	readWrite := func(read, write string) middleware.Permissions {
+		return middleware.Permissions{http.MethodGet: read, "*": write}
+	}
 
 	// Route Registration
 	mux := http.NewServeMux()
@@ -78,20 +189,74 @@ func main() {
 	// Swagger UI
 	mux.HandleFunc("/swagger/", httpSwagger.WrapHandler)
 
+	// Auth
+	mux.HandleFunc("/auth/login", authHandler.HandleLogin)
+	mux.Handle("/auth/me", protected(authHandler.HandleMe))
+
+	// Users, roles and API clients
+	mux.Handle("/users", authorized(middleware.Permissions{"*": auth.PermUsersManage}, userHandler.HandleUsers))
+	mux.Handle("/users/", authorized(middleware.Permissions{"*": auth.PermUsersManage}, userHandler.HandleUserByID))
+	mux.Handle("/api-clients", authorized(middleware.Permissions{"*": auth.PermUsersManage}, apiClientHandler.HandleAPIClients))
+	mux.Handle("/api-clients/", authorized(middleware.Permissions{"*": auth.PermUsersManage}, apiClientHandler.HandleAPIClientByID))
+	mux.Handle("/roles", authorized(middleware.Permissions{"*": auth.PermRolesManage}, roleHandler.HandleRoles))
+	mux.Handle("/roles/", authorized(middleware.Permissions{"*": auth.PermRolesManage}, roleHandler.HandleRoleByName))
+
 	// Categories
-	mux.HandleFunc("/categories", categoryHandler.HandleCategories)
-	mux.HandleFunc("/categories/", categoryHandler.HandleCategoryByID)
+	mux.Handle("/categories", authorized(readWrite(auth.PermCategoriesRead, auth.PermCategoriesWrite), categoryHandler.HandleCategories))
+	mux.Handle("/categories/", authorized(readWrite(auth.PermCategoriesRead, auth.PermCategoriesWrite), categoryHandler.HandleCategoryByID))
 
 	// Products
-	mux.HandleFunc("/products", productHandler.HandleProducts)
-	mux.HandleFunc("/products/", productHandler.HandleProductByID)
+	mux.Handle("/products/low-stock", authorized(middleware.Permissions{"*": auth.PermProductsRead}, productHandler.HandleLowStock))
+	mux.Handle("/products", authorized(readWrite(auth.PermProductsRead, auth.PermProductsWrite), productHandler.HandleProducts))
+	mux.Handle("/products/", authorized(readWrite(auth.PermProductsRead, auth.PermProductsWrite), productHandler.HandleProductByID))
+
+	// Suppliers and purchasing
+	mux.Handle("/suppliers", authorized(middleware.Permissions{"*": auth.PermPurchasingManage}, supplierHandler.HandleSuppliers))
+	mux.Handle("/suppliers/", authorized(middleware.Permissions{"*": auth.PermPurchasingManage}, supplierHandler.HandleSupplierByID))
+	mux.Handle("/purchase-orders", authorized(middleware.Permissions{"*": auth.PermPurchasingManage}, purchaseOrderHandler.HandlePurchaseOrders))
+	mux.Handle("/purchase-orders/", authorized(middleware.Permissions{"*": auth.PermPurchasingManage}, purchaseOrderHandler.HandlePurchaseOrderByID))
+
+	// Stock takes
+	mux.Handle("/stock-takes", authorized(middleware.Permissions{"*": auth.PermStockTakesManage}, stockTakeHandler.HandleStockTakes))
+	mux.Handle("/stock-takes/", authorized(middleware.Permissions{"*": auth.PermStockTakesManage}, stockTakeHandler.HandleStockTakeByID))
+
+	// Tax rates
+	mux.Handle("/tax-rates", authorized(readWrite(auth.PermProductsRead, auth.PermTaxesManage), taxRateHandler.HandleTaxRates))
+	mux.Handle("/tax-rates/", authorized(readWrite(auth.PermProductsRead, auth.PermTaxesManage), taxRateHandler.HandleTaxRateByID))
+
+	// Promotions
+	mux.Handle("/promotions", authorized(readWrite(auth.PermProductsRead, auth.PermPromotionsManage), promotionHandler.HandlePromotions))
+	mux.Handle("/promotions/", authorized(readWrite(auth.PermProductsRead, auth.PermPromotionsManage), promotionHandler.HandlePromotionByID))
+
+	// Vouchers
+	mux.Handle("/vouchers", authorized(middleware.Permissions{"*": auth.PermVouchersManage}, voucherHandler.HandleVouchers))
+	mux.Handle("/vouchers/", authorized(middleware.Permissions{"*": auth.PermVouchersManage}, voucherHandler.HandleVoucherByID))
+
+	// Customers and loyalty
+	mux.Handle("/customers", authorized(middleware.Permissions{"*": auth.PermCustomersManage}, customerHandler.HandleCustomers))
+	mux.Handle("/customers/", authorized(middleware.Permissions{"*": auth.PermCustomersManage}, customerHandler.HandleCustomerByID))
+	mux.Handle("/loyalty-rules", authorized(readWrite(auth.PermCustomersManage, auth.PermLoyaltyManage), loyaltyRuleHandler.HandleLoyaltyRules))
+	mux.Handle("/loyalty-rules/", authorized(readWrite(auth.PermCustomersManage, auth.PermLoyaltyManage), loyaltyRuleHandler.HandleLoyaltyRuleByID))
 
 	// Transactions
-	mux.HandleFunc("/checkout", transactionHandler.HandleCheckout)
+	mux.Handle("/checkout", authorized(middleware.Permissions{"*": auth.PermCheckout}, transactionHandler.HandleCheckout))
+	mux.Handle("/transactions", authorized(middleware.Permissions{"*": auth.PermTransactionsRead}, transactionHandler.HandleTransactions))
+	mux.Handle("/transactions/", authorized(readWrite(auth.PermTransactionsRead, auth.PermTransactionsRefund), transactionHandler.HandleTransactionByID))
+	// Printing a receipt is part of ringing up a sale, not of refunding one
+	mux.Handle("/transactions/{id}/receipt", authorized(middleware.Permissions{http.MethodPost: auth.PermCheckout}, transactionHandler.HandleTransactionByID))
+
+	// Shifts
+	mux.Handle("/shifts", authorized(middleware.Permissions{"*": auth.PermShiftsManage}, shiftHandler.HandleShifts))
+	mux.Handle("/shifts/", authorized(middleware.Permissions{"*": auth.PermShiftsManage}, shiftHandler.HandleShiftByID))
 
 	// Reports
-	mux.HandleFunc("/report", transactionHandler.HandleReport)
-	mux.HandleFunc("/report/", transactionHandler.HandleReport)
+	mux.Handle("/report", authorized(middleware.Permissions{"*": auth.PermReportsRead}, transactionHandler.HandleReport))
+	mux.Handle("/report/", authorized(middleware.Permissions{"*": auth.PermReportsRead}, transactionHandler.HandleReport))
+
+	// End of day
+	mux.Handle("/x-report", authorized(middleware.Permissions{"*": auth.PermReportsRead}, dayReportHandler.HandleXReport))
+	mux.Handle("/z-reports", authorized(readWrite(auth.PermReportsRead, auth.PermReportsClose), dayReportHandler.HandleZReports))
+	mux.Handle("/z-reports/", authorized(middleware.Permissions{"*": auth.PermReportsRead}, dayReportHandler.HandleZReportByNumber))
 
 	port := cfg.Server.Port
 	fmt.Printf("Server starting on port %s...\n", port)
@@ -105,65 +270,13 @@ func main() {
 	}
 }
 
-func runMigrations(db *sql.DB) error {
-	createCategoriesTable := `
-	CREATE TABLE IF NOT EXISTS categories (
-		id SERIAL PRIMARY KEY,
-		name TEXT NOT NULL,
-		description TEXT
-	);`
-
-	createProductsTable := `
-	CREATE TABLE IF NOT EXISTS products (
-		id SERIAL PRIMARY KEY,
-		name TEXT NOT NULL,
-		price INT NOT NULL,
-		stock INT NOT NULL,
-		category_id INT REFERENCES categories(id)
-	);`
-
-	createTransactionsTable := `
-	CREATE TABLE IF NOT EXISTS transactions (
-		id SERIAL PRIMARY KEY,
-		total_amount INT NOT NULL,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);`
-
-	createTransactionDetailsTable := `
-	CREATE TABLE IF NOT EXISTS transaction_details (
-		id SERIAL PRIMARY KEY,
-		transaction_id INT REFERENCES transactions(id) ON DELETE CASCADE,
-		product_id INT REFERENCES products(id),
-		quantity INT NOT NULL,
-		subtotal INT NOT NULL
-	);`
-
-	if _, err := db.Exec(createCategoriesTable); err != nil {
-		return fmt.Errorf("error creating categories table: %w", err)
-	}
-
-	if _, err := db.Exec(createProductsTable); err != nil {
-		return fmt.Errorf("error creating products table: %w", err)
-	}
-
-	if _, err := db.Exec(createTransactionsTable); err != nil {
-		return fmt.Errorf("error creating transactions table: %w", err)
-	}
-
-	if _, err := db.Exec(createTransactionDetailsTable); err != nil {
-		return fmt.Errorf("error creating transaction_details table: %w", err)
-	}
-
-	return nil
-}
-
 // enableCORS adds CORS headers to allow frontend access
 func enableCORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Set CORS headers
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
 		w.Header().Set("Access-Control-Max-Age", "86400")
 
 		// Handle preflight OPTIONS request
@@ -18,14 +18,33 @@ type Product struct {
 	Stock int `json:"stock"`
 }
 
+// authToken is sent as a bearer token on every request once logged in;
+// apiKey, when set, is sent in the X-API-Key header instead
+var authToken, apiKey string
+
 func main() {
 	baseURL := flag.String("url", "http://localhost:8080", "Base URL of the API")
 	totalRequests := flag.Int("n", 1000, "Total number of requests per endpoint")
 	concurrency := flag.Int("c", 100, "Number of concurrent workers")
 	debug := flag.Bool("debug", false, "Enable verbose error logging")
 	target := flag.String("target", "", "Filter endpoints by name (case-insensitive substring)")
+	token := flag.String("token", "", "Bearer token to authenticate with")
+	key := flag.String("key", "", "API key to authenticate with (sent as X-API-Key)")
+	username := flag.String("user", "admin", "Username to log in with when no token is given")
+	password := flag.String("password", "", "Password to log in with when no token is given")
 	flag.Parse()
 
+	authToken, apiKey = *token, *key
+	if authToken == "" && apiKey == "" && *password != "" {
+		var err error
+		authToken, err = login(*baseURL, *username, *password)
+		if err != nil {
+			fmt.Printf("Error logging in: %v\n", err)
+			return
+		}
+		fmt.Printf("Logged in as %s\n", *username)
+	}
+
 	// 1. Fetch valid IDs dynamically
 	products, err := fetchProducts(*baseURL + "/products")
 	if err != nil {
@@ -86,8 +105,55 @@ func main() {
 	}
 }
 
+func login(baseURL, username, password string) (string, error) {
+	jsonBody, err := json.Marshal(map[string]string{"username": username, "password": password})
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.Post(baseURL+"/auth/login", "application/json", bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("bad status: %s", resp.Status)
+	}
+
+	var result struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	return result.Token, nil
+}
+
+// newRequest builds a request carrying the configured credentials, if any
+func newRequest(method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	} else if authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+authToken)
+	}
+	return req, nil
+}
+
+func get(url string) (*http.Response, error) {
+	req, err := newRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return http.DefaultClient.Do(req)
+}
+
 func fetchProducts(url string) ([]Product, error) {
-	resp, err := http.Get(url)
+	resp, err := get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -111,7 +177,7 @@ func fetchProducts(url string) ([]Product, error) {
 }
 
 func fetchIDs(url string) ([]int, error) {
-	resp, err := http.Get(url)
+	resp, err := get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -197,7 +263,7 @@ func runTest(baseURL, name, pathTmpl, method string, isDetail bool, ids []int, t
 					url = fmt.Sprintf(baseURL+pathTmpl, keyword)
 				}
 
-				req, err := http.NewRequest(method, url, nil)
+				req, err := newRequest(method, url, nil)
 				if err != nil {
 					localFail++
 					continue
@@ -357,7 +423,7 @@ func runPostTest(baseURL, name, path string, ids []int, totalReqs, concurrency i
 				}
 
 				url := baseURL + path
-				req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonBody))
+				req, err := newRequest("POST", url, bytes.NewBuffer(jsonBody))
 				if err != nil {
 					localFail++
 					continue
@@ -428,8 +494,8 @@ func restockProducts(baseURL string) ([]Product, error) {
 			continue
 		}
 
-		url := fmt.Sprintf("%s/products/%d", baseURL, product.ID)
-		req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonBody))
+		url := fmt.Sprintf("%s/products/%d?set_stock=true", baseURL, product.ID)
+		req, err := newRequest("PUT", url, bytes.NewBuffer(jsonBody))
 		if err != nil {
 			continue
 		}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"kasir-api/internal/config"
+	"kasir-api/internal/migration"
+	"kasir-api/pkg/database"
+)
+
+const usage = `Usage: migrate <command> [args]
+
+Commands:
+  up            Apply all pending migrations
+  down [N]      Roll back the last N migrations (default 1)
+  status        List migrations and whether they are applied
+  force VERSION Mark VERSION as applied and clean after a manual fix;
+                0 clears a failed first migration
+`
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.NewPostgres(database.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		User:     cfg.Database.User,
+		Password: cfg.Database.Password,
+		Name:     cfg.Database.Name,
+	})
+	if err != nil {
+		log.Fatalf("Could not connect to database: %v", err)
+	}
+	defer db.Close()
+
+	migrator, err := migration.New(db)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	switch cmd := flag.Arg(0); cmd {
+	case "up":
+		n, err := migrator.Up()
+		if err != nil {
+			log.Fatalf("migrate up: %v", err)
+		}
+		fmt.Printf("Applied %d migration(s)\n", n)
+	case "down":
+		steps := 1
+		if flag.NArg() > 1 {
+			steps, err = strconv.Atoi(flag.Arg(1))
+			if err != nil || steps < 1 {
+				log.Fatalf("invalid number of steps %q", flag.Arg(1))
+			}
+		}
+		n, err := migrator.Down(steps)
+		if err != nil {
+			log.Fatalf("migrate down: %v", err)
+		}
+		fmt.Printf("Rolled back %d migration(s)\n", n)
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			log.Fatalf("migrate status: %v", err)
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Dirty {
+				state = "DIRTY"
+			} else if s.Applied {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%06d  %-45s %s\n", s.Version, s.Name, state)
+		}
+	case "force":
+		if flag.NArg() < 2 {
+			log.Fatal("force requires a version")
+		}
+		version, err := strconv.ParseInt(flag.Arg(1), 10, 64)
+		if err != nil {
+			log.Fatalf("invalid version %q", flag.Arg(1))
+		}
+		if err := migrator.Force(version); err != nil {
+			log.Fatalf("migrate force: %v", err)
+		}
+		fmt.Printf("Forced version %d\n", version)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", cmd)
+		flag.Usage()
+		os.Exit(2)
+	}
+}
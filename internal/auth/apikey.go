@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const apiKeyPrefix = "kasir_"
+
+// GenerateAPIKey returns a new random API key together with the short prefix
+// shown in listings and the hash that is stored instead of the key itself.
+func GenerateAPIKey() (key, displayPrefix, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", err
+	}
+	key = apiKeyPrefix + hex.EncodeToString(buf)
+	return key, key[:len(apiKeyPrefix)+8], HashAPIKey(key), nil
+}
+
+// HashAPIKey hashes an API key for lookup. Keys carry 256 bits of entropy, so
+// a fast hash is enough and keeps per-request verification cheap.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
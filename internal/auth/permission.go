@@ -0,0 +1,57 @@
+package auth
+
+// Permissions checked by the HTTP layer. Roles are granted any subset of these
+// through the role_permissions table.
+const (
+	PermCategoriesRead     = "categories:read"
+	PermCategoriesWrite    = "categories:write"
+	PermProductsRead       = "products:read"
+	PermProductsWrite      = "products:write"
+	PermCheckout           = "checkout"
+	PermTransactionsRead   = "transactions:read"
+	PermTransactionsRefund = "transactions:refund"
+	PermReportsRead        = "reports:read"
+	PermReportsClose       = "reports:close"
+	PermShiftsManage       = "shifts:manage"
+	PermTaxesManage        = "taxes:manage"
+	PermPromotionsManage   = "promotions:manage"
+	PermVouchersManage     = "vouchers:manage"
+	PermCustomersManage    = "customers:manage"
+	PermLoyaltyManage      = "loyalty:manage"
+	PermPurchasingManage   = "purchasing:manage"
+	PermStockTakesManage   = "stocktakes:manage"
+	PermUsersManage        = "users:manage"
+	PermRolesManage        = "roles:manage"
+)
+
+// AllPermissions lists every permission a role can be granted.
+var AllPermissions = []string{
+	PermCategoriesRead,
+	PermCategoriesWrite,
+	PermProductsRead,
+	PermProductsWrite,
+	PermCheckout,
+	PermTransactionsRead,
+	PermTransactionsRefund,
+	PermReportsRead,
+	PermReportsClose,
+	PermShiftsManage,
+	PermTaxesManage,
+	PermPromotionsManage,
+	PermVouchersManage,
+	PermCustomersManage,
+	PermLoyaltyManage,
+	PermPurchasingManage,
+	PermStockTakesManage,
+	PermUsersManage,
+	PermRolesManage,
+}
+
+func IsKnownPermission(permission string) bool {
+	for _, p := range AllPermissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
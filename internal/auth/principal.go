@@ -0,0 +1,41 @@
+package auth
+
+import "context"
+
+const (
+	PrincipalUser      = "user"
+	PrincipalAPIClient = "api_client"
+)
+
+// Principal is the authenticated caller of a request: either a user holding a
+// bearer token or an API client presenting an API key.
+type Principal struct {
+	Type        string   `json:"type"`
+	UserID      int      `json:"user_id,omitempty"`
+	APIClientID int      `json:"api_client_id,omitempty"`
+	Username    string   `json:"username"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+	TerminalID  string   `json:"terminal_id,omitempty"`
+}
+
+func (p *Principal) Can(permission string) bool {
+	for _, granted := range p.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+type contextKey struct{}
+
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// PrincipalFromContext returns the principal stored by the auth middleware, or nil.
+func PrincipalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(contextKey{}).(*Principal)
+	return p
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidToken  = errors.New("invalid or expired token")
+	ErrInvalidAPIKey = errors.New("invalid or revoked API key")
+)
+
+type Claims struct {
+	Username   string `json:"username"`
+	Role       string `json:"role"`
+	TerminalID string `json:"terminal_id,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// TokenManager issues and verifies HMAC-signed (HS256) access tokens.
+type TokenManager struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewTokenManager(secret string, ttl time.Duration) *TokenManager {
+	return &TokenManager{secret: []byte(secret), ttl: ttl}
+}
+
+// Issue signs a token for a user. terminalID names the till the user logged in
+// at and may be empty.
+func (m *TokenManager) Issue(userID int, username, role, terminalID string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(m.ttl)
+	claims := Claims{
+		Username:   username,
+		Role:       role,
+		TerminalID: terminalID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.Itoa(userID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// Parse verifies the signature and expiry of a token and returns the principal it was issued to.
+func (m *TokenManager) Parse(tokenString string) (*Principal, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(t *jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	return &Principal{Type: PrincipalUser, UserID: userID, Username: claims.Username, Role: claims.Role, TerminalID: claims.TerminalID}, nil
+}
@@ -0,0 +1,50 @@
+package auth_test
+
+import (
+	"kasir-api/internal/auth"
+	"testing"
+	"time"
+)
+
+func TestIssueAndParse(t *testing.T) {
+	tokens := auth.NewTokenManager("test-secret", time.Hour)
+
+	token, expiresAt, err := tokens.Issue(42, "budi", "cashier", "KASIR-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Until(expiresAt) <= 0 {
+		t.Errorf("expected expiry in the future, got %v", expiresAt)
+	}
+
+	p, err := tokens.Parse(token)
+	if err != nil {
+		t.Fatalf("failed to parse issued token: %v", err)
+	}
+	if p.UserID != 42 || p.Username != "budi" || p.Role != "cashier" || p.TerminalID != "KASIR-01" {
+		t.Errorf("unexpected principal: %+v", p)
+	}
+}
+
+func TestParseRejectsForeignSignature(t *testing.T) {
+	token, _, err := auth.NewTokenManager("other-secret", time.Hour).Issue(1, "admin", "admin", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := auth.NewTokenManager("test-secret", time.Hour).Parse(token); err == nil {
+		t.Error("expected token signed with another secret to be rejected")
+	}
+}
+
+func TestParseRejectsExpiredToken(t *testing.T) {
+	tokens := auth.NewTokenManager("test-secret", -time.Minute)
+	token, _, err := tokens.Issue(1, "admin", "admin", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tokens.Parse(token); err == nil {
+		t.Error("expected expired token to be rejected")
+	}
+}
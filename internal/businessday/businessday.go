@@ -0,0 +1,94 @@
+// Package businessday maps instants to the store's business days. A business
+// day runs from the cutoff time on its date to the cutoff time on the next,
+// in the store's timezone, so a late-night outlet closing at 03:00 can book
+// its after-midnight sales on the day they belong to.
+package businessday
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const dateLayout = "2006-01-02"
+
+type Calendar struct {
+	location *time.Location
+	cutoff   time.Duration
+}
+
+// New returns the calendar of a store in the IANA timezone tz (e.g.
+// Asia/Jakarta) whose day ends cutoff after midnight.
+func New(tz string, cutoff time.Duration) (*Calendar, error) {
+	if tz == "" || tz == "Local" {
+		return nil, errors.New("timezone must be an IANA name such as Asia/Jakarta")
+	}
+	location, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, err
+	}
+	if cutoff < 0 || cutoff >= 24*time.Hour {
+		return nil, fmt.Errorf("day cutoff must be between 00:00 and 23:59, got %s", cutoff)
+	}
+	return &Calendar{location: location, cutoff: cutoff}, nil
+}
+
+func (c *Calendar) Location() *time.Location {
+	return c.location
+}
+
+// DateOf returns the business date (YYYY-MM-DD) t falls in.
+func (c *Calendar) DateOf(t time.Time) string {
+	return t.In(c.location).Add(-c.cutoff).Format(dateLayout)
+}
+
+// Today returns the current business date.
+func (c *Calendar) Today() string {
+	return c.DateOf(time.Now())
+}
+
+// Start returns the instant the business day date (YYYY-MM-DD) begins.
+func (c *Calendar) Start(date string) (time.Time, error) {
+	d, err := time.ParseInLocation(dateLayout, date, c.location)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return d.Add(c.cutoff), nil
+}
+
+// End returns the instant the business day date ends, which is when the
+// next one begins.
+func (c *Calendar) End(date string) (time.Time, error) {
+	start, err := c.Start(date)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return start.AddDate(0, 0, 1), nil
+}
+
+// Bounds returns the half-open range [from, to) of instants covering the
+// business days start through end.
+func (c *Calendar) Bounds(start, end string) (from, to time.Time, err error) {
+	if from, err = c.Start(start); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if to, err = c.End(end); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return from, to, nil
+}
+
+// SQLDate returns a SQL expression for the business date of the timestamptz
+// expression col.
+func (c *Calendar) SQLDate(col string) string {
+	return fmt.Sprintf("DATE((%s AT TIME ZONE %s) - INTERVAL '%d minutes')",
+		col, pq.QuoteLiteral(c.location.String()), int(c.cutoff/time.Minute))
+}
+
+// SQLLocalTime returns a SQL expression for the timestamptz expression col as
+// wall-clock time in the store's timezone.
+func (c *Calendar) SQLLocalTime(col string) string {
+	return fmt.Sprintf("(%s AT TIME ZONE %s)", col, pq.QuoteLiteral(c.location.String()))
+}
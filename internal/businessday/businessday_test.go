@@ -0,0 +1,84 @@
+package businessday_test
+
+import (
+	"kasir-api/internal/businessday"
+	"testing"
+	"time"
+)
+
+func TestDateOfUsesTimezoneAndCutoff(t *testing.T) {
+	cal, err := businessday.New("Asia/Makassar", 4*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want string
+	}{
+		// 17:30 UTC is 01:30 WITA on the 2nd, still before the 04:00 cutoff
+		{"after midnight before cutoff", time.Date(2024, 3, 1, 17, 30, 0, 0, time.UTC), "2024-03-01"},
+		{"at cutoff", time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC), "2024-03-02"},
+		// 23:00 UTC on the 1st is already 07:00 WITA on the 2nd
+		{"utc evening", time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC), "2024-03-02"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cal.DateOf(tt.at); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBounds(t *testing.T) {
+	cal, err := businessday.New("Asia/Jakarta", 4*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from, to, err := cal.Bounds("2024-03-01", "2024-03-02")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 04:00 WIB is 21:00 UTC the day before
+	if want := time.Date(2024, 2, 29, 21, 0, 0, 0, time.UTC); !from.Equal(want) {
+		t.Errorf("expected from %s, got %s", want, from.UTC())
+	}
+	if want := time.Date(2024, 3, 2, 21, 0, 0, 0, time.UTC); !to.Equal(want) {
+		t.Errorf("expected to %s, got %s", want, to.UTC())
+	}
+
+	if _, _, err := cal.Bounds("2024-03-01", "tomorrow"); err == nil {
+		t.Error("expected an error for an invalid date")
+	}
+}
+
+func TestNewRejectsInvalidSettings(t *testing.T) {
+	for _, tc := range []struct {
+		tz     string
+		cutoff time.Duration
+	}{
+		{"", 0},
+		{"Local", 0},
+		{"Asia/Nowhere", 0},
+		{"Asia/Jakarta", 24 * time.Hour},
+		{"Asia/Jakarta", -time.Hour},
+	} {
+		if _, err := businessday.New(tc.tz, tc.cutoff); err == nil {
+			t.Errorf("expected an error for %q with cutoff %s", tc.tz, tc.cutoff)
+		}
+	}
+}
+
+func TestSQLDate(t *testing.T) {
+	cal, err := businessday.New("Asia/Jayapura", 90*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "DATE((t.created_at AT TIME ZONE 'Asia/Jayapura') - INTERVAL '90 minutes')"
+	if got := cal.SQLDate("t.created_at"); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
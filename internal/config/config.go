@@ -2,8 +2,10 @@ package config
 
 import (
 	"fmt"
+	"kasir-api/internal/invoice"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -11,6 +13,10 @@ import (
 type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
+	Auth     AuthConfig
+	Alerts   AlertConfig
+	Store    StoreConfig
+	Receipt  ReceiptConfig
 }
 
 type ServerConfig struct {
@@ -25,6 +31,37 @@ type DatabaseConfig struct {
 	Name     string
 }
 
+// StoreConfig places the store's business day: reports resolve dates in
+// Timezone, and a day ends DayCutoff after midnight. Code identifies the
+// store in invoice numbers, which follow InvoiceFormat.
+type StoreConfig struct {
+	Timezone      string
+	DayCutoff     time.Duration
+	Code          string
+	InvoiceFormat string
+}
+
+// ReceiptConfig holds the text/template header and footer printed on every
+// receipt and the default line width in characters.
+type ReceiptConfig struct {
+	Header string
+	Footer string
+	Width  int
+}
+
+// AlertConfig says where low-stock alerts go; they are logged when no
+// webhook URL is set.
+type AlertConfig struct {
+	LowStockWebhookURL string
+}
+
+type AuthConfig struct {
+	Secret        string
+	TokenTTL      time.Duration
+	AdminUsername string
+	AdminPassword string
+}
+
 func LoadConfig() (*Config, error) {
 	// Enable automatic environment variable reading
 	viper.AutomaticEnv()
@@ -49,6 +86,25 @@ func LoadConfig() (*Config, error) {
 			Password: viper.GetString("DB_PASSWORD"),
 			Name:     viper.GetString("DB_NAME"),
 		},
+		Auth: AuthConfig{
+			Secret:        viper.GetString("AUTH_SECRET"),
+			AdminUsername: viper.GetString("ADMIN_USERNAME"),
+			AdminPassword: viper.GetString("ADMIN_PASSWORD"),
+		},
+		Alerts: AlertConfig{
+			LowStockWebhookURL: viper.GetString("LOW_STOCK_WEBHOOK_URL"),
+		},
+		Store: StoreConfig{
+			Timezone:      viper.GetString("STORE_TIMEZONE"),
+			Code:          viper.GetString("STORE_CODE"),
+			InvoiceFormat: viper.GetString("INVOICE_FORMAT"),
+		},
+		Receipt: ReceiptConfig{
+			// A .env line cannot hold a newline, so \n separates lines
+			Header: strings.ReplaceAll(viper.GetString("RECEIPT_HEADER"), `\n`, "\n"),
+			Footer: strings.ReplaceAll(viper.GetString("RECEIPT_FOOTER"), `\n`, "\n"),
+			Width:  viper.GetInt("RECEIPT_WIDTH"),
+		},
 	}
 
 	// Set defaults
@@ -63,6 +119,39 @@ func LoadConfig() (*Config, error) {
 	if config.Database.Port == "" {
 		config.Database.Port = "5432" // Default PostgreSQL port
 	}
+	if config.Auth.Secret == "" {
+		return nil, fmt.Errorf("AUTH_SECRET is required")
+	}
+
+	config.Auth.TokenTTL = 12 * time.Hour // Default token lifetime (one shift)
+	if ttl := viper.GetString("AUTH_TOKEN_TTL"); ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AUTH_TOKEN_TTL: %w", err)
+		}
+		config.Auth.TokenTTL = d
+	}
+
+	if config.Store.Timezone == "" {
+		config.Store.Timezone = "Asia/Jakarta" // Default to WIB
+	}
+	if config.Store.Code == "" {
+		config.Store.Code = "01" // Default to a single store
+	}
+	if config.Store.InvoiceFormat == "" {
+		config.Store.InvoiceFormat = invoice.DefaultFormat
+	}
+	if cutoff := viper.GetString("BUSINESS_DAY_CUTOFF"); cutoff != "" {
+		t, err := time.Parse("15:04", cutoff)
+		if err != nil {
+			return nil, fmt.Errorf("invalid BUSINESS_DAY_CUTOFF, expected HH:MM: %w", err)
+		}
+		config.Store.DayCutoff = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+
+	if config.Receipt.Width == 0 {
+		config.Receipt.Width = 32 // Default to 58mm paper
+	}
 
 	return &config, nil
 }
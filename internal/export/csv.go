@@ -0,0 +1,73 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// csvWriter streams rows as they come. The first section starts with its
+// column headings; each later one is set off by a blank line and a line
+// holding its title.
+type csvWriter struct {
+	w        *csv.Writer
+	sections int
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) Section(title string, columns ...string) error {
+	if c.sections > 0 {
+		if err := c.w.Write(nil); err != nil {
+			return err
+		}
+		if err := c.w.Write([]string{title}); err != nil {
+			return err
+		}
+	}
+	c.sections++
+	return c.write(columns)
+}
+
+func (c *csvWriter) Row(values ...interface{}) error {
+	if c.sections == 0 {
+		return errNoSection
+	}
+	record := make([]string, len(values))
+	for i, v := range values {
+		if text, ok := v.(string); ok {
+			record[i] = csvText(text)
+			continue
+		}
+		record[i] = formatValue(v)
+	}
+	return c.write(record)
+}
+
+// csvText keeps text a spreadsheet would read as a formula, such as a
+// product named =HYPERLINK(...), as plain text by prefixing a quote. A leading
+// tab or carriage return counts too, as spreadsheets skip it before reading
+// the formula. Numbers are not text and keep their sign.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// write sends one record on its way instead of letting rows pile up in the
+// csv package's buffer.
+func (c *csvWriter) write(record []string) error {
+	if err := c.w.Write(record); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
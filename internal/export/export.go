@@ -0,0 +1,118 @@
+// Package export renders tabular data as CSV, XLSX or PDF for people who
+// work in spreadsheets rather than JSON. A document is a run of sections,
+// each a table with a title and column headings, written row by row so
+// large listings never have to be held in memory as a whole.
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+	FormatPDF  = "pdf"
+)
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+var contentTypes = map[string]string{
+	FormatJSON: "application/json",
+	FormatCSV:  "text/csv",
+	FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	FormatPDF:  "application/pdf",
+}
+
+// ContentType returns the MIME type of format.
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// Negotiate picks the response format. An explicit format (the format query
+// parameter) wins and must be one of json, csv, xlsx or pdf; otherwise the
+// most preferred known type in the Accept header is used, falling back to
+// JSON.
+func Negotiate(format, accept string) (string, error) {
+	if format != "" {
+		format = strings.ToLower(format)
+		if _, ok := contentTypes[format]; !ok {
+			return "", fmt.Errorf("%w %q: must be json, csv, xlsx or pdf", ErrUnknownFormat, format)
+		}
+		return format, nil
+	}
+
+	best, bestQ := FormatJSON, 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		for f, ct := range contentTypes {
+			if ct == mediaType && q > bestQ {
+				best, bestQ = f, q
+			}
+		}
+	}
+	return best, nil
+}
+
+// Writer writes one document. Values passed to Row may be strings, ints,
+// float64s, bools or time.Times; times are written in their own location.
+type Writer interface {
+	// Section starts a new table; the rows that follow belong to it.
+	Section(title string, columns ...string) error
+	Row(values ...interface{}) error
+	// Close completes the document. XLSX and PDF documents are only written
+	// to the underlying writer here.
+	Close() error
+}
+
+// NewWriter returns a Writer for format, titled title where the format has
+// room for one.
+func NewWriter(format string, w io.Writer, title string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w, title), nil
+	case FormatPDF:
+		return newPDFWriter(w, title), nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+}
+
+var errNoSection = errors.New("export: row written before any section")
+
+const timeLayout = "2006-01-02 15:04:05"
+
+// formatValue renders a cell as plain text, for formats without cell types.
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(timeLayout)
+	}
+	return fmt.Sprint(v)
+}
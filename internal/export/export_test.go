@@ -0,0 +1,178 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestNegotiate(t *testing.T) {
+	xlsx := "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	tests := []struct {
+		name, format, accept, want string
+	}{
+		{"default", "", "", FormatJSON},
+		{"browser", "", "text/html,application/xhtml+xml,*/*;q=0.8", FormatJSON},
+		{"accept csv", "", "text/csv", FormatCSV},
+		{"accept xlsx", "", xlsx, FormatXLSX},
+		{"preferred by q", "", "application/pdf;q=0.5, " + xlsx + ";q=0.9", FormatXLSX},
+		{"format wins", "PDF", "text/csv", FormatPDF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Negotiate(tt.format, tt.accept)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := Negotiate("docx", ""); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("expected ErrUnknownFormat, got %v", err)
+	}
+}
+
+func writeSample(t *testing.T, format string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf, "Laporan Penjualan")
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	at := time.Date(2026, 3, 1, 21, 30, 0, 0, time.FixedZone("WIB", 7*3600))
+	steps := []error{
+		w.Section("Ringkasan", "Metrik", "Nilai"),
+		w.Row("Total Revenue", 1250000),
+		w.Row("Item per Transaksi", 2.5),
+		w.Section("Transaksi", "ID", "Waktu", "Kasir"),
+		w.Row(7, at, "Siti, \"Ani\""),
+		w.Close(),
+	}
+	for _, err := range steps {
+		if err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestCSVWriter(t *testing.T) {
+	got := string(writeSample(t, FormatCSV))
+	want := "Metrik,Nilai\n" +
+		"Total Revenue,1250000\n" +
+		"Item per Transaksi,2.5\n" +
+		"\n" +
+		"Transaksi\n" +
+		"ID,Waktu,Kasir\n" +
+		"7,2026-03-01 21:30:00,\"Siti, \"\"Ani\"\"\"\n"
+	if got != want {
+		t.Errorf("unexpected CSV:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCSVWriterNeutralisesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatCSV, &buf, "Produk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Section("Produk", "Nama", "Catatan", "Stok", "Selisih"); err != nil {
+		t.Fatal(err)
+	}
+	rows := [][]interface{}{
+		{"=HYPERLINK(\"http://x\")", "+62 812", -3, -1.5},
+		{"-diskon", "@SUM(A1)", 0, 2.5},
+		{"\t=1+1", "\r@A1", 7, 1.0},
+		{"Kopi = enak", "", 4, 0.0},
+	}
+	for _, row := range rows {
+		if err := w.Row(row...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Nama,Catatan,Stok,Selisih\n" +
+		"\"'=HYPERLINK(\"\"http://x\"\")\",'+62 812,-3,-1.5\n" +
+		"'-diskon,'@SUM(A1),0,2.5\n" +
+		"'\t=1+1,\"'\r@A1\",7,1\n" +
+		"Kopi = enak,,4,0\n"
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected CSV:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestXLSXWriter(t *testing.T) {
+	f, err := excelize.OpenReader(bytes.NewReader(writeSample(t, FormatXLSX)))
+	if err != nil {
+		t.Fatalf("not a workbook: %v", err)
+	}
+	defer f.Close()
+
+	if sheets := f.GetSheetList(); len(sheets) != 2 || sheets[0] != "Ringkasan" || sheets[1] != "Transaksi" {
+		t.Fatalf("expected one sheet per section, got %v", sheets)
+	}
+	rows, err := f.GetRows("Ringkasan", excelize.Options{RawCellValue: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[0][0] != "Metrik" || rows[1][1] != "1250000" {
+		t.Errorf("unexpected rows: %v", rows)
+	}
+	waktu, err := f.GetCellValue("Transaksi", "B2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if waktu != "2026-03-01 21:30:00" {
+		t.Errorf("expected the time in its own zone, got %q", waktu)
+	}
+}
+
+func TestPDFWriter(t *testing.T) {
+	got := writeSample(t, FormatPDF)
+	if !bytes.HasPrefix(got, []byte("%PDF-")) || !bytes.Contains(got, []byte("%%EOF")) {
+		t.Errorf("not a PDF document: %q", got[:min(len(got), 20)])
+	}
+}
+
+func TestRowBeforeSection(t *testing.T) {
+	for _, format := range []string{FormatCSV, FormatXLSX, FormatPDF} {
+		w, err := NewWriter(format, &bytes.Buffer{}, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Row("x"); err == nil {
+			t.Errorf("%s: expected an error for a row outside a section", format)
+		}
+	}
+}
+
+func TestPDFValue(t *testing.T) {
+	tests := map[interface{}]string{
+		0:        "0",
+		999:      "999",
+		1250000:  "1.250.000",
+		-45000:   "-45.000",
+		12.5:     "12,50",
+		"Kopi":   "Kopi",
+		true:     "true",
+		int(1e9): "1.000.000.000",
+	}
+	for v, want := range tests {
+		if got := pdfValue(v); got != want {
+			t.Errorf("pdfValue(%v) = %q, want %q", v, got, want)
+		}
+	}
+	if strings.Contains(pdfValue(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)), ":05") {
+		t.Error("expected PDF times without seconds")
+	}
+}
@@ -0,0 +1,157 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	pdfMargin    = 15.0
+	pdfRowHeight = 6.0
+	pdfFontSize  = 9.0
+)
+
+// pdfWriter lays sections out as ruled tables on A4 pages, one after the
+// other, repeating a table's headings when it runs onto a new page. Columns
+// share the page width equally and long text is cut short, so it suits
+// summaries rather than wide listings.
+type pdfWriter struct {
+	out       io.Writer
+	pdf       *fpdf.Fpdf
+	translate func(string) string
+	columns   []string
+	widths    []float64
+}
+
+func newPDFWriter(w io.Writer, title string) *pdfWriter {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(false, pdfMargin)
+	pdf.SetTitle(title, true)
+	pdf.AliasNbPages("")
+	p := &pdfWriter{out: w, pdf: pdf, translate: pdf.UnicodeTranslatorFromDescriptor("")}
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pdfMargin + 5)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(0, 8, p.translate(title), "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+	return p
+}
+
+func (p *pdfWriter) Section(title string, columns ...string) error {
+	p.columns = columns
+	p.widths = make([]float64, len(columns))
+	pageWidth, _ := p.pdf.GetPageSize()
+	for i := range p.widths {
+		p.widths[i] = (pageWidth - 2*pdfMargin) / float64(len(columns))
+	}
+
+	// Keep the title with at least the headings and a first row
+	p.ensureRoom(8 + 3*pdfRowHeight)
+	p.pdf.Ln(3)
+	p.pdf.SetFont("Helvetica", "B", 11)
+	p.pdf.CellFormat(0, 8, p.translate(title), "", 1, "L", false, 0, "")
+	p.headings()
+	return p.pdf.Error()
+}
+
+func (p *pdfWriter) headings() {
+	p.pdf.SetFont("Helvetica", "B", pdfFontSize)
+	p.pdf.SetFillColor(217, 217, 217)
+	for i, c := range p.columns {
+		p.pdf.CellFormat(p.widths[i], pdfRowHeight, p.fit(c, p.widths[i]), "1", 0, "L", true, 0, "")
+	}
+	p.pdf.Ln(-1)
+}
+
+func (p *pdfWriter) Row(values ...interface{}) error {
+	if p.columns == nil {
+		return errNoSection
+	}
+	if p.ensureRoom(pdfRowHeight) {
+		p.headings()
+	}
+	p.pdf.SetFont("Helvetica", "", pdfFontSize)
+	for i := range p.columns {
+		var v interface{}
+		if i < len(values) {
+			v = values[i]
+		}
+		align := "L"
+		switch v.(type) {
+		case int, float64:
+			align = "R"
+		}
+		p.pdf.CellFormat(p.widths[i], pdfRowHeight, p.fit(pdfValue(v), p.widths[i]), "1", 0, align, false, 0, "")
+	}
+	p.pdf.Ln(-1)
+	return p.pdf.Error()
+}
+
+// ensureRoom starts a new page unless height still fits on this one and
+// reports whether it did.
+func (p *pdfWriter) ensureRoom(height float64) bool {
+	_, pageHeight := p.pdf.GetPageSize()
+	if p.pdf.GetY()+height <= pageHeight-pdfMargin {
+		return false
+	}
+	p.pdf.AddPage()
+	return true
+}
+
+// fit encodes s for the core fonts and shortens it to fit a cell of width w.
+func (p *pdfWriter) fit(s string, w float64) string {
+	s = p.translate(s)
+	room := w - 2
+	if p.pdf.GetStringWidth(s) <= room {
+		return s
+	}
+	for len(s) > 0 && p.pdf.GetStringWidth(s+"...") > room {
+		s = s[:len(s)-1]
+	}
+	return s + "..."
+}
+
+func (p *pdfWriter) Close() error {
+	return p.pdf.Output(p.out)
+}
+
+// pdfValue renders a cell for reading, with Indonesian digit grouping
+// (1.250.000 and 12,50).
+func pdfValue(v interface{}) string {
+	switch v := v.(type) {
+	case int:
+		return groupThousands(v)
+	case float64:
+		return strings.Replace(strconv.FormatFloat(v, 'f', 2, 64), ".", ",", 1)
+	case time.Time:
+		return v.Format("2006-01-02 15:04")
+	}
+	return formatValue(v)
+}
+
+func groupThousands(n int) string {
+	s := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+	var b strings.Builder
+	for i, d := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	return sign + b.String()
+}
@@ -0,0 +1,142 @@
+package export
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Excel limits sheet names to 31 characters and forbids a few of them.
+const maxSheetName = 31
+
+var sheetNameReplacer = strings.NewReplacer(":", "-", "\\", "-", "/", "-", "?", "", "*", "", "[", "(", "]", ")")
+
+// xlsxWriter puts every section on a sheet of its own, headings in the
+// first row, which stays in view while scrolling. Numbers and times are
+// written as typed cells so the accountant can sum and filter them.
+type xlsxWriter struct {
+	out      io.Writer
+	file     *excelize.File
+	stream   *excelize.StreamWriter
+	sections int
+	row      int
+	err      error
+
+	headingStyle, intStyle, floatStyle, timeStyle int
+}
+
+func newXLSXWriter(w io.Writer, title string) *xlsxWriter {
+	x := &xlsxWriter{out: w, file: excelize.NewFile()}
+	x.err = x.file.SetDocProps(&excelize.DocProperties{Title: title})
+	x.headingStyle = x.style(&excelize.Style{Font: &excelize.Font{Bold: true}, Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"D9D9D9"}}})
+	x.intStyle = x.style(&excelize.Style{NumFmt: 3})
+	x.floatStyle = x.style(&excelize.Style{NumFmt: 4})
+	timeFormat := "yyyy-mm-dd hh:mm:ss"
+	x.timeStyle = x.style(&excelize.Style{CustomNumFmt: &timeFormat})
+	return x
+}
+
+func (x *xlsxWriter) style(s *excelize.Style) int {
+	id, err := x.file.NewStyle(s)
+	if err != nil && x.err == nil {
+		x.err = err
+	}
+	return id
+}
+
+func (x *xlsxWriter) Section(title string, columns ...string) error {
+	if x.err != nil {
+		return x.err
+	}
+	if err := x.flush(); err != nil {
+		return err
+	}
+
+	name := sheetNameReplacer.Replace(title)
+	if len([]rune(name)) > maxSheetName {
+		name = string([]rune(name)[:maxSheetName])
+	}
+	// A new workbook comes with one empty sheet, which the first section takes over
+	if x.sections == 0 {
+		if err := x.file.SetSheetName(x.file.GetSheetName(0), name); err != nil {
+			return err
+		}
+	} else if _, err := x.file.NewSheet(name); err != nil {
+		return err
+	}
+	x.sections++
+
+	stream, err := x.file.NewStreamWriter(name)
+	if err != nil {
+		return err
+	}
+	x.stream = stream
+	for i, c := range columns {
+		width := float64(len([]rune(c)) + 4)
+		if width < 12 {
+			width = 12
+		}
+		if err := stream.SetColWidth(i+1, i+1, width); err != nil {
+			return err
+		}
+	}
+	if err := stream.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		return err
+	}
+
+	headings := make([]interface{}, len(columns))
+	for i, c := range columns {
+		headings[i] = excelize.Cell{StyleID: x.headingStyle, Value: c}
+	}
+	x.row = 1
+	return stream.SetRow("A1", headings)
+}
+
+func (x *xlsxWriter) Row(values ...interface{}) error {
+	if x.stream == nil {
+		return errNoSection
+	}
+	cells := make([]interface{}, len(values))
+	for i, v := range values {
+		switch v.(type) {
+		case int:
+			cells[i] = excelize.Cell{StyleID: x.intStyle, Value: v}
+		case float64:
+			cells[i] = excelize.Cell{StyleID: x.floatStyle, Value: v}
+		case time.Time:
+			cells[i] = excelize.Cell{StyleID: x.timeStyle, Value: v}
+		default:
+			cells[i] = v
+		}
+	}
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	return x.stream.SetRow(cell, cells)
+}
+
+// flush finishes the sheet being streamed; excelize writes one sheet at a
+// time.
+func (x *xlsxWriter) flush() error {
+	if x.stream == nil {
+		return nil
+	}
+	err := x.stream.Flush()
+	x.stream = nil
+	return err
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+	if x.err != nil {
+		return x.err
+	}
+	if err := x.flush(); err != nil {
+		return err
+	}
+	return x.file.Write(x.out)
+}
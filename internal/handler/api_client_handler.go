@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"kasir-api/internal/model"
+	"kasir-api/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type APIClientHandler struct {
+	service service.APIClientService
+}
+
+func NewAPIClientHandler(service service.APIClientService) *APIClientHandler {
+	return &APIClientHandler{service: service}
+}
+
+func (h *APIClientHandler) HandleAPIClients(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Path != "/api-clients" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *APIClientHandler) HandleAPIClientByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	idStr := strings.TrimPrefix(r.URL.Path, "/api-clients/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodDelete:
+		h.revoke(w, r, id)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getAll godoc
+// @Summary Get all API clients
+// @Description Get all API clients; keys themselves are never returned
+// @Tags api-clients
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} model.APIClient
+// @Failure 500 {object} map[string]string
+// @Router /api-clients [get]
+func (h *APIClientHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	clients, err := h.service.GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(clients)
+}
+
+// create godoc
+// @Summary Create an API client
+// @Description Create an API client with a role. The key in the response is shown only once; send it in the X-API-Key header.
+// @Tags api-clients
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param client body model.APIClientRequest true "API client"
+// @Success 201 {object} model.APIClientCredentials
+// @Failure 400 {object} map[string]string
+// @Router /api-clients [post]
+func (h *APIClientHandler) create(w http.ResponseWriter, r *http.Request) {
+	var req model.APIClientRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	created, err := h.service.Create(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// revoke godoc
+// @Summary Revoke an API client
+// @Description Deactivate an API client so its key is no longer accepted
+// @Tags api-clients
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "API client ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /api-clients/{id} [delete]
+func (h *APIClientHandler) revoke(w http.ResponseWriter, r *http.Request, id int) {
+	if err := h.service.Revoke(id); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
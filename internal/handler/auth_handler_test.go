@@ -0,0 +1,66 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"kasir-api/internal/handler"
+	"kasir-api/internal/model"
+	"kasir-api/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLogin(t *testing.T) {
+	mockService := &MockAuthService{
+		LoginFunc: func(req model.LoginRequest) (*model.LoginResponse, error) {
+			if req.Username != "kasir1" || req.Password != "rahasia123" {
+				return nil, service.ErrInvalidCredentials
+			}
+			return &model.LoginResponse{Token: "signed", User: model.User{ID: 3, Username: "kasir1", Role: model.RoleCashier}}, nil
+		},
+	}
+	h := handler.NewAuthHandler(mockService)
+
+	payload := []byte(`{"username":"kasir1", "password":"rahasia123"}`)
+	req, err := http.NewRequest("POST", "/auth/login", bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleLogin).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	var resp model.LoginResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Token != "signed" || resp.User.ID != 3 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+}
+
+func TestLoginWrongPassword(t *testing.T) {
+	mockService := &MockAuthService{
+		LoginFunc: func(req model.LoginRequest) (*model.LoginResponse, error) {
+			return nil, service.ErrInvalidCredentials
+		},
+	}
+	h := handler.NewAuthHandler(mockService)
+
+	req, err := http.NewRequest("POST", "/auth/login", bytes.NewBufferString(`{"username":"kasir1", "password":"salah"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleLogin).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusUnauthorized {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusUnauthorized)
+	}
+}
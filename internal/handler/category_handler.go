@@ -62,6 +62,8 @@ func (h *CategoryHandler) HandleCategoryByID(w http.ResponseWriter, r *http.Requ
 // @Description Get all categories
 // @Tags categories
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Success 200 {array} model.Category
 // @Failure 500 {object} map[string]string
 // @Router /categories [get]
@@ -80,6 +82,8 @@ func (h *CategoryHandler) getAll(w http.ResponseWriter, r *http.Request) {
 // @Tags categories
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param category body model.Category true "Category object"
 // @Success 201 {object} model.Category
 // @Failure 400 {object} map[string]string
@@ -104,6 +108,8 @@ func (h *CategoryHandler) create(w http.ResponseWriter, r *http.Request) {
 // @Description Get a single category by ID
 // @Tags categories
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path int true "Category ID"
 // @Success 200 {object} model.Category
 // @Failure 404 {object} map[string]string
@@ -123,6 +129,8 @@ func (h *CategoryHandler) getByID(w http.ResponseWriter, r *http.Request, id int
 // @Tags categories
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path int true "Category ID"
 // @Param category body model.Category true "Category object"
 // @Success 200 {object} model.Category
@@ -147,6 +155,8 @@ func (h *CategoryHandler) update(w http.ResponseWriter, r *http.Request, id int)
 // @Summary Delete a category
 // @Description Delete a category by ID
 // @Tags categories
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path int true "Category ID"
 // @Success 204
 // @Failure 404 {object} map[string]string
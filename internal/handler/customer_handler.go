@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"kasir-api/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type CustomerHandler struct {
+	service            service.CustomerService
+	transactionService service.TransactionService
+}
+
+func NewCustomerHandler(service service.CustomerService, transactionService service.TransactionService) *CustomerHandler {
+	return &CustomerHandler{service: service, transactionService: transactionService}
+}
+
+func (h *CustomerHandler) HandleCustomers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Path != "/customers" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *CustomerHandler) HandleCustomerByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	idStr, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/customers/"), "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.getByID(w, r, id)
+	case action == "" && r.Method == http.MethodPut:
+		h.update(w, r, id)
+	case action == "" && r.Method == http.MethodDelete:
+		h.delete(w, r, id)
+	case action == "transactions" && r.Method == http.MethodGet:
+		h.getTransactions(w, r, id)
+	case action == "points" && r.Method == http.MethodGet:
+		h.getPoints(w, r, id)
+	case action != "" && action != "transactions" && action != "points":
+		http.NotFound(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getAll godoc
+// @Summary Get customers
+// @Description Get registered customers by name, optionally searching name, phone and member number
+// @Tags customers
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param search query string false "Part of the name, phone or member number"
+// @Success 200 {array} model.Customer
+// @Failure 500 {object} map[string]string
+// @Router /customers [get]
+func (h *CustomerHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	customers, err := h.service.GetAll(r.URL.Query().Get("search"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(customers)
+}
+
+// create godoc
+// @Summary Register a customer
+// @Description Register a customer. A member number is assigned when none is given; points start at zero.
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param customer body model.Customer true "Customer object"
+// @Success 201 {object} model.Customer
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /customers [post]
+func (h *CustomerHandler) create(w http.ResponseWriter, r *http.Request) {
+	var c model.Customer
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	created, err := h.service.Create(c)
+	if err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// getByID godoc
+// @Summary Get customer by ID
+// @Description Get a customer with their current points balance
+// @Tags customers
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Customer ID"
+// @Success 200 {object} model.Customer
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /customers/{id} [get]
+func (h *CustomerHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	c, err := h.service.GetByID(id)
+	if err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(c)
+}
+
+// update godoc
+// @Summary Update a customer
+// @Description Update a customer's name, phone or member number. The points balance cannot be edited.
+// @Tags customers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Customer ID"
+// @Param customer body model.Customer true "Customer object"
+// @Success 200 {object} model.Customer
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /customers/{id} [put]
+func (h *CustomerHandler) update(w http.ResponseWriter, r *http.Request, id int) {
+	var c model.Customer
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	updated, err := h.service.Update(id, c)
+	if err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(updated)
+}
+
+// delete godoc
+// @Summary Delete a customer
+// @Description Delete a customer and their points ledger. Their past transactions are kept without the customer.
+// @Tags customers
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Customer ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /customers/{id} [delete]
+func (h *CustomerHandler) delete(w http.ResponseWriter, r *http.Request, id int) {
+	if err := h.service.Delete(id); err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getTransactions godoc
+// @Summary Get customer purchase history
+// @Description Get a paginated list of the customer's transactions with their details, newest first. Accepts the same filters as /transactions.
+// @Tags customers
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Customer ID"
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param product_id query int false "Only transactions containing this product"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Success 200 {object} model.TransactionList
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /customers/{id}/transactions [get]
+func (h *CustomerHandler) getTransactions(w http.ResponseWriter, r *http.Request, id int) {
+	if _, err := h.service.GetByID(id); err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+
+	filter, err := parseTransactionFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter.CustomerID = id
+
+	list, err := h.transactionService.GetTransactions(filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(list)
+}
+
+// getPoints godoc
+// @Summary Get customer points ledger
+// @Description Get every change to the customer's points balance, newest first
+// @Tags customers
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Customer ID"
+// @Success 200 {array} model.PointEntry
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /customers/{id}/points [get]
+func (h *CustomerHandler) getPoints(w http.ResponseWriter, r *http.Request, id int) {
+	entries, err := h.service.GetPointEntries(id)
+	if err != nil {
+		writeCustomerError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(entries)
+}
+
+func writeCustomerError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Customer not found", http.StatusNotFound)
+	case errors.Is(err, repository.ErrInvalidCustomer):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
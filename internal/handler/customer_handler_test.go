@@ -0,0 +1,71 @@
+package handler_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"kasir-api/internal/handler"
+	"kasir-api/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetCustomerTransactions(t *testing.T) {
+	var got model.TransactionFilter
+	customers := &MockCustomerService{
+		GetByIDFunc: func(id int) (*model.Customer, error) {
+			return &model.Customer{ID: id, Name: "Siti"}, nil
+		},
+	}
+	transactions := &MockTransactionService{
+		GetTransactionsFunc: func(filter model.TransactionFilter) (*model.TransactionList, error) {
+			got = filter
+			return &model.TransactionList{Data: []model.Transaction{{ID: 10, CustomerID: filter.CustomerID}}, Page: 1, Limit: 20, Total: 1}, nil
+		},
+	}
+	h := handler.NewCustomerHandler(customers, transactions)
+
+	req, err := http.NewRequest("GET", "/customers/7/transactions?start_date=2026-01-01", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleCustomerByID).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if got.CustomerID != 7 || got.StartDate != "2026-01-01" {
+		t.Errorf("unexpected filter: %+v", got)
+	}
+
+	var list model.TransactionList
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].CustomerID != 7 {
+		t.Errorf("unexpected transactions: %+v", list.Data)
+	}
+}
+
+func TestGetCustomerPointsNotFound(t *testing.T) {
+	customers := &MockCustomerService{
+		GetPointEntriesFunc: func(customerID int) ([]model.PointEntry, error) {
+			return nil, sql.ErrNoRows
+		},
+	}
+	h := handler.NewCustomerHandler(customers, &MockTransactionService{})
+
+	req, err := http.NewRequest("GET", "/customers/99/points", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleCustomerByID).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+}
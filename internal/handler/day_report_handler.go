@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/auth"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"kasir-api/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type DayReportHandler struct {
+	service service.DayReportService
+}
+
+func NewDayReportHandler(service service.DayReportService) *DayReportHandler {
+	return &DayReportHandler{service: service}
+}
+
+// HandleXReport godoc
+// @Summary Get an X report
+// @Description Get the running end-of-day totals of a business day without closing it: sales, refunds, tax per rate, discounts and money taken and refunded per payment method. Can be taken any number of times.
+// @Tags day-close
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param date query string false "Business day (YYYY-MM-DD), today by default"
+// @Success 200 {object} model.DayReport
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /x-report [get]
+func (h *DayReportHandler) HandleXReport(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Path != "/x-report" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	report, err := h.service.GetXReport(r.URL.Query().Get("date"))
+	if err != nil {
+		writeDayReportError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+func (h *DayReportHandler) HandleZReports(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Path != "/z-reports" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.closeDay(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *DayReportHandler) HandleZReportByNumber(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	numberStr, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/z-reports/"), "/")
+	zNumber, err := strconv.Atoi(numberStr)
+	if err != nil {
+		http.Error(w, "Invalid Z number", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case action != "":
+		http.NotFound(w, r)
+	case r.Method == http.MethodGet:
+		h.getByNumber(w, r, zNumber)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getAll godoc
+// @Summary Get Z reports
+// @Description Get every Z report, newest first. The payment and tax breakdowns are returned by the single report endpoint.
+// @Tags day-close
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} model.DayReport
+// @Failure 500 {object} map[string]string
+// @Router /z-reports [get]
+func (h *DayReportHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	reports, err := h.service.GetZReports()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(reports)
+}
+
+// closeDay godoc
+// @Summary Close a business day
+// @Description Take the Z report of a business day, today by default. Its totals are frozen under the next Z number and cannot be changed. After the first Z report, days are closed one after the other with none skipped, including days without sales; once a day is closed, checkouts, refunds and voids on it are rejected.
+// @Tags day-close
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param close body model.CloseDayRequest true "Business day to close"
+// @Success 201 {object} model.DayReport
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /z-reports [post]
+func (h *DayReportHandler) closeDay(w http.ResponseWriter, r *http.Request) {
+	var req model.CloseDayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		req.ClosedBy = p.UserID
+	}
+
+	report, err := h.service.CloseDay(req)
+	if err != nil {
+		writeDayReportError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(report)
+}
+
+// getByNumber godoc
+// @Summary Get Z report by number
+// @Description Get a Z report with its payment and tax breakdowns. Per-method refunded and net are null on Z reports taken before they were recorded.
+// @Tags day-close
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param z_number path int true "Z number"
+// @Success 200 {object} model.DayReport
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /z-reports/{z_number} [get]
+func (h *DayReportHandler) getByNumber(w http.ResponseWriter, r *http.Request, zNumber int) {
+	report, err := h.service.GetZReport(zNumber)
+	if err != nil {
+		writeDayReportError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+func writeDayReportError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Z report not found", http.StatusNotFound)
+	case errors.Is(err, repository.ErrInvalidDayClose):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
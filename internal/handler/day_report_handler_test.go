@@ -0,0 +1,115 @@
+package handler_test
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"kasir-api/internal/auth"
+	"kasir-api/internal/handler"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCloseDay(t *testing.T) {
+	var got model.CloseDayRequest
+	mockService := &MockDayReportService{
+		CloseDayFunc: func(req model.CloseDayRequest) (*model.DayReport, error) {
+			got = req
+			return &model.DayReport{ZNumber: 12, BusinessDate: req.BusinessDate, GrossSales: 150000, TotalRefund: 20000, NetSales: 130000}, nil
+		},
+	}
+	h := handler.NewDayReportHandler(mockService)
+
+	body := []byte(`{"business_date": "2024-03-01", "note": "tutup toko"}`)
+	req, err := http.NewRequest("POST", "/z-reports", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{Type: auth.PrincipalUser, UserID: 2}))
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleZReports).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+	if got.BusinessDate != "2024-03-01" || got.ClosedBy != 2 {
+		t.Errorf("unexpected close request: %+v", got)
+	}
+
+	var report model.DayReport
+	if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if report.ZNumber != 12 || report.NetSales != 130000 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func TestCloseDayErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"already closed", fmt.Errorf("%w: business days up to 2024-03-01 are already closed (Z 12)", repository.ErrInvalidDayClose), http.StatusBadRequest},
+		{"day skipped", fmt.Errorf("%w: business day 2024-03-02 has to be closed before 2024-03-03", repository.ErrInvalidDayClose), http.StatusBadRequest},
+		{"database down", sql.ErrConnDone, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler.NewDayReportHandler(&MockDayReportService{
+				CloseDayFunc: func(req model.CloseDayRequest) (*model.DayReport, error) {
+					return nil, tt.err
+				},
+			})
+
+			rr := httptest.NewRecorder()
+			h.HandleZReports(rr, httptest.NewRequest(http.MethodPost, "/z-reports", bytes.NewBufferString(`{}`)))
+
+			if rr.Code != tt.wantStatus {
+				t.Errorf("expected %d, got %d", tt.wantStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestGetXReport(t *testing.T) {
+	var gotDate string
+	h := handler.NewDayReportHandler(&MockDayReportService{
+		GetXReportFunc: func(businessDate string) (*model.DayReport, error) {
+			gotDate = businessDate
+			return &model.DayReport{BusinessDate: businessDate, Payments: []model.DayReportPayment{{Method: model.PaymentMethodCash, Amount: 50000, TransactionCount: 3}}}, nil
+		},
+	})
+
+	rr := httptest.NewRecorder()
+	h.HandleXReport(rr, httptest.NewRequest(http.MethodGet, "/x-report?date=2024-03-02", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if gotDate != "2024-03-02" {
+		t.Errorf("expected date 2024-03-02, got %q", gotDate)
+	}
+}
+
+func TestGetZReportNotFound(t *testing.T) {
+	h := handler.NewDayReportHandler(&MockDayReportService{
+		GetZReportFunc: func(zNumber int) (*model.DayReport, error) {
+			return nil, sql.ErrNoRows
+		},
+	})
+
+	rr := httptest.NewRecorder()
+	h.HandleZReportByNumber(rr, httptest.NewRequest(http.MethodGet, "/z-reports/99", nil))
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rr.Code)
+	}
+}
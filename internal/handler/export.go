@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"kasir-api/internal/export"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"kasir-api/internal/service"
+	"log"
+	"net/http"
+)
+
+// exportFormat picks the response format from the format query parameter or
+// the Accept header; JSON unless asked otherwise.
+func exportFormat(r *http.Request) (string, error) {
+	return export.Negotiate(r.URL.Query().Get("format"), r.Header.Get("Accept"))
+}
+
+// exportResponse records whether any of an export has been sent, after
+// which a failure can no longer be turned into an error response.
+type exportResponse struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (e *exportResponse) Write(p []byte) (int, error) {
+	e.written = true
+	return e.ResponseWriter.Write(p)
+}
+
+// startExport sets up a download of filename (without extension) in format.
+func startExport(w http.ResponseWriter, format, filename, title string) (*exportResponse, export.Writer, error) {
+	res := &exportResponse{ResponseWriter: w}
+	ew, err := export.NewWriter(format, res, title)
+	if err != nil {
+		return nil, nil, err
+	}
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+"."+format))
+	return res, ew, nil
+}
+
+// failExport answers with err and status while nothing has been sent.
+// Once part of the file is out the connection is dropped instead, so the
+// client cannot mistake a truncated file for a complete one.
+func failExport(res *exportResponse, err error, status int) {
+	if res.written {
+		log.Printf("export aborted: %v", err)
+		panic(http.ErrAbortHandler)
+	}
+	res.Header().Del("Content-Disposition")
+	http.Error(res.ResponseWriter, err.Error(), status)
+}
+
+// stickyWriter keeps the first error of a run of writes, so a document can
+// be written out without checking every call.
+type stickyWriter struct {
+	w   export.Writer
+	err error
+}
+
+func (s *stickyWriter) section(title string, columns ...string) {
+	if s.err == nil {
+		s.err = s.w.Section(title, columns...)
+	}
+}
+
+func (s *stickyWriter) row(values ...interface{}) {
+	if s.err == nil {
+		s.err = s.w.Row(values...)
+	}
+}
+
+// percent turns basis points into a percentage.
+func percent(bp int) float64 {
+	return float64(bp) / 100
+}
+
+// optionalID leaves a cell empty for an unset reference.
+func optionalID(id int) interface{} {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+// writeSalesReport lays the sales report out as sections that follow its
+// JSON: the summary first, then each breakdown.
+func writeSalesReport(ew export.Writer, report *repository.SalesReport) error {
+	s := &stickyWriter{w: ew}
+
+	s.section("Ringkasan", "Metrik", "Nilai")
+	s.row("Tanggal Mulai", report.TanggalMulai)
+	s.row("Tanggal Akhir", report.TanggalAkhir)
+	s.row("Total Revenue", report.TotalRevenue)
+	s.row("Total Refund", report.TotalRefund)
+	s.row("Total Transaksi", report.TotalTransaksi)
+	s.row("Rata-rata Belanja", report.RataRataBelanja)
+	s.row("Item per Transaksi", report.ItemPerTransaksi)
+	s.row("Total Diskon", report.TotalDiskon)
+	s.row("Total Pajak", report.TotalPajak)
+	s.row("Penjualan Bersih", report.PenjualanBersih)
+	s.row("Total HPP", report.TotalHPP)
+	s.row("Laba Kotor", report.LabaKotor)
+	s.row("Margin (%)", percent(report.MarginBP))
+	s.row("Produk Terlaris", report.ProdukTerlaris.Nama)
+
+	s.section("Terlaris Qty", "Produk", "Qty Terjual", "Total Revenue")
+	for _, p := range report.TerlarisQty {
+		s.row(p.Nama, p.QtyTerjual, p.TotalRevenue)
+	}
+	s.section("Terlaris Revenue", "Produk", "Qty Terjual", "Total Revenue")
+	for _, p := range report.TerlarisRevenue {
+		s.row(p.Nama, p.QtyTerjual, p.TotalRevenue)
+	}
+	s.section("Per Kategori", "Kategori", "Qty Terjual", "Total Revenue")
+	for _, k := range report.PerKategoriRevenue {
+		s.row(k.Nama, k.QtyTerjual, k.TotalRevenue)
+	}
+	s.section("Per Waktu", "Waktu", "Total Revenue", "Total Transaksi")
+	for _, p := range report.PerWaktu {
+		s.row(p.Waktu, p.TotalRevenue, p.TotalTransaksi)
+	}
+	s.section("Per Metode", "Metode", "Total Revenue", "Total Refund", "Total Transaksi")
+	for _, m := range report.PerMetode {
+		s.row(m.Metode, m.TotalRevenue, m.TotalRefund, m.TotalTransaksi)
+	}
+	s.section("Pajak", "Nama", "Tarif (%)", "Inklusif", "DPP", "Total Pajak")
+	for _, p := range report.Pajak {
+		s.row(p.Nama, percent(p.TarifBP), p.Inklusif, p.DPP, p.TotalPajak)
+	}
+	s.section("Promosi", "Nama", "Total Transaksi", "Total Diskon")
+	for _, p := range report.Promosi {
+		s.row(p.Nama, p.TotalTransaksi, p.TotalDiskon)
+	}
+
+	if len(report.PerKasir) > 0 {
+		s.section("Per Kasir", "Kasir", "Total Revenue", "Total Transaksi")
+		for _, k := range report.PerKasir {
+			s.row(k.Nama, k.TotalRevenue, k.TotalTransaksi)
+		}
+	}
+	profitSection := func(title, name string, lines []repository.RingkasanLaba) {
+		if len(lines) == 0 {
+			return
+		}
+		s.section(title, name, "Qty Terjual", "Penjualan Bersih", "Total HPP", "Laba Kotor", "Margin (%)")
+		for _, l := range lines {
+			s.row(l.Nama, l.QtyTerjual, l.PenjualanBersih, l.TotalHPP, l.LabaKotor, percent(l.MarginBP))
+		}
+	}
+	profitSection("Laba per Produk", "Produk", report.PerProduk)
+	profitSection("Laba per Kategori", "Kategori", report.PerKategori)
+	if len(report.PerHari) > 0 {
+		s.section("Laba per Hari", "Tanggal", "Penjualan Bersih", "Total HPP", "Laba Kotor", "Margin (%)")
+		for _, d := range report.PerHari {
+			s.row(d.Tanggal, d.PenjualanBersih, d.TotalHPP, d.LabaKotor, percent(d.MarginBP))
+		}
+	}
+
+	return s.err
+}
+
+// exportSalesReport sends report as a CSV, XLSX or PDF download.
+func exportSalesReport(w http.ResponseWriter, format string, report *repository.SalesReport) {
+	period := report.TanggalMulai
+	if report.TanggalAkhir != report.TanggalMulai {
+		period += " s/d " + report.TanggalAkhir
+	}
+	res, ew, err := startExport(w, format, "laporan-penjualan-"+report.TanggalMulai+"_"+report.TanggalAkhir, "Laporan Penjualan "+period)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := writeSalesReport(ew, report); err != nil {
+		failExport(res, err, http.StatusInternalServerError)
+		return
+	}
+	if err := ew.Close(); err != nil {
+		failExport(res, err, http.StatusInternalServerError)
+	}
+}
+
+// exportTransactions sends every transaction matching filter as a download.
+// CSV holds one row per transaction and is streamed as the rows are read.
+// XLSX adds sheets with the items and payments; PDF prints a condensed
+// listing with totals.
+func (h *TransactionHandler) exportTransactions(w http.ResponseWriter, format string, filter model.TransactionFilter) {
+	res, ew, err := startExport(w, format, "transactions", "Transactions")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	columns := []string{"ID", "Invoice", "Store", "Created At", "Status", "Cashier ID", "Cashier", "Terminal", "Shift ID", "Customer ID", "Voucher",
+		"Subtotal", "Discount", "Voucher Discount", "Tax", "Total", "Paid", "Change", "Points Earned", "Points Redeemed"}
+	values := func(t model.Transaction) []interface{} {
+		return []interface{}{t.ID, t.InvoiceNumber, t.StoreCode, t.CreatedAt, t.Status, optionalID(t.CashierID), t.CashierName, t.TerminalID, optionalID(t.ShiftID),
+			optionalID(t.CustomerID), t.VoucherCode, t.SubtotalAmount, t.DiscountAmount, t.VoucherDiscount, t.TaxAmount, t.TotalAmount,
+			t.PaidAmount, t.ChangeAmount, t.PointsEarned, t.PointsRedeemed}
+	}
+	if format == export.FormatPDF {
+		columns = []string{"ID", "Time", "Cashier", "Status", "Discount", "Tax", "Total"}
+		values = func(t model.Transaction) []interface{} {
+			return []interface{}{t.ID, t.CreatedAt, t.CashierName, t.Status, t.DiscountAmount, t.TaxAmount, t.TotalAmount}
+		}
+	}
+
+	// The headings wait for the first transaction: until then nothing has
+	// been sent and a bad filter is still answered with 400, as in JSON
+	rows := 0
+	var voided, discount, tax, total int
+	err = h.service.EachTransaction(filter, func(t model.Transaction) error {
+		if rows == 0 {
+			if err := ew.Section("Transactions", columns...); err != nil {
+				return err
+			}
+		}
+		rows++
+		if t.Status == model.TransactionStatusVoided {
+			voided++
+		} else {
+			discount += t.DiscountAmount
+			tax += t.TaxAmount
+			total += t.TotalAmount
+		}
+		return ew.Row(values(t)...)
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidFilter) {
+			status = http.StatusBadRequest
+		}
+		failExport(res, err, status)
+		return
+	}
+
+	s := &stickyWriter{w: ew}
+	if rows == 0 {
+		s.section("Transactions", columns...)
+	}
+	switch format {
+	case export.FormatXLSX:
+		s.section("Items", "Transaction ID", "Product ID", "Product", "Category", "Quantity", "Unit Price", "Subtotal", "Discount",
+			"Tax Name", "Tax Rate (%)", "Tax Inclusive", "Tax", "Line Total", "Unit Cost", "Refunded Quantity")
+		if s.err == nil {
+			s.err = h.service.EachTransactionDetail(filter, func(d model.TransactionDetail) error {
+				return ew.Row(d.TransactionID, optionalID(d.ProductID), d.ProductName, d.CategoryName, d.Quantity, d.UnitPrice, d.Subtotal,
+					d.DiscountAmount, d.TaxName, percent(d.TaxRate), d.TaxInclusive, d.TaxAmount, d.LineTotal, d.UnitCost, d.RefundedQuantity)
+			})
+		}
+		s.section("Payments", "Transaction ID", "Method", "Amount", "Change", "Reference")
+		if s.err == nil {
+			s.err = h.service.EachPayment(filter, func(p model.Payment) error {
+				return ew.Row(p.TransactionID, p.Method, p.Amount, p.ChangeAmount, p.Reference)
+			})
+		}
+	case export.FormatPDF:
+		s.section("Totals (voided transactions excluded)", "Metric", "Value")
+		s.row("Transactions", rows-voided)
+		s.row("Voided", voided)
+		s.row("Discount", discount)
+		s.row("Tax", tax)
+		s.row("Total", total)
+	}
+	if s.err == nil {
+		s.err = ew.Close()
+	}
+	if s.err != nil {
+		failExport(res, s.err, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"encoding/json"
+	"kasir-api/internal/model"
+	"kasir-api/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type LoyaltyRuleHandler struct {
+	service service.LoyaltyRuleService
+}
+
+func NewLoyaltyRuleHandler(service service.LoyaltyRuleService) *LoyaltyRuleHandler {
+	return &LoyaltyRuleHandler{service: service}
+}
+
+func (h *LoyaltyRuleHandler) HandleLoyaltyRules(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Path != "/loyalty-rules" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *LoyaltyRuleHandler) HandleLoyaltyRuleByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	idStr := strings.TrimPrefix(r.URL.Path, "/loyalty-rules/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getByID(w, r, id)
+	case http.MethodPut:
+		h.update(w, r, id)
+	case http.MethodDelete:
+		h.delete(w, r, id)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getAll godoc
+// @Summary Get all loyalty rules
+// @Description Get all loyalty point earning rules, including inactive ones
+// @Tags loyalty
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} model.LoyaltyRule
+// @Failure 500 {object} map[string]string
+// @Router /loyalty-rules [get]
+func (h *LoyaltyRuleHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.service.GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(rules)
+}
+
+// create godoc
+// @Summary Create a loyalty rule
+// @Description Create a rule earning points for every full spend_amount a customer pays, optionally only counting spend on one category. Active rules stack; each point is worth Rp1 when redeemed.
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param rule body model.LoyaltyRule true "Loyalty rule object"
+// @Success 201 {object} model.LoyaltyRule
+// @Failure 400 {object} map[string]string
+// @Router /loyalty-rules [post]
+func (h *LoyaltyRuleHandler) create(w http.ResponseWriter, r *http.Request) {
+	var rule model.LoyaltyRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	created, err := h.service.Create(rule)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// getByID godoc
+// @Summary Get loyalty rule by ID
+// @Description Get a single loyalty rule
+// @Tags loyalty
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Loyalty rule ID"
+// @Success 200 {object} model.LoyaltyRule
+// @Failure 404 {object} map[string]string
+// @Router /loyalty-rules/{id} [get]
+func (h *LoyaltyRuleHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	rule, err := h.service.GetByID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(rule)
+}
+
+// update godoc
+// @Summary Update a loyalty rule
+// @Description Update a loyalty rule. Points already earned are not recalculated.
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Loyalty rule ID"
+// @Param rule body model.LoyaltyRule true "Loyalty rule object"
+// @Success 200 {object} model.LoyaltyRule
+// @Failure 400 {object} map[string]string
+// @Router /loyalty-rules/{id} [put]
+func (h *LoyaltyRuleHandler) update(w http.ResponseWriter, r *http.Request, id int) {
+	var rule model.LoyaltyRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	updated, err := h.service.Update(id, rule)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(updated)
+}
+
+// delete godoc
+// @Summary Delete a loyalty rule
+// @Description Delete a loyalty rule; set active to false instead to keep it for reference
+// @Tags loyalty
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Loyalty rule ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /loyalty-rules/{id} [delete]
+func (h *LoyaltyRuleHandler) delete(w http.ResponseWriter, r *http.Request, id int) {
+	if err := h.service.Delete(id); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,24 @@
+package handler_test
+
+import (
+	"kasir-api/internal/auth"
+	"kasir-api/internal/model"
+)
+
+type MockAuthService struct {
+	LoginFunc              func(req model.LoginRequest) (*model.LoginResponse, error)
+	AuthenticateFunc       func(token string) (*auth.Principal, error)
+	AuthenticateAPIKeyFunc func(key string) (*auth.Principal, error)
+}
+
+func (m *MockAuthService) Login(req model.LoginRequest) (*model.LoginResponse, error) {
+	return m.LoginFunc(req)
+}
+
+func (m *MockAuthService) Authenticate(token string) (*auth.Principal, error) {
+	return m.AuthenticateFunc(token)
+}
+
+func (m *MockAuthService) AuthenticateAPIKey(key string) (*auth.Principal, error) {
+	return m.AuthenticateAPIKeyFunc(key)
+}
@@ -0,0 +1,38 @@
+package handler_test
+
+import (
+	"kasir-api/internal/model"
+)
+
+type MockCustomerService struct {
+	CreateFunc          func(c model.Customer) (*model.Customer, error)
+	GetAllFunc          func(search string) ([]model.Customer, error)
+	GetByIDFunc         func(id int) (*model.Customer, error)
+	UpdateFunc          func(id int, c model.Customer) (*model.Customer, error)
+	DeleteFunc          func(id int) error
+	GetPointEntriesFunc func(customerID int) ([]model.PointEntry, error)
+}
+
+func (m *MockCustomerService) Create(c model.Customer) (*model.Customer, error) {
+	return m.CreateFunc(c)
+}
+
+func (m *MockCustomerService) GetAll(search string) ([]model.Customer, error) {
+	return m.GetAllFunc(search)
+}
+
+func (m *MockCustomerService) GetByID(id int) (*model.Customer, error) {
+	return m.GetByIDFunc(id)
+}
+
+func (m *MockCustomerService) Update(id int, c model.Customer) (*model.Customer, error) {
+	return m.UpdateFunc(id, c)
+}
+
+func (m *MockCustomerService) Delete(id int) error {
+	return m.DeleteFunc(id)
+}
+
+func (m *MockCustomerService) GetPointEntries(customerID int) ([]model.PointEntry, error) {
+	return m.GetPointEntriesFunc(customerID)
+}
@@ -0,0 +1,28 @@
+package handler_test
+
+import (
+	"kasir-api/internal/model"
+)
+
+type MockDayReportService struct {
+	GetXReportFunc  func(businessDate string) (*model.DayReport, error)
+	CloseDayFunc    func(req model.CloseDayRequest) (*model.DayReport, error)
+	GetZReportsFunc func() ([]model.DayReport, error)
+	GetZReportFunc  func(zNumber int) (*model.DayReport, error)
+}
+
+func (m *MockDayReportService) GetXReport(businessDate string) (*model.DayReport, error) {
+	return m.GetXReportFunc(businessDate)
+}
+
+func (m *MockDayReportService) CloseDay(req model.CloseDayRequest) (*model.DayReport, error) {
+	return m.CloseDayFunc(req)
+}
+
+func (m *MockDayReportService) GetZReports() ([]model.DayReport, error) {
+	return m.GetZReportsFunc()
+}
+
+func (m *MockDayReportService) GetZReport(zNumber int) (*model.DayReport, error) {
+	return m.GetZReportFunc(zNumber)
+}
@@ -0,0 +1,18 @@
+package handler_test
+
+import (
+	"kasir-api/internal/model"
+)
+
+type MockInventoryService struct {
+	GetStockMovementsFunc func(productID int, filter model.StockMovementFilter) ([]model.StockMovement, error)
+	AdjustStockFunc       func(productID int, req model.StockAdjustmentRequest) (*model.StockMovement, error)
+}
+
+func (m *MockInventoryService) GetStockMovements(productID int, filter model.StockMovementFilter) ([]model.StockMovement, error) {
+	return m.GetStockMovementsFunc(productID, filter)
+}
+
+func (m *MockInventoryService) AdjustStock(productID int, req model.StockAdjustmentRequest) (*model.StockMovement, error) {
+	return m.AdjustStockFunc(productID, req)
+}
@@ -0,0 +1,33 @@
+package handler_test
+
+import (
+	"kasir-api/internal/model"
+)
+
+type MockLoyaltyRuleService struct {
+	CreateFunc  func(rule model.LoyaltyRule) (model.LoyaltyRule, error)
+	GetAllFunc  func() ([]model.LoyaltyRule, error)
+	GetByIDFunc func(id int) (model.LoyaltyRule, error)
+	UpdateFunc  func(id int, rule model.LoyaltyRule) (model.LoyaltyRule, error)
+	DeleteFunc  func(id int) error
+}
+
+func (m *MockLoyaltyRuleService) Create(rule model.LoyaltyRule) (model.LoyaltyRule, error) {
+	return m.CreateFunc(rule)
+}
+
+func (m *MockLoyaltyRuleService) GetAll() ([]model.LoyaltyRule, error) {
+	return m.GetAllFunc()
+}
+
+func (m *MockLoyaltyRuleService) GetByID(id int) (model.LoyaltyRule, error) {
+	return m.GetByIDFunc(id)
+}
+
+func (m *MockLoyaltyRuleService) Update(id int, rule model.LoyaltyRule) (model.LoyaltyRule, error) {
+	return m.UpdateFunc(id, rule)
+}
+
+func (m *MockLoyaltyRuleService) Delete(id int) error {
+	return m.DeleteFunc(id)
+}
@@ -5,11 +5,12 @@ import (
 )
 
 type MockProductService struct {
-	CreateFunc  func(product model.Product) (model.Product, error)
-	GetAllFunc  func(nameFilter string) ([]model.Product, error)
-	GetByIDFunc func(id int) (model.Product, error)
-	UpdateFunc  func(id int, product model.Product) (model.Product, error)
-	DeleteFunc  func(id int) error
+	CreateFunc      func(product model.Product) (model.Product, error)
+	GetAllFunc      func(nameFilter string) ([]model.Product, error)
+	GetLowStockFunc func() ([]model.Product, error)
+	GetByIDFunc     func(id int) (model.Product, error)
+	UpdateFunc      func(id int, product model.Product) (model.Product, error)
+	DeleteFunc      func(id int) error
 }
 
 func (m *MockProductService) Create(product model.Product) (model.Product, error) {
@@ -20,6 +21,10 @@ func (m *MockProductService) GetAll(nameFilter string) ([]model.Product, error)
 	return m.GetAllFunc(nameFilter)
 }
 
+func (m *MockProductService) GetLowStock() ([]model.Product, error) {
+	return m.GetLowStockFunc()
+}
+
 func (m *MockProductService) GetByID(id int) (model.Product, error) {
 	return m.GetByIDFunc(id)
 }
@@ -0,0 +1,33 @@
+package handler_test
+
+import (
+	"kasir-api/internal/model"
+)
+
+type MockPromotionService struct {
+	CreateFunc  func(promo model.Promotion) (model.Promotion, error)
+	GetAllFunc  func() ([]model.Promotion, error)
+	GetByIDFunc func(id int) (model.Promotion, error)
+	UpdateFunc  func(id int, promo model.Promotion) (model.Promotion, error)
+	DeleteFunc  func(id int) error
+}
+
+func (m *MockPromotionService) Create(promo model.Promotion) (model.Promotion, error) {
+	return m.CreateFunc(promo)
+}
+
+func (m *MockPromotionService) GetAll() ([]model.Promotion, error) {
+	return m.GetAllFunc()
+}
+
+func (m *MockPromotionService) GetByID(id int) (model.Promotion, error) {
+	return m.GetByIDFunc(id)
+}
+
+func (m *MockPromotionService) Update(id int, promo model.Promotion) (model.Promotion, error) {
+	return m.UpdateFunc(id, promo)
+}
+
+func (m *MockPromotionService) Delete(id int) error {
+	return m.DeleteFunc(id)
+}
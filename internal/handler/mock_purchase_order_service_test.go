@@ -0,0 +1,33 @@
+package handler_test
+
+import (
+	"kasir-api/internal/model"
+)
+
+type MockPurchaseOrderService struct {
+	CreateFunc  func(req model.PurchaseOrderRequest) (*model.PurchaseOrder, error)
+	GetAllFunc  func(filter model.PurchaseOrderFilter) ([]model.PurchaseOrder, error)
+	GetByIDFunc func(id int) (*model.PurchaseOrder, error)
+	ReceiveFunc func(id int, req model.GoodsReceiptRequest) (*model.GoodsReceipt, error)
+	CloseFunc   func(id int, req model.ClosePurchaseOrderRequest) (*model.PurchaseOrder, error)
+}
+
+func (m *MockPurchaseOrderService) Create(req model.PurchaseOrderRequest) (*model.PurchaseOrder, error) {
+	return m.CreateFunc(req)
+}
+
+func (m *MockPurchaseOrderService) GetAll(filter model.PurchaseOrderFilter) ([]model.PurchaseOrder, error) {
+	return m.GetAllFunc(filter)
+}
+
+func (m *MockPurchaseOrderService) GetByID(id int) (*model.PurchaseOrder, error) {
+	return m.GetByIDFunc(id)
+}
+
+func (m *MockPurchaseOrderService) Receive(id int, req model.GoodsReceiptRequest) (*model.GoodsReceipt, error) {
+	return m.ReceiveFunc(id, req)
+}
+
+func (m *MockPurchaseOrderService) Close(id int, req model.ClosePurchaseOrderRequest) (*model.PurchaseOrder, error) {
+	return m.CloseFunc(id, req)
+}
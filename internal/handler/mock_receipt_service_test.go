@@ -0,0 +1,13 @@
+package handler_test
+
+import (
+	"kasir-api/internal/model"
+)
+
+type MockReceiptService struct {
+	PrintReceiptFunc func(transactionID int, req model.ReceiptRequest) ([]byte, error)
+}
+
+func (m *MockReceiptService) PrintReceipt(transactionID int, req model.ReceiptRequest) ([]byte, error) {
+	return m.PrintReceiptFunc(transactionID, req)
+}
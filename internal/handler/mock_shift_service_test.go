@@ -0,0 +1,31 @@
+package handler_test
+
+import "kasir-api/internal/model"
+
+type MockShiftService struct {
+	OpenFunc            func(req model.OpenShiftRequest) (*model.Shift, error)
+	GetAllFunc          func(filter model.ShiftFilter) ([]model.Shift, error)
+	GetByIDFunc         func(id int) (*model.Shift, error)
+	AddCashMovementFunc func(shiftID int, req model.CashMovementRequest) (*model.CashMovement, error)
+	CloseFunc           func(id int, req model.CloseShiftRequest) (*model.Shift, error)
+}
+
+func (m *MockShiftService) Open(req model.OpenShiftRequest) (*model.Shift, error) {
+	return m.OpenFunc(req)
+}
+
+func (m *MockShiftService) GetAll(filter model.ShiftFilter) ([]model.Shift, error) {
+	return m.GetAllFunc(filter)
+}
+
+func (m *MockShiftService) GetByID(id int) (*model.Shift, error) {
+	return m.GetByIDFunc(id)
+}
+
+func (m *MockShiftService) AddCashMovement(shiftID int, req model.CashMovementRequest) (*model.CashMovement, error) {
+	return m.AddCashMovementFunc(shiftID, req)
+}
+
+func (m *MockShiftService) Close(id int, req model.CloseShiftRequest) (*model.Shift, error) {
+	return m.CloseFunc(id, req)
+}
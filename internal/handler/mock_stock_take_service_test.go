@@ -0,0 +1,38 @@
+package handler_test
+
+import (
+	"kasir-api/internal/model"
+)
+
+type MockStockTakeService struct {
+	OpenFunc         func(req model.OpenStockTakeRequest) (*model.StockTake, error)
+	GetAllFunc       func(filter model.StockTakeFilter) ([]model.StockTake, error)
+	GetByIDFunc      func(id int) (*model.StockTake, error)
+	SubmitCountsFunc func(id int, req model.StockCountRequest) (*model.StockTake, error)
+	PostFunc         func(id int, userID int) (*model.StockTake, error)
+	CancelFunc       func(id int, userID int) (*model.StockTake, error)
+}
+
+func (m *MockStockTakeService) Open(req model.OpenStockTakeRequest) (*model.StockTake, error) {
+	return m.OpenFunc(req)
+}
+
+func (m *MockStockTakeService) GetAll(filter model.StockTakeFilter) ([]model.StockTake, error) {
+	return m.GetAllFunc(filter)
+}
+
+func (m *MockStockTakeService) GetByID(id int) (*model.StockTake, error) {
+	return m.GetByIDFunc(id)
+}
+
+func (m *MockStockTakeService) SubmitCounts(id int, req model.StockCountRequest) (*model.StockTake, error) {
+	return m.SubmitCountsFunc(id, req)
+}
+
+func (m *MockStockTakeService) Post(id int, userID int) (*model.StockTake, error) {
+	return m.PostFunc(id, userID)
+}
+
+func (m *MockStockTakeService) Cancel(id int, userID int) (*model.StockTake, error) {
+	return m.CancelFunc(id, userID)
+}
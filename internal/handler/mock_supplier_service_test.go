@@ -0,0 +1,33 @@
+package handler_test
+
+import (
+	"kasir-api/internal/model"
+)
+
+type MockSupplierService struct {
+	CreateFunc  func(s model.Supplier) (*model.Supplier, error)
+	GetAllFunc  func(search string) ([]model.Supplier, error)
+	GetByIDFunc func(id int) (*model.Supplier, error)
+	UpdateFunc  func(id int, s model.Supplier) (*model.Supplier, error)
+	DeleteFunc  func(id int) error
+}
+
+func (m *MockSupplierService) Create(s model.Supplier) (*model.Supplier, error) {
+	return m.CreateFunc(s)
+}
+
+func (m *MockSupplierService) GetAll(search string) ([]model.Supplier, error) {
+	return m.GetAllFunc(search)
+}
+
+func (m *MockSupplierService) GetByID(id int) (*model.Supplier, error) {
+	return m.GetByIDFunc(id)
+}
+
+func (m *MockSupplierService) Update(id int, s model.Supplier) (*model.Supplier, error) {
+	return m.UpdateFunc(id, s)
+}
+
+func (m *MockSupplierService) Delete(id int) error {
+	return m.DeleteFunc(id)
+}
@@ -0,0 +1,33 @@
+package handler_test
+
+import (
+	"kasir-api/internal/model"
+)
+
+type MockTaxRateService struct {
+	CreateFunc  func(rate model.TaxRate) (model.TaxRate, error)
+	GetAllFunc  func() ([]model.TaxRate, error)
+	GetByIDFunc func(id int) (model.TaxRate, error)
+	UpdateFunc  func(id int, rate model.TaxRate) (model.TaxRate, error)
+	DeleteFunc  func(id int) error
+}
+
+func (m *MockTaxRateService) Create(rate model.TaxRate) (model.TaxRate, error) {
+	return m.CreateFunc(rate)
+}
+
+func (m *MockTaxRateService) GetAll() ([]model.TaxRate, error) {
+	return m.GetAllFunc()
+}
+
+func (m *MockTaxRateService) GetByID(id int) (model.TaxRate, error) {
+	return m.GetByIDFunc(id)
+}
+
+func (m *MockTaxRateService) Update(id int, rate model.TaxRate) (model.TaxRate, error) {
+	return m.UpdateFunc(id, rate)
+}
+
+func (m *MockTaxRateService) Delete(id int) error {
+	return m.DeleteFunc(id)
+}
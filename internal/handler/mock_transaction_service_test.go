@@ -0,0 +1,54 @@
+package handler_test
+
+import (
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+)
+
+type MockTransactionService struct {
+	CheckoutFunc              func(req model.CheckoutRequest) (*model.Transaction, error)
+	GetSalesReportFunc        func(filter model.ReportFilter) (*repository.SalesReport, error)
+	GetTransactionsFunc       func(filter model.TransactionFilter) (*model.TransactionList, error)
+	EachTransactionFunc       func(filter model.TransactionFilter, fn func(model.Transaction) error) error
+	EachTransactionDetailFunc func(filter model.TransactionFilter, fn func(model.TransactionDetail) error) error
+	EachPaymentFunc           func(filter model.TransactionFilter, fn func(model.Payment) error) error
+	GetTransactionByIDFunc    func(id int) (*model.Transaction, error)
+	RefundFunc                func(transactionID int, req model.RefundRequest) (*model.Refund, error)
+	VoidFunc                  func(transactionID int, req model.VoidRequest) (*model.Refund, error)
+}
+
+func (m *MockTransactionService) Checkout(req model.CheckoutRequest) (*model.Transaction, error) {
+	return m.CheckoutFunc(req)
+}
+
+func (m *MockTransactionService) GetSalesReport(filter model.ReportFilter) (*repository.SalesReport, error) {
+	return m.GetSalesReportFunc(filter)
+}
+
+func (m *MockTransactionService) GetTransactions(filter model.TransactionFilter) (*model.TransactionList, error) {
+	return m.GetTransactionsFunc(filter)
+}
+
+func (m *MockTransactionService) EachTransaction(filter model.TransactionFilter, fn func(model.Transaction) error) error {
+	return m.EachTransactionFunc(filter, fn)
+}
+
+func (m *MockTransactionService) EachTransactionDetail(filter model.TransactionFilter, fn func(model.TransactionDetail) error) error {
+	return m.EachTransactionDetailFunc(filter, fn)
+}
+
+func (m *MockTransactionService) EachPayment(filter model.TransactionFilter, fn func(model.Payment) error) error {
+	return m.EachPaymentFunc(filter, fn)
+}
+
+func (m *MockTransactionService) GetTransactionByID(id int) (*model.Transaction, error) {
+	return m.GetTransactionByIDFunc(id)
+}
+
+func (m *MockTransactionService) Refund(transactionID int, req model.RefundRequest) (*model.Refund, error) {
+	return m.RefundFunc(transactionID, req)
+}
+
+func (m *MockTransactionService) Void(transactionID int, req model.VoidRequest) (*model.Refund, error) {
+	return m.VoidFunc(transactionID, req)
+}
@@ -0,0 +1,33 @@
+package handler_test
+
+import (
+	"kasir-api/internal/model"
+)
+
+type MockVoucherService struct {
+	CreateFunc  func(v model.Voucher) (model.Voucher, error)
+	GetAllFunc  func() ([]model.Voucher, error)
+	GetByIDFunc func(id int) (model.Voucher, error)
+	UpdateFunc  func(id int, v model.Voucher) (model.Voucher, error)
+	DeleteFunc  func(id int) error
+}
+
+func (m *MockVoucherService) Create(v model.Voucher) (model.Voucher, error) {
+	return m.CreateFunc(v)
+}
+
+func (m *MockVoucherService) GetAll() ([]model.Voucher, error) {
+	return m.GetAllFunc()
+}
+
+func (m *MockVoucherService) GetByID(id int) (model.Voucher, error) {
+	return m.GetByIDFunc(id)
+}
+
+func (m *MockVoucherService) Update(id int, v model.Voucher) (model.Voucher, error) {
+	return m.UpdateFunc(id, v)
+}
+
+func (m *MockVoucherService) Delete(id int) error {
+	return m.DeleteFunc(id)
+}
@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"kasir-api/internal/auth"
 	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
 	"kasir-api/internal/service"
 	"net/http"
 	"strconv"
@@ -10,11 +14,12 @@ import (
 )
 
 type ProductHandler struct {
-	service service.ProductService
+	service          service.ProductService
+	inventoryService service.InventoryService
 }
 
-func NewProductHandler(service service.ProductService) *ProductHandler {
-	return &ProductHandler{service: service}
+func NewProductHandler(service service.ProductService, inventoryService service.InventoryService) *ProductHandler {
+	return &ProductHandler{service: service, inventoryService: inventoryService}
 }
 
 func (h *ProductHandler) HandleProducts(w http.ResponseWriter, r *http.Request) {
@@ -38,30 +43,64 @@ func (h *ProductHandler) HandleProducts(w http.ResponseWriter, r *http.Request)
 func (h *ProductHandler) HandleProductByID(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	idStr := strings.TrimPrefix(r.URL.Path, "/products/")
+	idStr, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/products/"), "/")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
 		http.Error(w, "Invalid ID", http.StatusBadRequest)
 		return
 	}
 
-	switch r.Method {
-	case http.MethodGet:
+	switch {
+	case action == "" && r.Method == http.MethodGet:
 		h.getByID(w, r, id)
-	case http.MethodPut:
+	case action == "" && r.Method == http.MethodPut:
 		h.update(w, r, id)
-	case http.MethodDelete:
+	case action == "" && r.Method == http.MethodDelete:
 		h.delete(w, r, id)
+	case action == "stock-movements" && r.Method == http.MethodGet:
+		h.getStockMovements(w, r, id)
+	case action == "stock-adjustments" && r.Method == http.MethodPost:
+		h.adjustStock(w, r, id)
+	case action != "" && action != "stock-movements" && action != "stock-adjustments":
+		http.NotFound(w, r)
 	default:
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 	}
 }
 
+// HandleLowStock godoc
+// @Summary Get low-stock products
+// @Description Get products whose stock is below their minimum stock, furthest below first, with the quantity to reorder. Products with no minimum stock set are never listed.
+// @Tags products
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} model.Product
+// @Failure 500 {object} map[string]string
+// @Router /products/low-stock [get]
+func (h *ProductHandler) HandleLowStock(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	products, err := h.service.GetLowStock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(products)
+}
+
 // getAll godoc
 // @Summary Get all products
 // @Description Get all products with their category information. Optional filter by name using query parameter.
 // @Tags products
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param name query string false "Filter products by name (partial match, case-insensitive)"
 // @Success 200 {array} model.Product
 // @Failure 500 {object} map[string]string
@@ -82,6 +121,8 @@ func (h *ProductHandler) getAll(w http.ResponseWriter, r *http.Request) {
 // @Tags products
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param product body model.Product true "Product object"
 // @Success 201 {object} model.Product
 // @Failure 400 {object} map[string]string
@@ -92,6 +133,9 @@ func (h *ProductHandler) create(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid request payload", http.StatusBadRequest)
 		return
 	}
+	if principal := auth.PrincipalFromContext(r.Context()); principal != nil {
+		p.UserID = principal.UserID
+	}
 	created, err := h.service.Create(p)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -106,6 +150,8 @@ func (h *ProductHandler) create(w http.ResponseWriter, r *http.Request) {
 // @Description Get a single product by ID with category information
 // @Tags products
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path int true "Product ID"
 // @Success 200 {object} model.Product
 // @Failure 404 {object} map[string]string
@@ -121,11 +167,14 @@ func (h *ProductHandler) getByID(w http.ResponseWriter, r *http.Request, id int)
 
 // update godoc
 // @Summary Update a product
-// @Description Update an existing product by ID
+// @Description Update an existing product by ID. Stock is left as it is unless set_stock=true, in which case it is overwritten and the change recorded in the stock ledger as a correction; use stock adjustments for everyday stock changes.
 // @Tags products
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path int true "Product ID"
+// @Param set_stock query bool false "Overwrite stock with the value in the body"
 // @Param product body model.Product true "Product object"
 // @Success 200 {object} model.Product
 // @Failure 400 {object} map[string]string
@@ -137,6 +186,10 @@ func (h *ProductHandler) update(w http.ResponseWriter, r *http.Request, id int)
 		http.Error(w, "Invalid request payload", http.StatusBadRequest)
 		return
 	}
+	if principal := auth.PrincipalFromContext(r.Context()); principal != nil {
+		p.UserID = principal.UserID
+	}
+	p.SetStock = r.URL.Query().Get("set_stock") == "true"
 	updated, err := h.service.Update(id, p)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -149,6 +202,8 @@ func (h *ProductHandler) update(w http.ResponseWriter, r *http.Request, id int)
 // @Summary Delete a product
 // @Description Delete a product by ID
 // @Tags products
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path int true "Product ID"
 // @Success 204
 // @Failure 404 {object} map[string]string
@@ -160,3 +215,80 @@ func (h *ProductHandler) delete(w http.ResponseWriter, r *http.Request, id int)
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// getStockMovements godoc
+// @Summary Get product stock movements
+// @Description Get the stock ledger of a product, newest first: every sale, refund, adjustment, receipt and stock-take that changed its stock, with the user and document responsible
+// @Tags products
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param type query string false "Only movements of this type" Enums(initial, sale, refund, void, adjustment, receiving, stock_take)
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Success 200 {array} model.StockMovement
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /products/{id}/stock-movements [get]
+func (h *ProductHandler) getStockMovements(w http.ResponseWriter, r *http.Request, id int) {
+	q := r.URL.Query()
+	filter := model.StockMovementFilter{
+		Type:      q.Get("type"),
+		StartDate: q.Get("start_date"),
+		EndDate:   q.Get("end_date"),
+	}
+
+	movements, err := h.inventoryService.GetStockMovements(id, filter)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(movements)
+}
+
+// adjustStock godoc
+// @Summary Adjust product stock
+// @Description Change a product's stock by a signed quantity, negative to write stock off, with a reason code. The change is applied to the current stock, so it does not undo sales made since the product was loaded. Stock cannot go below zero.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Product ID"
+// @Param adjustment body model.StockAdjustmentRequest true "Adjustment; reason_code is one of damaged, expired, lost, found, internal_use, correction"
+// @Success 201 {object} model.StockMovement
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /products/{id}/stock-adjustments [post]
+func (h *ProductHandler) adjustStock(w http.ResponseWriter, r *http.Request, id int) {
+	var req model.StockAdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	if principal := auth.PrincipalFromContext(r.Context()); principal != nil {
+		req.UserID = principal.UserID
+	}
+
+	movement, err := h.inventoryService.AdjustStock(id, req)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Product not found", http.StatusNotFound)
+		return
+	case errors.Is(err, repository.ErrInvalidStockAdjustment):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(movement)
+}
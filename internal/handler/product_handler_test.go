@@ -3,8 +3,10 @@ package handler_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"kasir-api/internal/handler"
 	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -17,7 +19,7 @@ func TestCreateProduct(t *testing.T) {
 			return product, nil
 		},
 	}
-	h := handler.NewProductHandler(mockService)
+	h := handler.NewProductHandler(mockService, &MockInventoryService{})
 
 	payload := []byte(`{"name":"Smartphone", "price":5000000, "stock":10, "category_id":1}`)
 	req, err := http.NewRequest("POST", "/products", bytes.NewBuffer(payload))
@@ -44,3 +46,96 @@ func TestCreateProduct(t *testing.T) {
 		t.Errorf("expected ID 100, got %v", created.ID)
 	}
 }
+
+func TestGetLowStockProducts(t *testing.T) {
+	mockService := &MockProductService{
+		GetLowStockFunc: func() ([]model.Product, error) {
+			return []model.Product{{ID: 3, Name: "Beras 5kg", Stock: 2, MinStock: 5, ReorderQuantity: 20}}, nil
+		},
+	}
+	h := handler.NewProductHandler(mockService, &MockInventoryService{})
+
+	rr := httptest.NewRecorder()
+	h.HandleLowStock(rr, httptest.NewRequest(http.MethodGet, "/products/low-stock", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var products []model.Product
+	if err := json.Unmarshal(rr.Body.Bytes(), &products); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(products) != 1 || products[0].MinStock != 5 || products[0].ReorderQuantity != 20 {
+		t.Errorf("unexpected products: %+v", products)
+	}
+
+	rr = httptest.NewRecorder()
+	h.HandleLowStock(rr, httptest.NewRequest(http.MethodPost, "/products/low-stock", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for POST, got %d", rr.Code)
+	}
+}
+
+func TestGetStockMovements(t *testing.T) {
+	var gotID int
+	var gotFilter model.StockMovementFilter
+	inventory := &MockInventoryService{
+		GetStockMovementsFunc: func(productID int, filter model.StockMovementFilter) ([]model.StockMovement, error) {
+			gotID, gotFilter = productID, filter
+			return []model.StockMovement{
+				{ID: 2, ProductID: productID, Type: model.StockMovementSale, Quantity: -3, StockAfter: 7, ReferenceType: model.StockReferenceTransaction, ReferenceID: 41},
+			}, nil
+		},
+	}
+	h := handler.NewProductHandler(&MockProductService{}, inventory)
+
+	req, err := http.NewRequest("GET", "/products/12/stock-movements?type=sale", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleProductByID).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if gotID != 12 || gotFilter.Type != model.StockMovementSale {
+		t.Errorf("unexpected arguments: product %d, filter %+v", gotID, gotFilter)
+	}
+
+	var movements []model.StockMovement
+	if err := json.Unmarshal(rr.Body.Bytes(), &movements); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(movements) != 1 || movements[0].Quantity != -3 || movements[0].StockAfter != 7 {
+		t.Errorf("unexpected movements: %+v", movements)
+	}
+}
+
+func TestAdjustStockRejectsNegativeStock(t *testing.T) {
+	var gotReq model.StockAdjustmentRequest
+	inventory := &MockInventoryService{
+		AdjustStockFunc: func(productID int, req model.StockAdjustmentRequest) (*model.StockMovement, error) {
+			gotReq = req
+			return nil, fmt.Errorf("%w: Indomie has only 2 in stock", repository.ErrInvalidStockAdjustment)
+		},
+	}
+	h := handler.NewProductHandler(&MockProductService{}, inventory)
+
+	body := []byte(`{"quantity": -5, "reason_code": "damaged", "note": "dropped carton"}`)
+	req, err := http.NewRequest("POST", "/products/3/stock-adjustments", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleProductByID).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+	if gotReq.Quantity != -5 || gotReq.ReasonCode != model.StockReasonDamaged || gotReq.Note != "dropped carton" {
+		t.Errorf("unexpected request passed to service: %+v", gotReq)
+	}
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"encoding/json"
+	"kasir-api/internal/model"
+	"kasir-api/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type PromotionHandler struct {
+	service service.PromotionService
+}
+
+func NewPromotionHandler(service service.PromotionService) *PromotionHandler {
+	return &PromotionHandler{service: service}
+}
+
+func (h *PromotionHandler) HandlePromotions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Path != "/promotions" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *PromotionHandler) HandlePromotionByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	idStr := strings.TrimPrefix(r.URL.Path, "/promotions/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getByID(w, r, id)
+	case http.MethodPut:
+		h.update(w, r, id)
+	case http.MethodDelete:
+		h.delete(w, r, id)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getAll godoc
+// @Summary Get all promotions
+// @Description Get all promotions, including inactive and expired ones
+// @Tags promotions
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} model.Promotion
+// @Failure 500 {object} map[string]string
+// @Router /promotions [get]
+func (h *PromotionHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	promos, err := h.service.GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(promos)
+}
+
+// create godoc
+// @Summary Create a promotion
+// @Description Create a percentage, fixed_amount, buy_x_get_y, bundle or min_spend promotion. Percentages are in basis points (1000 = 10%). Promotions with active set to true are applied automatically at checkout while inside their validity window.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param promotion body model.Promotion true "Promotion object"
+// @Success 201 {object} model.Promotion
+// @Failure 400 {object} map[string]string
+// @Router /promotions [post]
+func (h *PromotionHandler) create(w http.ResponseWriter, r *http.Request) {
+	var promo model.Promotion
+	if err := json.NewDecoder(r.Body).Decode(&promo); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	created, err := h.service.Create(promo)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// getByID godoc
+// @Summary Get promotion by ID
+// @Description Get a single promotion with its items
+// @Tags promotions
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} model.Promotion
+// @Failure 404 {object} map[string]string
+// @Router /promotions/{id} [get]
+func (h *PromotionHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	promo, err := h.service.GetByID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(promo)
+}
+
+// update godoc
+// @Summary Update a promotion
+// @Description Update a promotion and replace its items. Past transactions keep the discounts they were given.
+// @Tags promotions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Promotion ID"
+// @Param promotion body model.Promotion true "Promotion object"
+// @Success 200 {object} model.Promotion
+// @Failure 400 {object} map[string]string
+// @Router /promotions/{id} [put]
+func (h *PromotionHandler) update(w http.ResponseWriter, r *http.Request, id int) {
+	var promo model.Promotion
+	if err := json.NewDecoder(r.Body).Decode(&promo); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	updated, err := h.service.Update(id, promo)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(updated)
+}
+
+// delete godoc
+// @Summary Delete a promotion
+// @Description Delete a promotion; set active to false instead to keep it for reference
+// @Tags promotions
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Promotion ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /promotions/{id} [delete]
+func (h *PromotionHandler) delete(w http.ResponseWriter, r *http.Request, id int) {
+	if err := h.service.Delete(id); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,42 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"kasir-api/internal/handler"
+	"kasir-api/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreatePromotion(t *testing.T) {
+	mockService := &MockPromotionService{
+		CreateFunc: func(promo model.Promotion) (model.Promotion, error) {
+			promo.ID = 5
+			return promo, nil
+		},
+	}
+	h := handler.NewPromotionHandler(mockService)
+
+	payload := []byte(`{"name":"Beli 2 Gratis 1","type":"buy_x_get_y","buy_qty":2,"get_qty":1,"active":true,"items":[{"category_id":3}]}`)
+	req, err := http.NewRequest("POST", "/promotions", bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandlePromotions).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+
+	var created model.Promotion
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if created.ID != 5 || created.Type != model.PromotionBuyXGetY || len(created.Items) != 1 || created.Items[0].CategoryID != 3 {
+		t.Errorf("unexpected promotion: %+v", created)
+	}
+}
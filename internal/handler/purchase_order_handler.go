@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/auth"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"kasir-api/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type PurchaseOrderHandler struct {
+	service service.PurchaseOrderService
+}
+
+func NewPurchaseOrderHandler(service service.PurchaseOrderService) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{service: service}
+}
+
+func (h *PurchaseOrderHandler) HandlePurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Path != "/purchase-orders" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *PurchaseOrderHandler) HandlePurchaseOrderByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	idStr, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/purchase-orders/"), "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.getByID(w, r, id)
+	case action == "receipts" && r.Method == http.MethodPost:
+		h.receive(w, r, id)
+	case action == "close" && r.Method == http.MethodPost:
+		h.close(w, r, id)
+	case action != "" && action != "receipts" && action != "close":
+		http.NotFound(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getAll godoc
+// @Summary Get purchase orders
+// @Description Get purchase orders, newest first, with their expected and received cost. Lines and receipts are returned by the single order endpoint.
+// @Tags purchase-orders
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param supplier_id query int false "Only orders with this supplier"
+// @Param status query string false "Only orders in this status" Enums(open, partially_received, received, closed)
+// @Success 200 {array} model.PurchaseOrder
+// @Failure 400 {object} map[string]string
+// @Router /purchase-orders [get]
+func (h *PurchaseOrderHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	filter := model.PurchaseOrderFilter{Status: r.URL.Query().Get("status")}
+	if v := r.URL.Query().Get("supplier_id"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "invalid supplier_id", http.StatusBadRequest)
+			return
+		}
+		filter.SupplierID = n
+	}
+
+	orders, err := h.service.GetAll(filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(orders)
+}
+
+// create godoc
+// @Summary Create a purchase order
+// @Description Order products from a supplier at an expected unit cost. Stock only changes when the goods are received.
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param order body model.PurchaseOrderRequest true "Supplier and items"
+// @Success 201 {object} model.PurchaseOrder
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /purchase-orders [post]
+func (h *PurchaseOrderHandler) create(w http.ResponseWriter, r *http.Request) {
+	var req model.PurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		req.CreatedBy = p.UserID
+	}
+
+	order, err := h.service.Create(req)
+	if err != nil {
+		writePurchaseOrderError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(order)
+}
+
+// getByID godoc
+// @Summary Get purchase order by ID
+// @Description Get a purchase order with its lines, the quantity received of each, and its goods receipts
+// @Tags purchase-orders
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} model.PurchaseOrder
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /purchase-orders/{id} [get]
+func (h *PurchaseOrderHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	order, err := h.service.GetByID(id)
+	if err != nil {
+		writePurchaseOrderError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(order)
+}
+
+// receive godoc
+// @Summary Receive goods
+// @Description Book a delivery against an open purchase order. The delivered units are added to stock and recorded in the stock ledger, and each product's cost price becomes the weighted average of the stock on hand and the units received. A delivery may cover part of the order; the order is marked received once every line has arrived in full.
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Purchase order ID"
+// @Param receipt body model.GoodsReceiptRequest true "Delivered items"
+// @Success 201 {object} model.GoodsReceipt
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /purchase-orders/{id}/receipts [post]
+func (h *PurchaseOrderHandler) receive(w http.ResponseWriter, r *http.Request, id int) {
+	var req model.GoodsReceiptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		req.ReceivedBy = p.UserID
+	}
+
+	receipt, err := h.service.Receive(id, req)
+	if err != nil {
+		writePurchaseOrderError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(receipt)
+}
+
+// close godoc
+// @Summary Close a purchase order
+// @Description Close an order that will not be delivered in full. No further goods can be received against it; what was already received stays in stock.
+// @Tags purchase-orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Purchase order ID"
+// @Param close body model.ClosePurchaseOrderRequest true "Reason for closing"
+// @Success 200 {object} model.PurchaseOrder
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /purchase-orders/{id}/close [post]
+func (h *PurchaseOrderHandler) close(w http.ResponseWriter, r *http.Request, id int) {
+	var req model.ClosePurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		req.ClosedBy = p.UserID
+	}
+
+	order, err := h.service.Close(id, req)
+	if err != nil {
+		writePurchaseOrderError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(order)
+}
+
+func writePurchaseOrderError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Purchase order not found", http.StatusNotFound)
+	case errors.Is(err, repository.ErrInvalidPurchaseOrder):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,78 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"kasir-api/internal/auth"
+	"kasir-api/internal/handler"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReceiveGoodsUsesPrincipal(t *testing.T) {
+	var gotID int
+	var got model.GoodsReceiptRequest
+	mockService := &MockPurchaseOrderService{
+		ReceiveFunc: func(id int, req model.GoodsReceiptRequest) (*model.GoodsReceipt, error) {
+			gotID, got = id, req
+			return &model.GoodsReceipt{
+				ID:              3,
+				PurchaseOrderID: id,
+				ReceivedBy:      req.ReceivedBy,
+				Lines:           []model.GoodsReceiptLine{{PurchaseOrderLineID: 11, ProductID: 4, ProductName: "Gula 1kg", Quantity: 20, UnitCost: 14000}},
+			}, nil
+		},
+	}
+	h := handler.NewPurchaseOrderHandler(mockService)
+
+	body := []byte(`{"note": "first delivery", "items": [{"product_id": 4, "quantity": 20}]}`)
+	req, err := http.NewRequest("POST", "/purchase-orders/9/receipts", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{Type: auth.PrincipalUser, UserID: 5}))
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandlePurchaseOrderByID).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+	if gotID != 9 || got.ReceivedBy != 5 || len(got.Items) != 1 || got.Items[0].Quantity != 20 {
+		t.Errorf("unexpected receipt request for order %d: %+v", gotID, got)
+	}
+
+	var receipt model.GoodsReceipt
+	if err := json.Unmarshal(rr.Body.Bytes(), &receipt); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(receipt.Lines) != 1 || receipt.Lines[0].UnitCost != 14000 {
+		t.Errorf("unexpected receipt: %+v", receipt)
+	}
+}
+
+func TestReceiveGoodsOnClosedOrder(t *testing.T) {
+	mockService := &MockPurchaseOrderService{
+		ReceiveFunc: func(id int, req model.GoodsReceiptRequest) (*model.GoodsReceipt, error) {
+			return nil, fmt.Errorf("%w: purchase order %d is already closed", repository.ErrInvalidPurchaseOrder, id)
+		},
+	}
+	h := handler.NewPurchaseOrderHandler(mockService)
+
+	body := []byte(`{"items": [{"product_id": 4, "quantity": 5}]}`)
+	req, err := http.NewRequest("POST", "/purchase-orders/9/receipts", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandlePurchaseOrderByID).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
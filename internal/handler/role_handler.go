@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"encoding/json"
+	"kasir-api/internal/model"
+	"kasir-api/internal/service"
+	"net/http"
+	"strings"
+)
+
+type RoleHandler struct {
+	service service.RoleService
+}
+
+func NewRoleHandler(service service.RoleService) *RoleHandler {
+	return &RoleHandler{service: service}
+}
+
+func (h *RoleHandler) HandleRoles(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Path != "/roles" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *RoleHandler) HandleRoleByName(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	name := strings.TrimPrefix(r.URL.Path, "/roles/")
+	if name == "" || strings.Contains(name, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getByName(w, r, name)
+	case http.MethodPut:
+		h.update(w, r, name)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getAll godoc
+// @Summary Get all roles
+// @Description Get all roles with the permissions granted to each
+// @Tags roles
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} model.Role
+// @Failure 500 {object} map[string]string
+// @Router /roles [get]
+func (h *RoleHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.service.GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(roles)
+}
+
+// create godoc
+// @Summary Create a role
+// @Description Create a custom role with a set of permissions
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param role body model.Role true "Role object"
+// @Success 201 {object} model.Role
+// @Failure 400 {object} map[string]string
+// @Router /roles [post]
+func (h *RoleHandler) create(w http.ResponseWriter, r *http.Request) {
+	var role model.Role
+	if err := json.NewDecoder(r.Body).Decode(&role); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	created, err := h.service.Create(role)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// getByName godoc
+// @Summary Get role by name
+// @Description Get a single role and its permissions
+// @Tags roles
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param name path string true "Role name"
+// @Success 200 {object} model.Role
+// @Failure 404 {object} map[string]string
+// @Router /roles/{name} [get]
+func (h *RoleHandler) getByName(w http.ResponseWriter, r *http.Request, name string) {
+	role, err := h.service.GetByName(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(role)
+}
+
+// update godoc
+// @Summary Update a role
+// @Description Replace a role's description and permissions
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param name path string true "Role name"
+// @Param role body model.Role true "Role object"
+// @Success 200 {object} model.Role
+// @Failure 400 {object} map[string]string
+// @Router /roles/{name} [put]
+func (h *RoleHandler) update(w http.ResponseWriter, r *http.Request, name string) {
+	var role model.Role
+	if err := json.NewDecoder(r.Body).Decode(&role); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	updated, err := h.service.Update(name, role)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(updated)
+}
@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/auth"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"kasir-api/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type ShiftHandler struct {
+	service service.ShiftService
+}
+
+func NewShiftHandler(service service.ShiftService) *ShiftHandler {
+	return &ShiftHandler{service: service}
+}
+
+func (h *ShiftHandler) HandleShifts(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Path != "/shifts" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.open(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *ShiftHandler) HandleShiftByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	idStr, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/shifts/"), "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.getByID(w, r, id)
+	case action == "cash-movements" && r.Method == http.MethodPost:
+		h.addCashMovement(w, r, id)
+	case action == "close" && r.Method == http.MethodPost:
+		h.close(w, r, id)
+	case action != "" && action != "cash-movements" && action != "close":
+		http.NotFound(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getAll godoc
+// @Summary Get shifts
+// @Description Get cashier shifts, newest first, with their running cash totals
+// @Tags shifts
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param terminal_id query string false "Only shifts at this terminal"
+// @Param status query string false "Only open or closed shifts" Enums(open, closed)
+// @Success 200 {array} model.Shift
+// @Failure 400 {object} map[string]string
+// @Router /shifts [get]
+func (h *ShiftHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	filter := model.ShiftFilter{
+		TerminalID: r.URL.Query().Get("terminal_id"),
+		Status:     r.URL.Query().Get("status"),
+	}
+	shifts, err := h.service.GetAll(filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(shifts)
+}
+
+// open godoc
+// @Summary Open a shift
+// @Description Open a cash drawer shift at the caller's terminal with a starting float. Sales and refunds at the terminal are attributed to the shift until it is closed.
+// @Tags shifts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param shift body model.OpenShiftRequest true "Opening float"
+// @Success 201 {object} model.Shift
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /shifts [post]
+func (h *ShiftHandler) open(w http.ResponseWriter, r *http.Request) {
+	var req model.OpenShiftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		req.TerminalID = p.TerminalID
+		req.CashierID = p.UserID
+	}
+
+	shift, err := h.service.Open(req)
+	if err != nil {
+		writeShiftError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(shift)
+}
+
+// getByID godoc
+// @Summary Get shift by ID
+// @Description Get a shift with its cash movements and expected cash
+// @Tags shifts
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Shift ID"
+// @Success 200 {object} model.Shift
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /shifts/{id} [get]
+func (h *ShiftHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	shift, err := h.service.GetByID(id)
+	if err != nil {
+		writeShiftError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(shift)
+}
+
+// addCashMovement godoc
+// @Summary Record a pay-in or pay-out
+// @Description Record cash put into or taken out of the drawer of an open shift
+// @Tags shifts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Shift ID"
+// @Param movement body model.CashMovementRequest true "Cash movement"
+// @Success 201 {object} model.CashMovement
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /shifts/{id}/cash-movements [post]
+func (h *ShiftHandler) addCashMovement(w http.ResponseWriter, r *http.Request, id int) {
+	var req model.CashMovementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		req.CreatedBy = p.UserID
+	}
+
+	movement, err := h.service.AddCashMovement(id, req)
+	if err != nil {
+		writeShiftError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(movement)
+}
+
+// close godoc
+// @Summary Close a shift
+// @Description Close a shift with the cash counted in the drawer. The expected cash is computed from the opening float, cash sales, refunds and pay-ins/outs, and the difference is stored as the discrepancy.
+// @Tags shifts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Shift ID"
+// @Param close body model.CloseShiftRequest true "Counted cash"
+// @Success 200 {object} model.Shift
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /shifts/{id}/close [post]
+func (h *ShiftHandler) close(w http.ResponseWriter, r *http.Request, id int) {
+	var req model.CloseShiftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		req.ClosedBy = p.UserID
+	}
+
+	shift, err := h.service.Close(id, req)
+	if err != nil {
+		writeShiftError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(shift)
+}
+
+func writeShiftError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Shift not found", http.StatusNotFound)
+	case errors.Is(err, repository.ErrInvalidShift):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
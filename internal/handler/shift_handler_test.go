@@ -0,0 +1,93 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"kasir-api/internal/auth"
+	"kasir-api/internal/handler"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpenShiftUsesPrincipalTerminal(t *testing.T) {
+	var got model.OpenShiftRequest
+	mockService := &MockShiftService{
+		OpenFunc: func(req model.OpenShiftRequest) (*model.Shift, error) {
+			got = req
+			return &model.Shift{ID: 1, TerminalID: req.TerminalID, Status: model.ShiftStatusOpen, OpeningFloat: req.OpeningFloat}, nil
+		},
+	}
+	h := handler.NewShiftHandler(mockService)
+
+	req, err := http.NewRequest("POST", "/shifts", bytes.NewBufferString(`{"opening_float":200000}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{Type: auth.PrincipalUser, UserID: 3, TerminalID: "KASIR-02"}))
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleShifts).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+	if got.TerminalID != "KASIR-02" || got.CashierID != 3 || got.OpeningFloat != 200000 {
+		t.Errorf("unexpected open request: %+v", got)
+	}
+}
+
+func TestCloseShiftReturnsDiscrepancy(t *testing.T) {
+	mockService := &MockShiftService{
+		CloseFunc: func(id int, req model.CloseShiftRequest) (*model.Shift, error) {
+			expected := 350000
+			discrepancy := req.CountedCash - expected
+			return &model.Shift{ID: id, Status: model.ShiftStatusClosed, ExpectedCash: expected, CountedCash: &req.CountedCash, Discrepancy: &discrepancy}, nil
+		},
+	}
+	h := handler.NewShiftHandler(mockService)
+
+	req, err := http.NewRequest("POST", "/shifts/4/close", bytes.NewBufferString(`{"counted_cash":345000}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleShiftByID).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	var shift model.Shift
+	if err := json.Unmarshal(rr.Body.Bytes(), &shift); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if shift.Discrepancy == nil || *shift.Discrepancy != -5000 {
+		t.Errorf("expected discrepancy -5000, got %v", shift.Discrepancy)
+	}
+}
+
+func TestCloseShiftAlreadyClosed(t *testing.T) {
+	mockService := &MockShiftService{
+		CloseFunc: func(id int, req model.CloseShiftRequest) (*model.Shift, error) {
+			return nil, fmt.Errorf("%w: shift %d is already closed", repository.ErrInvalidShift, id)
+		},
+	}
+	h := handler.NewShiftHandler(mockService)
+
+	req, err := http.NewRequest("POST", "/shifts/4/close", bytes.NewBufferString(`{"counted_cash":100000}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleShiftByID).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/auth"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"kasir-api/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type StockTakeHandler struct {
+	service service.StockTakeService
+}
+
+func NewStockTakeHandler(service service.StockTakeService) *StockTakeHandler {
+	return &StockTakeHandler{service: service}
+}
+
+func (h *StockTakeHandler) HandleStockTakes(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Path != "/stock-takes" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.open(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *StockTakeHandler) HandleStockTakeByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	idStr, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/stock-takes/"), "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.getByID(w, r, id)
+	case action == "counts" && r.Method == http.MethodPost:
+		h.submitCounts(w, r, id)
+	case action == "post" && r.Method == http.MethodPost:
+		h.post(w, r, id)
+	case action == "cancel" && r.Method == http.MethodPost:
+		h.cancel(w, r, id)
+	case action != "" && action != "counts" && action != "post" && action != "cancel":
+		http.NotFound(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getAll godoc
+// @Summary Get stock takes
+// @Description Get stock takes, newest first, with how many of their products have been counted
+// @Tags stock-takes
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param status query string false "Only stock takes in this status" Enums(open, posted, cancelled)
+// @Success 200 {array} model.StockTake
+// @Failure 400 {object} map[string]string
+// @Router /stock-takes [get]
+func (h *StockTakeHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	takes, err := h.service.GetAll(model.StockTakeFilter{Status: r.URL.Query().Get("status")})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(takes)
+}
+
+// open godoc
+// @Summary Open a stock take
+// @Description Open a stock take (stock opname) for one category, or for the whole store when no category_id is given. The stock of every product in scope is snapshotted. Only one session can be open for a product at a time.
+// @Tags stock-takes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param stock_take body model.OpenStockTakeRequest true "Scope of the count"
+// @Success 201 {object} model.StockTake
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /stock-takes [post]
+func (h *StockTakeHandler) open(w http.ResponseWriter, r *http.Request) {
+	var req model.OpenStockTakeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		req.OpenedBy = p.UserID
+	}
+
+	take, err := h.service.Open(req)
+	if err != nil {
+		writeStockTakeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(take)
+}
+
+// getByID godoc
+// @Summary Get stock take by ID
+// @Description Get a stock take with every product in scope, the counts submitted for it and its variance against the system stock at the time it was counted
+// @Tags stock-takes
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Stock take ID"
+// @Success 200 {object} model.StockTake
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /stock-takes/{id} [get]
+func (h *StockTakeHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	take, err := h.service.GetByID(id)
+	if err != nil {
+		writeStockTakeError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(take)
+}
+
+// submitCounts godoc
+// @Summary Submit counted quantities
+// @Description Submit counted quantities for products of an open stock take. Several devices can count at once; their counts of a product are added up, and a device counting a product again replaces its earlier count.
+// @Tags stock-takes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Stock take ID"
+// @Param counts body model.StockCountRequest true "Counted quantities"
+// @Success 200 {object} model.StockTake
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /stock-takes/{id}/counts [post]
+func (h *StockTakeHandler) submitCounts(w http.ResponseWriter, r *http.Request, id int) {
+	var req model.StockCountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		req.CountedBy = p.UserID
+		req.Device = p.TerminalID
+		if req.Device == "" {
+			req.Device = p.Username
+		}
+	}
+
+	take, err := h.service.SubmitCounts(id, req)
+	if err != nil {
+		writeStockTakeError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(take)
+}
+
+// post godoc
+// @Summary Post a stock take
+// @Description Close an open stock take and adjust the stock of every counted product by its variance, recorded in the stock ledger as stock_take movements. Products that were not counted are left unchanged.
+// @Tags stock-takes
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Stock take ID"
+// @Success 200 {object} model.StockTake
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /stock-takes/{id}/post [post]
+func (h *StockTakeHandler) post(w http.ResponseWriter, r *http.Request, id int) {
+	var userID int
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		userID = p.UserID
+	}
+
+	take, err := h.service.Post(id, userID)
+	if err != nil {
+		writeStockTakeError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(take)
+}
+
+// cancel godoc
+// @Summary Cancel a stock take
+// @Description Close an open stock take without changing any stock
+// @Tags stock-takes
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Stock take ID"
+// @Success 200 {object} model.StockTake
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /stock-takes/{id}/cancel [post]
+func (h *StockTakeHandler) cancel(w http.ResponseWriter, r *http.Request, id int) {
+	var userID int
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		userID = p.UserID
+	}
+
+	take, err := h.service.Cancel(id, userID)
+	if err != nil {
+		writeStockTakeError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(take)
+}
+
+func writeStockTakeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Stock take not found", http.StatusNotFound)
+	case errors.Is(err, repository.ErrInvalidStockTake):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,75 @@
+package handler_test
+
+import (
+	"bytes"
+	"fmt"
+	"kasir-api/internal/auth"
+	"kasir-api/internal/handler"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSubmitCountsUsesDevice(t *testing.T) {
+	tests := []struct {
+		name       string
+		principal  *auth.Principal
+		wantDevice string
+	}{
+		{"terminal", &auth.Principal{Type: auth.PrincipalUser, UserID: 4, Username: "rina", TerminalID: "KASIR-03"}, "KASIR-03"},
+		{"no terminal", &auth.Principal{Type: auth.PrincipalUser, UserID: 4, Username: "rina"}, "rina"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got model.StockCountRequest
+			mockService := &MockStockTakeService{
+				SubmitCountsFunc: func(id int, req model.StockCountRequest) (*model.StockTake, error) {
+					got = req
+					return &model.StockTake{ID: id, Status: model.StockTakeStatusOpen}, nil
+				},
+			}
+			h := handler.NewStockTakeHandler(mockService)
+
+			body := []byte(`{"items": [{"product_id": 8, "quantity": 12}]}`)
+			req, err := http.NewRequest("POST", "/stock-takes/2/counts", bytes.NewBuffer(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = req.WithContext(auth.WithPrincipal(req.Context(), tt.principal))
+
+			rr := httptest.NewRecorder()
+			http.HandlerFunc(h.HandleStockTakeByID).ServeHTTP(rr, req)
+
+			if status := rr.Code; status != http.StatusOK {
+				t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+			}
+			if got.Device != tt.wantDevice || got.CountedBy != 4 || len(got.Items) != 1 || got.Items[0].Quantity != 12 {
+				t.Errorf("unexpected count request: %+v", got)
+			}
+		})
+	}
+}
+
+func TestPostStockTakeAlreadyPosted(t *testing.T) {
+	mockService := &MockStockTakeService{
+		PostFunc: func(id int, userID int) (*model.StockTake, error) {
+			return nil, fmt.Errorf("%w: stock take %d is already posted", repository.ErrInvalidStockTake, id)
+		},
+	}
+	h := handler.NewStockTakeHandler(mockService)
+
+	req, err := http.NewRequest("POST", "/stock-takes/2/post", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleStockTakeByID).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"kasir-api/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type SupplierHandler struct {
+	service service.SupplierService
+}
+
+func NewSupplierHandler(service service.SupplierService) *SupplierHandler {
+	return &SupplierHandler{service: service}
+}
+
+func (h *SupplierHandler) HandleSuppliers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Path != "/suppliers" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *SupplierHandler) HandleSupplierByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	idStr, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/suppliers/"), "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.getByID(w, r, id)
+	case action == "" && r.Method == http.MethodPut:
+		h.update(w, r, id)
+	case action == "" && r.Method == http.MethodDelete:
+		h.delete(w, r, id)
+	case action != "":
+		http.NotFound(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getAll godoc
+// @Summary Get suppliers
+// @Description Get suppliers by name, optionally searching by name
+// @Tags suppliers
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param search query string false "Part of the supplier name"
+// @Success 200 {array} model.Supplier
+// @Failure 500 {object} map[string]string
+// @Router /suppliers [get]
+func (h *SupplierHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	suppliers, err := h.service.GetAll(r.URL.Query().Get("search"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(suppliers)
+}
+
+// create godoc
+// @Summary Create a supplier
+// @Description Create a supplier to place purchase orders with
+// @Tags suppliers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param supplier body model.Supplier true "Supplier object"
+// @Success 201 {object} model.Supplier
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /suppliers [post]
+func (h *SupplierHandler) create(w http.ResponseWriter, r *http.Request) {
+	var s model.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	created, err := h.service.Create(s)
+	if err != nil {
+		writeSupplierError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// getByID godoc
+// @Summary Get supplier by ID
+// @Description Get a single supplier by ID
+// @Tags suppliers
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Supplier ID"
+// @Success 200 {object} model.Supplier
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /suppliers/{id} [get]
+func (h *SupplierHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	s, err := h.service.GetByID(id)
+	if err != nil {
+		writeSupplierError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(s)
+}
+
+// update godoc
+// @Summary Update a supplier
+// @Description Update a supplier's name and contact details
+// @Tags suppliers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Supplier ID"
+// @Param supplier body model.Supplier true "Supplier object"
+// @Success 200 {object} model.Supplier
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /suppliers/{id} [put]
+func (h *SupplierHandler) update(w http.ResponseWriter, r *http.Request, id int) {
+	var s model.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	updated, err := h.service.Update(id, s)
+	if err != nil {
+		writeSupplierError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(updated)
+}
+
+// delete godoc
+// @Summary Delete a supplier
+// @Description Delete a supplier that has no purchase orders
+// @Tags suppliers
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Supplier ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /suppliers/{id} [delete]
+func (h *SupplierHandler) delete(w http.ResponseWriter, r *http.Request, id int) {
+	if err := h.service.Delete(id); err != nil {
+		writeSupplierError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeSupplierError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Supplier not found", http.StatusNotFound)
+	case errors.Is(err, repository.ErrInvalidSupplier):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
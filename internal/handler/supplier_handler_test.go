@@ -0,0 +1,31 @@
+package handler_test
+
+import (
+	"fmt"
+	"kasir-api/internal/handler"
+	"kasir-api/internal/repository"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDeleteSupplierWithPurchaseOrders(t *testing.T) {
+	mockService := &MockSupplierService{
+		DeleteFunc: func(id int) error {
+			return fmt.Errorf("%w: supplier %d has purchase orders", repository.ErrInvalidSupplier, id)
+		},
+	}
+	h := handler.NewSupplierHandler(mockService)
+
+	req, err := http.NewRequest("DELETE", "/suppliers/2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleSupplierByID).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"encoding/json"
+	"kasir-api/internal/model"
+	"kasir-api/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type TaxRateHandler struct {
+	service service.TaxRateService
+}
+
+func NewTaxRateHandler(service service.TaxRateService) *TaxRateHandler {
+	return &TaxRateHandler{service: service}
+}
+
+func (h *TaxRateHandler) HandleTaxRates(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Path != "/tax-rates" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *TaxRateHandler) HandleTaxRateByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	idStr := strings.TrimPrefix(r.URL.Path, "/tax-rates/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getByID(w, r, id)
+	case http.MethodPut:
+		h.update(w, r, id)
+	case http.MethodDelete:
+		h.delete(w, r, id)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getAll godoc
+// @Summary Get all tax rates
+// @Description Get all configured tax rates
+// @Tags tax-rates
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} model.TaxRate
+// @Failure 500 {object} map[string]string
+// @Router /tax-rates [get]
+func (h *TaxRateHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.service.GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(rates)
+}
+
+// create godoc
+// @Summary Create a tax rate
+// @Description Create a tax rate. rate_bp is in basis points (1100 = 11%); inclusive rates are already part of the product price.
+// @Tags tax-rates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param tax_rate body model.TaxRate true "Tax rate object"
+// @Success 201 {object} model.TaxRate
+// @Failure 400 {object} map[string]string
+// @Router /tax-rates [post]
+func (h *TaxRateHandler) create(w http.ResponseWriter, r *http.Request) {
+	var rate model.TaxRate
+	if err := json.NewDecoder(r.Body).Decode(&rate); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	created, err := h.service.Create(rate)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// getByID godoc
+// @Summary Get tax rate by ID
+// @Description Get a single tax rate by ID
+// @Tags tax-rates
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Tax rate ID"
+// @Success 200 {object} model.TaxRate
+// @Failure 404 {object} map[string]string
+// @Router /tax-rates/{id} [get]
+func (h *TaxRateHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	rate, err := h.service.GetByID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(rate)
+}
+
+// update godoc
+// @Summary Update a tax rate
+// @Description Update a tax rate. Past transactions keep the rate they were sold with.
+// @Tags tax-rates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Tax rate ID"
+// @Param tax_rate body model.TaxRate true "Tax rate object"
+// @Success 200 {object} model.TaxRate
+// @Failure 400 {object} map[string]string
+// @Router /tax-rates/{id} [put]
+func (h *TaxRateHandler) update(w http.ResponseWriter, r *http.Request, id int) {
+	var rate model.TaxRate
+	if err := json.NewDecoder(r.Body).Decode(&rate); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	updated, err := h.service.Update(id, rate)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(updated)
+}
+
+// delete godoc
+// @Summary Delete a tax rate
+// @Description Delete a tax rate; products and categories using it become untaxed
+// @Tags tax-rates
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Tax rate ID"
+// @Success 204
+// @Failure 404 {object} map[string]string
+// @Router /tax-rates/{id} [delete]
+func (h *TaxRateHandler) delete(w http.ResponseWriter, r *http.Request, id int) {
+	if err := h.service.Delete(id); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,42 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"kasir-api/internal/handler"
+	"kasir-api/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateTaxRate(t *testing.T) {
+	mockService := &MockTaxRateService{
+		CreateFunc: func(rate model.TaxRate) (model.TaxRate, error) {
+			rate.ID = 2
+			return rate, nil
+		},
+	}
+	h := handler.NewTaxRateHandler(mockService)
+
+	payload := []byte(`{"name":"PPN 11%","rate_bp":1100,"inclusive":true}`)
+	req, err := http.NewRequest("POST", "/tax-rates", bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleTaxRates).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+
+	var created model.TaxRate
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if created.ID != 2 || created.Rate != 1100 || !created.Inclusive {
+		t.Errorf("unexpected tax rate: %+v", created)
+	}
+}
@@ -1,20 +1,28 @@
 package handler
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"kasir-api/internal/auth"
+	"kasir-api/internal/export"
 	"kasir-api/internal/model"
+	"kasir-api/internal/payment"
+	"kasir-api/internal/receipt"
+	"kasir-api/internal/repository"
 	"kasir-api/internal/service"
 	"net/http"
+	"strconv"
 	"strings"
-	"time"
 )
 
 type TransactionHandler struct {
-	service service.TransactionService
+	service  service.TransactionService
+	receipts service.ReceiptService
 }
 
-func NewTransactionHandler(service service.TransactionService) *TransactionHandler {
-	return &TransactionHandler{service: service}
+func NewTransactionHandler(service service.TransactionService, receipts service.ReceiptService) *TransactionHandler {
+	return &TransactionHandler{service: service, receipts: receipts}
 }
 
 func (h *TransactionHandler) HandleReport(w http.ResponseWriter, r *http.Request) {
@@ -31,13 +39,57 @@ func (h *TransactionHandler) HandleReport(w http.ResponseWriter, r *http.Request
 	}
 }
 
+func (h *TransactionHandler) HandleTransactions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Path != "/transactions" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *TransactionHandler) HandleTransactionByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	idStr, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/transactions/"), "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		h.getByID(w, r, id)
+	case action == "refunds" && r.Method == http.MethodPost:
+		h.refund(w, r, id)
+	case action == "void" && r.Method == http.MethodPost:
+		h.void(w, r, id)
+	case action == "receipt" && r.Method == http.MethodPost:
+		h.receipt(w, r, id)
+	case action != "" && action != "refunds" && action != "void" && action != "receipt":
+		http.NotFound(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
 // HandleCheckout godoc
 // @Summary Process checkout/transaction
-// @Description Create a new transaction with multiple items, calculate total, update stock
+// @Description Create a new transaction with multiple items, calculate total, update stock. Items need a quantity greater than zero; a product listed more than once is taken from stock once for the combined quantity. Pay with one or more payments; cash overpayment is returned as change. Active promotions are applied automatically before tax, followed by voucher_code if given; vouchers limited per customer need customer_ref or customer_id, and with customer_id the customer_ref, if given, must be their member number. With customer_id the customer earns loyalty points and can pay with the points method. Without payments the sale is recorded as exact cash. Fails once today has been closed with a Z report.
 // @Tags transactions
 // @Accept json
 // @Produce json
-// @Param items body model.CheckoutRequest true "Checkout items"
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param items body model.CheckoutRequest true "Checkout items and payments"
 // @Success 201 {object} model.Transaction
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -69,7 +121,18 @@ func (h *TransactionHandler) checkout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	transaction, err := h.service.Checkout(req.Items)
+	// The cashier and till are whoever is authenticated, never the request body
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		req.CashierID = p.UserID
+		req.TerminalID = p.TerminalID
+	}
+
+	transaction, err := h.service.Checkout(req)
+	if errors.Is(err, repository.ErrInvalidCheckout) || errors.Is(err, payment.ErrInvalidPayment) || errors.Is(err, repository.ErrInvalidVoucher) ||
+		errors.Is(err, repository.ErrInvalidCustomer) || errors.Is(err, repository.ErrDayClosed) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -82,44 +145,361 @@ func (h *TransactionHandler) checkout(w http.ResponseWriter, r *http.Request) {
 
 // getReport godoc
 // @Summary Get sales report
-// @Description Get sales report for a date range. Use /report/hari-ini for today's report.
+// @Description Get sales report for a date range. Dates are business days in the store timezone, ending at the configured day cutoff; both default to the current business day. Use /report/hari-ini for today's report. Besides the totals it lists the top products by quantity and by revenue, revenue per category, the average basket and items per transaction, and a revenue time series by hour or day. Filter by cashier or terminal, or set group_by for a per-cashier breakdown or a gross profit breakdown per product, category or day. Gross profit is net sales before tax less the cost of the goods at the time they were sold. Set format (or the Accept header) to csv, xlsx or pdf to download the report instead: CSV has one block per section, XLSX one sheet per section, and PDF is a printable summary.
 // @Tags reports
-// @Produce json
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param start_date query string false "Start date (YYYY-MM-DD)"
 // @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param cashier_id query int false "Only sales rung up by this user"
+// @Param terminal_id query string false "Only sales made at this terminal"
+// @Param group_by query string false "Add per_kasir, per_produk, per_kategori or per_hari" Enums(cashier, product, category, day)
+// @Param top query int false "Number of products in the top lists (default 5, max 50)"
+// @Param interval query string false "Time series buckets; hour for a single day, otherwise day by default. Hourly series cover at most 31 days" Enums(hour, day)
+// @Param format query string false "Response format; defaults to the Accept header, then json" Enums(json, csv, xlsx, pdf)
 // @Success 200 {object} repository.SalesReport
+// @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /report [get]
 func (h *TransactionHandler) getReport(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/report")
 
+	format, err := exportFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Empty dates mean the current business day
 	var startDate, endDate string
-	today := time.Now().Format("2006-01-02")
-
-	if path == "/hari-ini" {
-		// Today's report
-		startDate = today
-		endDate = today
-	} else {
-		// Date range from query params
+	if path != "/hari-ini" {
 		startDate = r.URL.Query().Get("start_date")
 		endDate = r.URL.Query().Get("end_date")
+	}
 
-		// Default to today if not provided
-		if startDate == "" {
-			startDate = today
+	filter := model.ReportFilter{
+		StartDate:  startDate,
+		EndDate:    endDate,
+		TerminalID: r.URL.Query().Get("terminal_id"),
+		GroupBy:    r.URL.Query().Get("group_by"),
+		Interval:   r.URL.Query().Get("interval"),
+	}
+	if v := r.URL.Query().Get("cashier_id"); v != "" {
+		cashierID, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "invalid cashier_id", http.StatusBadRequest)
+			return
 		}
-		if endDate == "" {
-			endDate = today
+		filter.CashierID = cashierID
+	}
+	if v := r.URL.Query().Get("top"); v != "" {
+		top, err := strconv.Atoi(v)
+		if err != nil || top < 1 {
+			http.Error(w, "top must be a positive number", http.StatusBadRequest)
+			return
 		}
+		filter.Top = top
+	}
+	if filter.Interval != "" && filter.Interval != model.ReportIntervalHour && filter.Interval != model.ReportIntervalDay {
+		http.Error(w, "interval must be 'hour' or 'day'", http.StatusBadRequest)
+		return
+	}
+	switch filter.GroupBy {
+	case "", model.ReportGroupByCashier, model.ReportGroupByProduct, model.ReportGroupByCategory, model.ReportGroupByDay:
+	default:
+		http.Error(w, "group_by must be one of cashier, product, category or day", http.StatusBadRequest)
+		return
 	}
 
-	report, err := h.service.GetSalesReport(startDate, endDate)
+	report, err := h.service.GetSalesReport(filter)
+	if errors.Is(err, service.ErrInvalidFilter) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if format != export.FormatJSON {
+		exportSalesReport(w, format, report)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(report)
 }
+
+// getAll godoc
+// @Summary Get transaction history
+// @Description Get a paginated list of transactions with their details, newest first. Optional filters by date range, amount range, product, cashier, terminal, shift, customer and store, and search by invoice number. Set format (or the Accept header) to csv, xlsx or pdf to download every matching transaction instead, ignoring page and limit: CSV is streamed with one row per transaction, XLSX adds sheets with the items and payments, and PDF is a printable listing with totals.
+// @Tags transactions
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param min_amount query int false "Minimum total amount"
+// @Param max_amount query int false "Maximum total amount"
+// @Param product_id query int false "Only transactions containing this product"
+// @Param cashier_id query int false "Only transactions rung up by this user"
+// @Param terminal_id query string false "Only transactions made at this terminal"
+// @Param shift_id query int false "Only transactions made during this shift"
+// @Param customer_id query int false "Only transactions of this customer"
+// @Param invoice query string false "Only transactions whose invoice number contains this text"
+// @Param store_code query string false "Only transactions of this store"
+// @Param page query int false "Page number (default 1)"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param format query string false "Response format; defaults to the Accept header, then json" Enums(json, csv, xlsx, pdf)
+// @Success 200 {object} model.TransactionList
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /transactions [get]
+func (h *TransactionHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseTransactionFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	format, err := exportFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if format != export.FormatJSON {
+		h.exportTransactions(w, format, filter)
+		return
+	}
+
+	list, err := h.service.GetTransactions(filter)
+	if errors.Is(err, service.ErrInvalidFilter) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(list)
+}
+
+// getByID godoc
+// @Summary Get transaction by ID
+// @Description Get a single transaction with its detail rows and product names
+// @Tags transactions
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Transaction ID"
+// @Success 200 {object} model.Transaction
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /transactions/{id} [get]
+func (h *TransactionHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	transaction, err := h.service.GetTransactionByID(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Transaction not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(transaction)
+}
+
+// receipt godoc
+// @Summary Print a transaction receipt
+// @Description Render the receipt of a transaction with the configured store header and footer. format text gives plain text 32 or 48 columns wide, escpos a raw ESC/POS byte stream to send to a thermal printer, and html a page to send by email. width defaults to the configured receipt width. Every text and ESC/POS receipt is logged as a print, and all but the first are marked as reprints with their print number; html copies are not logged and never marked. Needs the checkout permission.
+// @Tags transactions
+// @Produce plain
+// @Produce octet-stream
+// @Produce html
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Transaction ID"
+// @Param format query string false "Receipt format" Enums(text, escpos, html)
+// @Param width query int false "Characters per line" Enums(32, 48)
+// @Success 200 {string} string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /transactions/{id}/receipt [post]
+func (h *TransactionHandler) receipt(w http.ResponseWriter, r *http.Request, id int) {
+	q := r.URL.Query()
+	req := model.ReceiptRequest{Format: q.Get("format")}
+
+	var contentType string
+	switch req.Format {
+	case "", model.ReceiptFormatText:
+		req.Format = model.ReceiptFormatText
+		contentType = "text/plain; charset=utf-8"
+	case model.ReceiptFormatESCPOS:
+		contentType = "application/octet-stream"
+	case model.ReceiptFormatHTML:
+		contentType = "text/html; charset=utf-8"
+	default:
+		http.Error(w, "Invalid format", http.StatusBadRequest)
+		return
+	}
+
+	if v := q.Get("width"); v != "" {
+		width, err := strconv.Atoi(v)
+		if err != nil || !receipt.ValidWidth(width) {
+			http.Error(w, "Invalid width", http.StatusBadRequest)
+			return
+		}
+		req.Width = width
+	}
+
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		req.PrintedBy = p.UserID
+	}
+
+	out, err := h.receipts.PrintReceipt(id, req)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Transaction not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Write(out)
+}
+
+func parseTransactionFilter(r *http.Request) (model.TransactionFilter, error) {
+	q := r.URL.Query()
+	filter := model.TransactionFilter{
+		StartDate:  q.Get("start_date"),
+		EndDate:    q.Get("end_date"),
+		TerminalID: q.Get("terminal_id"),
+		Invoice:    q.Get("invoice"),
+		StoreCode:  q.Get("store_code"),
+	}
+
+	intParams := []struct {
+		name string
+		dest *int
+	}{
+		{"product_id", &filter.ProductID},
+		{"cashier_id", &filter.CashierID},
+		{"shift_id", &filter.ShiftID},
+		{"customer_id", &filter.CustomerID},
+		{"page", &filter.Page},
+		{"limit", &filter.Limit},
+	}
+	for _, p := range intParams {
+		if v := q.Get(p.name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return filter, errors.New("invalid " + p.name)
+			}
+			*p.dest = n
+		}
+	}
+
+	if v := q.Get("min_amount"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, errors.New("invalid min_amount")
+		}
+		filter.MinAmount = &n
+	}
+	if v := q.Get("max_amount"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, errors.New("invalid max_amount")
+		}
+		filter.MaxAmount = &n
+	}
+
+	return filter, nil
+}
+
+// refund godoc
+// @Summary Refund a transaction
+// @Description Refund some or all lines of a transaction and restore their stock. Leave items empty to refund everything still refundable. The money goes back the way the sale was paid, split over its payment methods, and only the cash part is taken from the drawer. Points spent on the sale are returned to the customer's balance. Refunds count on the day they are issued and fail once today has been closed with a Z report.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Transaction ID"
+// @Param refund body model.RefundRequest true "Refund request"
+// @Success 201 {object} model.Refund
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /transactions/{id}/refunds [post]
+func (h *TransactionHandler) refund(w http.ResponseWriter, r *http.Request, id int) {
+	var req model.RefundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		req.TerminalID = p.TerminalID
+		req.UserID = p.UserID
+	}
+
+	refund, err := h.service.Refund(id, req)
+	if err != nil {
+		writeRefundError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(refund)
+}
+
+// void godoc
+// @Summary Void a transaction
+// @Description Cancel a transaction by refunding everything still refundable and restoring stock. A voucher used on the sale no longer counts against its usage limits. Fails once today has been closed with a Z report.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Transaction ID"
+// @Param void body model.VoidRequest true "Void reason"
+// @Success 201 {object} model.Refund
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /transactions/{id}/void [post]
+func (h *TransactionHandler) void(w http.ResponseWriter, r *http.Request, id int) {
+	var req model.VoidRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		req.TerminalID = p.TerminalID
+		req.UserID = p.UserID
+	}
+
+	refund, err := h.service.Void(id, req)
+	if err != nil {
+		writeRefundError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(refund)
+}
+
+func writeRefundError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Transaction not found", http.StatusNotFound)
+	case errors.Is(err, repository.ErrInvalidRefund), errors.Is(err, repository.ErrDayClosed):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,615 @@
+package handler_test
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kasir-api/internal/auth"
+	"kasir-api/internal/handler"
+	"kasir-api/internal/model"
+	"kasir-api/internal/payment"
+	"kasir-api/internal/repository"
+	"kasir-api/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestGetTransactionsParsesFilter(t *testing.T) {
+	var got model.TransactionFilter
+	mockService := &MockTransactionService{
+		GetTransactionsFunc: func(filter model.TransactionFilter) (*model.TransactionList, error) {
+			got = filter
+			return &model.TransactionList{Data: []model.Transaction{{ID: 7}}, Page: 2, Limit: 10, Total: 11}, nil
+		},
+	}
+	h := handler.NewTransactionHandler(mockService, &MockReceiptService{})
+
+	req, err := http.NewRequest("GET", "/transactions?start_date=2024-01-01&end_date=2024-01-31&min_amount=1000&max_amount=50000&product_id=3&invoice=20240105&store_code=JKT01&page=2&limit=10", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handlerFunc := http.HandlerFunc(h.HandleTransactions)
+	handlerFunc.ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+
+	if got.StartDate != "2024-01-01" || got.EndDate != "2024-01-31" {
+		t.Errorf("unexpected date range: %q - %q", got.StartDate, got.EndDate)
+	}
+	if got.MinAmount == nil || *got.MinAmount != 1000 || got.MaxAmount == nil || *got.MaxAmount != 50000 {
+		t.Errorf("unexpected amount range: %v - %v", got.MinAmount, got.MaxAmount)
+	}
+	if got.ProductID != 3 || got.Page != 2 || got.Limit != 10 {
+		t.Errorf("unexpected product/page/limit: %d/%d/%d", got.ProductID, got.Page, got.Limit)
+	}
+	if got.Invoice != "20240105" || got.StoreCode != "JKT01" {
+		t.Errorf("unexpected invoice search: %q in store %q", got.Invoice, got.StoreCode)
+	}
+
+	var list model.TransactionList
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if list.Total != 11 || len(list.Data) != 1 {
+		t.Errorf("unexpected list: %+v", list)
+	}
+}
+
+func TestGetTransactionsRejectsInvalidAmount(t *testing.T) {
+	h := handler.NewTransactionHandler(&MockTransactionService{}, &MockReceiptService{})
+
+	req, err := http.NewRequest("GET", "/transactions?min_amount=abc", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleTransactions).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestGetTransactionsStatusByError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"invalid filter", fmt.Errorf("%w: start_date cannot be after end_date", service.ErrInvalidFilter), http.StatusBadRequest},
+		{"database failure", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockTransactionService{
+				GetTransactionsFunc: func(filter model.TransactionFilter) (*model.TransactionList, error) {
+					return nil, tt.err
+				},
+			}
+			h := handler.NewTransactionHandler(mockService, &MockReceiptService{})
+
+			rr := httptest.NewRecorder()
+			h.HandleTransactions(rr, httptest.NewRequest(http.MethodGet, "/transactions", nil))
+			if rr.Code != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, rr.Code)
+			}
+		})
+	}
+}
+
+func TestGetTransactionByIDNotFound(t *testing.T) {
+	mockService := &MockTransactionService{
+		GetTransactionByIDFunc: func(id int) (*model.Transaction, error) {
+			return nil, sql.ErrNoRows
+		},
+	}
+	h := handler.NewTransactionHandler(mockService, &MockReceiptService{})
+
+	req, err := http.NewRequest("GET", "/transactions/99", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleTransactionByID).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusNotFound)
+	}
+}
+
+func TestRefundTransaction(t *testing.T) {
+	var gotID int
+	var gotReq model.RefundRequest
+	mockService := &MockTransactionService{
+		RefundFunc: func(transactionID int, req model.RefundRequest) (*model.Refund, error) {
+			gotID = transactionID
+			gotReq = req
+			return &model.Refund{ID: 1, TransactionID: transactionID, Type: model.RefundTypeRefund, TotalAmount: 5000}, nil
+		},
+	}
+	h := handler.NewTransactionHandler(mockService, &MockReceiptService{})
+
+	payload := []byte(`{"reason":"damaged", "items":[{"transaction_detail_id":4, "quantity":1}]}`)
+	req, err := http.NewRequest("POST", "/transactions/12/refunds", bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleTransactionByID).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+	if gotID != 12 || len(gotReq.Items) != 1 || gotReq.Items[0].TransactionDetailID != 4 {
+		t.Errorf("unexpected refund call: id=%d req=%+v", gotID, gotReq)
+	}
+}
+
+func TestVoidTransactionInvalidRefund(t *testing.T) {
+	mockService := &MockTransactionService{
+		VoidFunc: func(transactionID int, req model.VoidRequest) (*model.Refund, error) {
+			return nil, fmt.Errorf("%w: already refunded", repository.ErrInvalidRefund)
+		},
+	}
+	h := handler.NewTransactionHandler(mockService, &MockReceiptService{})
+
+	req, err := http.NewRequest("POST", "/transactions/12/void", bytes.NewBufferString(`{"reason":"wrong item"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleTransactionByID).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestPrintReceipt(t *testing.T) {
+	var gotID int
+	var got model.ReceiptRequest
+	mockReceipts := &MockReceiptService{
+		PrintReceiptFunc: func(transactionID int, req model.ReceiptRequest) ([]byte, error) {
+			gotID = transactionID
+			got = req
+			return []byte("\x1b@STRUK"), nil
+		},
+	}
+	h := handler.NewTransactionHandler(&MockTransactionService{}, mockReceipts)
+
+	req := httptest.NewRequest(http.MethodPost, "/transactions/7/receipt?format=escpos&width=48", nil)
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{Type: auth.PrincipalUser, UserID: 5}))
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleTransactionByID).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/octet-stream" {
+		t.Errorf("expected an octet stream, got %q", ct)
+	}
+	if rr.Body.String() != "\x1b@STRUK" {
+		t.Errorf("unexpected body %q", rr.Body.String())
+	}
+	if gotID != 7 || got.Format != model.ReceiptFormatESCPOS || got.Width != 48 || got.PrintedBy != 5 {
+		t.Errorf("unexpected request for transaction %d: %+v", gotID, got)
+	}
+}
+
+func TestPrintReceiptRejectsInvalidParams(t *testing.T) {
+	h := handler.NewTransactionHandler(&MockTransactionService{}, &MockReceiptService{})
+
+	for _, query := range []string{"format=pdf", "width=40", "width=abc"} {
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(h.HandleTransactionByID).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/transactions/7/receipt?"+query, nil))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: got status %v want %v", query, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestPrintReceiptRequiresPost(t *testing.T) {
+	h := handler.NewTransactionHandler(&MockTransactionService{}, &MockReceiptService{
+		PrintReceiptFunc: func(transactionID int, req model.ReceiptRequest) ([]byte, error) {
+			t.Error("a GET must not print a receipt")
+			return nil, nil
+		},
+	})
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleTransactionByID).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/transactions/7/receipt", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestPrintReceiptNotFound(t *testing.T) {
+	mockReceipts := &MockReceiptService{
+		PrintReceiptFunc: func(transactionID int, req model.ReceiptRequest) ([]byte, error) {
+			if req.Format != model.ReceiptFormatText {
+				t.Errorf("expected text by default, got %q", req.Format)
+			}
+			return nil, sql.ErrNoRows
+		},
+	}
+	h := handler.NewTransactionHandler(&MockTransactionService{}, mockReceipts)
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleTransactionByID).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/transactions/99/receipt", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestCheckoutUsesAuthenticatedCashier(t *testing.T) {
+	var got model.CheckoutRequest
+	mockService := &MockTransactionService{
+		CheckoutFunc: func(req model.CheckoutRequest) (*model.Transaction, error) {
+			got = req
+			return &model.Transaction{ID: 1, CashierID: req.CashierID, TerminalID: req.TerminalID}, nil
+		},
+	}
+	h := handler.NewTransactionHandler(mockService, &MockReceiptService{})
+
+	body := []byte(`{"items":[{"product_id":1,"quantity":2}],"cashier_id":99,"terminal_id":"spoofed"}`)
+	req, err := http.NewRequest("POST", "/checkout", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = req.WithContext(auth.WithPrincipal(req.Context(), &auth.Principal{Type: auth.PrincipalUser, UserID: 5, TerminalID: "KASIR-01"}))
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleCheckout).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+	if got.CashierID != 5 || got.TerminalID != "KASIR-01" {
+		t.Errorf("expected cashier 5 at KASIR-01, got %d at %q", got.CashierID, got.TerminalID)
+	}
+}
+
+func TestCheckoutRejectsInvalidItems(t *testing.T) {
+	mockService := &MockTransactionService{
+		CheckoutFunc: func(req model.CheckoutRequest) (*model.Transaction, error) {
+			return nil, fmt.Errorf("%w: quantity must be greater than zero", repository.ErrInvalidCheckout)
+		},
+	}
+	h := handler.NewTransactionHandler(mockService, &MockReceiptService{})
+
+	body := []byte(`{"items":[{"product_id":1,"quantity":-5}]}`)
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleCheckout).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/checkout", bytes.NewBuffer(body)))
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestGetReportGroupByCashier(t *testing.T) {
+	var got model.ReportFilter
+	mockService := &MockTransactionService{
+		GetSalesReportFunc: func(filter model.ReportFilter) (*repository.SalesReport, error) {
+			got = filter
+			return &repository.SalesReport{PerKasir: []repository.PenjualanKasir{{KasirID: 5, Nama: "Budi", TotalRevenue: 10000, TotalTransaksi: 2}}}, nil
+		},
+	}
+	h := handler.NewTransactionHandler(mockService, &MockReceiptService{})
+
+	req, err := http.NewRequest("GET", "/report?start_date=2024-01-01&end_date=2024-01-31&terminal_id=KASIR-01&group_by=cashier", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleReport).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if got.TerminalID != "KASIR-01" || got.GroupBy != model.ReportGroupByCashier {
+		t.Errorf("unexpected filter: %+v", got)
+	}
+
+	var report repository.SalesReport
+	if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(report.PerKasir) != 1 || report.PerKasir[0].KasirID != 5 {
+		t.Errorf("unexpected per_kasir: %+v", report.PerKasir)
+	}
+}
+
+func TestGetReportGroupByProduct(t *testing.T) {
+	var got model.ReportFilter
+	mockService := &MockTransactionService{
+		GetSalesReportFunc: func(filter model.ReportFilter) (*repository.SalesReport, error) {
+			got = filter
+			return &repository.SalesReport{
+				PenjualanBersih: 100000, TotalHPP: 70000, LabaKotor: 30000, MarginBP: 3000,
+				PerProduk: []repository.RingkasanLaba{{ID: 3, Nama: "Beras 5kg", QtyTerjual: 2, PenjualanBersih: 100000, TotalHPP: 70000, LabaKotor: 30000, MarginBP: 3000}},
+			}, nil
+		},
+	}
+	h := handler.NewTransactionHandler(mockService, &MockReceiptService{})
+
+	req, err := http.NewRequest("GET", "/report?start_date=2024-01-01&end_date=2024-01-31&group_by=product", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleReport).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusOK)
+	}
+	if got.GroupBy != model.ReportGroupByProduct {
+		t.Errorf("unexpected filter: %+v", got)
+	}
+
+	var report repository.SalesReport
+	if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if report.LabaKotor != 30000 || len(report.PerProduk) != 1 || report.PerProduk[0].MarginBP != 3000 {
+		t.Errorf("unexpected gross profit: %+v", report)
+	}
+}
+
+func TestGetReportTopAndInterval(t *testing.T) {
+	var got model.ReportFilter
+	mockService := &MockTransactionService{
+		GetSalesReportFunc: func(filter model.ReportFilter) (*repository.SalesReport, error) {
+			got = filter
+			return &repository.SalesReport{
+				Interval:    filter.Interval,
+				TerlarisQty: []repository.ProdukTerjual{{ProdukID: 3, Nama: "Beras 5kg", QtyTerjual: 12, TotalRevenue: 840000}},
+				PerWaktu:    []repository.PenjualanPeriode{{Waktu: "2024-01-01 09:00", TotalRevenue: 70000, TotalTransaksi: 1}},
+			}, nil
+		},
+	}
+	h := handler.NewTransactionHandler(mockService, &MockReceiptService{})
+
+	rr := httptest.NewRecorder()
+	h.HandleReport(rr, httptest.NewRequest(http.MethodGet, "/report?start_date=2024-01-01&end_date=2024-01-01&top=10&interval=hour", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got.Top != 10 || got.Interval != model.ReportIntervalHour {
+		t.Errorf("unexpected filter: %+v", got)
+	}
+	var report repository.SalesReport
+	if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(report.TerlarisQty) != 1 || len(report.PerWaktu) != 1 || report.PerWaktu[0].Waktu != "2024-01-01 09:00" {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	for _, query := range []string{"top=0", "top=many", "interval=week"} {
+		rr := httptest.NewRecorder()
+		h.HandleReport(rr, httptest.NewRequest(http.MethodGet, "/report?"+query, nil))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, rr.Code)
+		}
+	}
+}
+
+func TestGetReportExportCSV(t *testing.T) {
+	mockService := &MockTransactionService{
+		GetSalesReportFunc: func(filter model.ReportFilter) (*repository.SalesReport, error) {
+			return &repository.SalesReport{
+				TanggalMulai: "2024-01-01",
+				TanggalAkhir: "2024-01-31",
+				TotalRevenue: 150000,
+				TerlarisQty:  []repository.ProdukTerjual{{ProdukID: 3, Nama: "Beras 5kg", QtyTerjual: 2, TotalRevenue: 140000}},
+			}, nil
+		},
+	}
+	h := handler.NewTransactionHandler(mockService, &MockReceiptService{})
+
+	rr := httptest.NewRecorder()
+	h.HandleReport(rr, httptest.NewRequest(http.MethodGet, "/report?start_date=2024-01-01&end_date=2024-01-31&format=csv", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "text/csv" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	if cd := rr.Header().Get("Content-Disposition"); cd != `attachment; filename="laporan-penjualan-2024-01-01_2024-01-31.csv"` {
+		t.Errorf("unexpected content disposition %q", cd)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{"Metrik,Nilai\n", "Total Revenue,150000\n", "\nTerlaris Qty\nProduk,Qty Terjual,Total Revenue\nBeras 5kg,2,140000\n"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in:\n%s", want, body)
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	h.HandleReport(rr, httptest.NewRequest(http.MethodGet, "/report?format=docx", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("unknown format: expected 400, got %d", rr.Code)
+	}
+}
+
+func TestGetTransactionsExportXLSX(t *testing.T) {
+	var got model.TransactionFilter
+	mockService := &MockTransactionService{
+		EachTransactionFunc: func(filter model.TransactionFilter, fn func(model.Transaction) error) error {
+			got = filter
+			for _, tr := range []model.Transaction{{ID: 2, TotalAmount: 20000, Status: "completed"}, {ID: 1, TotalAmount: 10000, Status: "voided"}} {
+				if err := fn(tr); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		EachTransactionDetailFunc: func(filter model.TransactionFilter, fn func(model.TransactionDetail) error) error {
+			return fn(model.TransactionDetail{TransactionID: 2, ProductID: 3, ProductName: "Beras 5kg", Quantity: 1, LineTotal: 20000})
+		},
+		EachPaymentFunc: func(filter model.TransactionFilter, fn func(model.Payment) error) error {
+			return fn(model.Payment{TransactionID: 2, Method: "cash", Amount: 20000})
+		},
+	}
+	h := handler.NewTransactionHandler(mockService, &MockReceiptService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/transactions?cashier_id=4&page=3", nil)
+	req.Header.Set("Accept", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	rr := httptest.NewRecorder()
+	h.HandleTransactions(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got.CashierID != 4 {
+		t.Errorf("unexpected filter: %+v", got)
+	}
+	f, err := excelize.OpenReader(rr.Body)
+	if err != nil {
+		t.Fatalf("response is not a workbook: %v", err)
+	}
+	defer f.Close()
+	if sheets := f.GetSheetList(); fmt.Sprint(sheets) != "[Transactions Items Payments]" {
+		t.Fatalf("unexpected sheets %v", sheets)
+	}
+	for sheet, want := range map[string]int{"Transactions": 3, "Items": 2, "Payments": 2} {
+		rows, err := f.GetRows(sheet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != want {
+			t.Errorf("%s: expected %d rows, got %d", sheet, want, len(rows))
+		}
+	}
+}
+
+func TestGetTransactionsExportInvalidFilter(t *testing.T) {
+	mockService := &MockTransactionService{
+		EachTransactionFunc: func(filter model.TransactionFilter, fn func(model.Transaction) error) error {
+			return fmt.Errorf("%w: start_date cannot be after end_date", service.ErrInvalidFilter)
+		},
+	}
+	h := handler.NewTransactionHandler(mockService, &MockReceiptService{})
+
+	rr := httptest.NewRecorder()
+	h.HandleTransactions(rr, httptest.NewRequest(http.MethodGet, "/transactions?start_date=2024-02-01&end_date=2024-01-01&format=csv", nil))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if cd := rr.Header().Get("Content-Disposition"); cd != "" {
+		t.Errorf("expected no download, got %q", cd)
+	}
+}
+
+func TestGetReportRejectsInvalidFilter(t *testing.T) {
+	mockService := &MockTransactionService{
+		GetSalesReportFunc: func(filter model.ReportFilter) (*repository.SalesReport, error) {
+			return nil, fmt.Errorf("%w: start_date cannot be after end_date", service.ErrInvalidFilter)
+		},
+	}
+	h := handler.NewTransactionHandler(mockService, &MockReceiptService{})
+
+	rr := httptest.NewRecorder()
+	h.HandleReport(rr, httptest.NewRequest(http.MethodGet, "/report?start_date=2024-02-01&end_date=2024-01-01", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rr.Code)
+	}
+}
+
+func TestGetReportRejectsUnknownGroupBy(t *testing.T) {
+	h := handler.NewTransactionHandler(&MockTransactionService{}, &MockReceiptService{})
+
+	req, err := http.NewRequest("GET", "/report?group_by=week", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleReport).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestCheckoutIntoClosedDay(t *testing.T) {
+	mockService := &MockTransactionService{
+		CheckoutFunc: func(req model.CheckoutRequest) (*model.Transaction, error) {
+			return nil, fmt.Errorf("%w: today has already been closed with a Z report", repository.ErrDayClosed)
+		},
+	}
+	h := handler.NewTransactionHandler(mockService, &MockReceiptService{})
+
+	rr := httptest.NewRecorder()
+	h.HandleCheckout(rr, httptest.NewRequest(http.MethodPost, "/checkout", bytes.NewBufferString(`{"items": [{"product_id": 1, "quantity": 1}]}`)))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", rr.Code)
+	}
+}
+
+func TestCheckoutRejectsShortPayment(t *testing.T) {
+	mockService := &MockTransactionService{
+		CheckoutFunc: func(req model.CheckoutRequest) (*model.Transaction, error) {
+			if _, _, err := payment.Settle(20000, req.Payments); err != nil {
+				return nil, err
+			}
+			return &model.Transaction{ID: 1}, nil
+		},
+	}
+	h := handler.NewTransactionHandler(mockService, &MockReceiptService{})
+
+	body := []byte(`{"items":[{"product_id":1,"quantity":2}],"payments":[{"method":"cash","amount":10000}]}`)
+	req, err := http.NewRequest("POST", "/checkout", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleCheckout).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
+
+func TestCheckoutRejectsRedeemedVoucher(t *testing.T) {
+	mockService := &MockTransactionService{
+		CheckoutFunc: func(req model.CheckoutRequest) (*model.Transaction, error) {
+			return nil, fmt.Errorf("%w: code %s has been fully redeemed", repository.ErrInvalidVoucher, req.VoucherCode)
+		},
+	}
+	h := handler.NewTransactionHandler(mockService, &MockReceiptService{})
+
+	body := []byte(`{"items":[{"product_id":1,"quantity":1}],"voucher_code":"HEMAT10"}`)
+	req, err := http.NewRequest("POST", "/checkout", bytes.NewBuffer(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleCheckout).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"kasir-api/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type VoucherHandler struct {
+	service service.VoucherService
+}
+
+func NewVoucherHandler(service service.VoucherService) *VoucherHandler {
+	return &VoucherHandler{service: service}
+}
+
+func (h *VoucherHandler) HandleVouchers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Path != "/vouchers" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getAll(w, r)
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *VoucherHandler) HandleVoucherByID(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	idStr := strings.TrimPrefix(r.URL.Path, "/vouchers/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.getByID(w, r, id)
+	case http.MethodPut:
+		h.update(w, r, id)
+	case http.MethodDelete:
+		h.delete(w, r, id)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// getAll godoc
+// @Summary Get all vouchers
+// @Description Get all vouchers with how many times each has been redeemed
+// @Tags vouchers
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} model.Voucher
+// @Failure 500 {object} map[string]string
+// @Router /vouchers [get]
+func (h *VoucherHandler) getAll(w http.ResponseWriter, r *http.Request) {
+	vouchers, err := h.service.GetAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(vouchers)
+}
+
+// create godoc
+// @Summary Create a voucher
+// @Description Create a percentage or fixed_amount voucher code. Percentages are in basis points (1000 = 10%). A usage_limit or per_customer_limit of 0 means unlimited. Codes are case insensitive.
+// @Tags vouchers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param voucher body model.Voucher true "Voucher object"
+// @Success 201 {object} model.Voucher
+// @Failure 400 {object} map[string]string
+// @Router /vouchers [post]
+func (h *VoucherHandler) create(w http.ResponseWriter, r *http.Request) {
+	var v model.Voucher
+	if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	created, err := h.service.Create(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+// getByID godoc
+// @Summary Get voucher by ID
+// @Description Get a single voucher with its redemption count
+// @Tags vouchers
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Voucher ID"
+// @Success 200 {object} model.Voucher
+// @Failure 404 {object} map[string]string
+// @Router /vouchers/{id} [get]
+func (h *VoucherHandler) getByID(w http.ResponseWriter, r *http.Request, id int) {
+	v, err := h.service.GetByID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(v)
+}
+
+// update godoc
+// @Summary Update a voucher
+// @Description Update a voucher. Redemptions already made keep counting towards its limits.
+// @Tags vouchers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Voucher ID"
+// @Param voucher body model.Voucher true "Voucher object"
+// @Success 200 {object} model.Voucher
+// @Failure 400 {object} map[string]string
+// @Router /vouchers/{id} [put]
+func (h *VoucherHandler) update(w http.ResponseWriter, r *http.Request, id int) {
+	var v model.Voucher
+	if err := json.NewDecoder(r.Body).Decode(&v); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+	updated, err := h.service.Update(id, v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(updated)
+}
+
+// delete godoc
+// @Summary Delete a voucher
+// @Description Delete a voucher that has never been redeemed. Redeemed vouchers keep their redemption history and cannot be deleted; set active to false instead
+// @Tags vouchers
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Voucher ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /vouchers/{id} [delete]
+func (h *VoucherHandler) delete(w http.ResponseWriter, r *http.Request, id int) {
+	err := h.service.Delete(id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Voucher not found", http.StatusNotFound)
+		return
+	case errors.Is(err, repository.ErrInvalidVoucher):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,60 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"kasir-api/internal/handler"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCreateVoucher(t *testing.T) {
+	mockService := &MockVoucherService{
+		CreateFunc: func(v model.Voucher) (model.Voucher, error) {
+			v.ID = 5
+			return v, nil
+		},
+	}
+	h := handler.NewVoucherHandler(mockService)
+
+	payload := []byte(`{"code":"HEMAT10","type":"percentage","percent_bp":1000,"max_discount":25000,"usage_limit":500,"per_customer_limit":1,"active":true}`)
+	req, err := http.NewRequest("POST", "/vouchers", bytes.NewBuffer(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleVouchers).ServeHTTP(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("handler returned wrong status code: got %v want %v", status, http.StatusCreated)
+	}
+
+	var created model.Voucher
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if created.ID != 5 || created.Code != "HEMAT10" || created.UsageLimit != 500 || created.PerCustomerLimit != 1 {
+		t.Errorf("unexpected voucher: %+v", created)
+	}
+}
+
+func TestDeleteRedeemedVoucher(t *testing.T) {
+	mockService := &MockVoucherService{
+		DeleteFunc: func(id int) error {
+			return fmt.Errorf("%w: voucher %d has been redeemed, deactivate it instead", repository.ErrInvalidVoucher, id)
+		},
+	}
+	h := handler.NewVoucherHandler(mockService)
+
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(h.HandleVoucherByID).ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/vouchers/5", nil))
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v", status, http.StatusBadRequest)
+	}
+}
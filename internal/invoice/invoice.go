@@ -0,0 +1,185 @@
+// Package invoice formats the human-readable numbers printed on sales. A
+// format such as INV/{store}/{yyyyMMdd}/{seq} mixes literal text with
+// placeholders; the sequence restarts whenever the finest date unit the
+// format shows changes, so the example above counts from 1 each business day.
+package invoice
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultFormat numbers sales per store and business day.
+const DefaultFormat = "INV/{store}/{yyyyMMdd}/{seq}"
+
+// defaultDigits is how far {seq} is zero-padded when no width is given.
+const defaultDigits = 4
+
+var ErrInvalidFormat = errors.New("invalid invoice number format")
+
+// Date units, coarsest first, with the Go layout of each token.
+var dateTokens = []struct {
+	token  string
+	layout string
+	unit   int
+}{
+	{"yyyy", "2006", unitYear},
+	{"yy", "06", unitYear},
+	{"MM", "01", unitMonth},
+	{"dd", "02", unitDay},
+}
+
+const (
+	unitNone = iota
+	unitYear
+	unitMonth
+	unitDay
+)
+
+// periodLayouts key the sequence by the finest unit the format shows.
+var periodLayouts = map[int]string{
+	unitYear:  "2006",
+	unitMonth: "2006-01",
+	unitDay:   "2006-01-02",
+}
+
+// Format is a parsed invoice number format bound to one store.
+type Format struct {
+	store  string
+	parts  []part
+	period string
+}
+
+// part is a piece of the format: literal text, a date layout, or the
+// sequence number padded to digits.
+type part struct {
+	text   string
+	layout string
+	seq    bool
+	digits int
+}
+
+// New parses format for the store with the given code. Placeholders are
+// {store}, {seq} or {seq:N} for a sequence padded to N digits, and dates
+// written with yyyy, yy, MM and dd such as {yyyyMMdd} or {yy}{MM}. The format
+// needs exactly one {seq}, and a month or day only with the units above it,
+// so numbers never repeat.
+func New(format, store string) (*Format, error) {
+	f := &Format{store: store}
+	units := map[int]bool{}
+	seqs := 0
+
+	for rest := format; rest != ""; {
+		open := strings.IndexAny(rest, "{}")
+		if open < 0 {
+			f.parts = append(f.parts, part{text: rest})
+			break
+		}
+		if rest[open] == '}' {
+			return nil, fmt.Errorf("%w: unmatched } in %q", ErrInvalidFormat, format)
+		}
+		if open > 0 {
+			f.parts = append(f.parts, part{text: rest[:open]})
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("%w: unmatched { in %q", ErrInvalidFormat, format)
+		}
+		name := rest[open+1 : open+end]
+		rest = rest[open+end+1:]
+
+		switch {
+		case name == "store":
+			if store == "" {
+				return nil, fmt.Errorf("%w: {store} needs a store code", ErrInvalidFormat)
+			}
+			f.parts = append(f.parts, part{text: store})
+		case name == "seq" || strings.HasPrefix(name, "seq:"):
+			digits := defaultDigits
+			if n, ok := strings.CutPrefix(name, "seq:"); ok {
+				d, err := strconv.Atoi(n)
+				if err != nil || d < 1 || d > 18 {
+					return nil, fmt.Errorf("%w: {%s} needs a width from 1 to 18", ErrInvalidFormat, name)
+				}
+				digits = d
+			}
+			f.parts = append(f.parts, part{seq: true, digits: digits})
+			seqs++
+		default:
+			layout, err := dateLayout(name, units)
+			if err != nil {
+				return nil, err
+			}
+			f.parts = append(f.parts, part{layout: layout})
+		}
+	}
+
+	if seqs != 1 {
+		return nil, fmt.Errorf("%w: %q needs exactly one {seq}", ErrInvalidFormat, format)
+	}
+	if units[unitDay] && !units[unitMonth] || units[unitMonth] && !units[unitYear] {
+		return nil, fmt.Errorf("%w: %q shows a month or day without the units above it", ErrInvalidFormat, format)
+	}
+	for unit := unitDay; unit > unitNone; unit-- {
+		if units[unit] {
+			f.period = periodLayouts[unit]
+			break
+		}
+	}
+	return f, nil
+}
+
+// dateLayout turns a date placeholder such as yyyyMMdd into a Go layout,
+// recording the units it shows.
+func dateLayout(name string, units map[int]bool) (string, error) {
+	var layout strings.Builder
+	for rest := name; rest != ""; {
+		matched := false
+		for _, d := range dateTokens {
+			if strings.HasPrefix(rest, d.token) {
+				layout.WriteString(d.layout)
+				units[d.unit] = true
+				rest = rest[len(d.token):]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return "", fmt.Errorf("%w: unknown placeholder {%s}", ErrInvalidFormat, name)
+		}
+	}
+	return layout.String(), nil
+}
+
+func (f *Format) Store() string {
+	return f.store
+}
+
+// Period returns the key of the sequence a sale on the business date belongs
+// to: the date cut to the finest unit the format shows, or "" when the
+// sequence never restarts.
+func (f *Format) Period(date time.Time) string {
+	if f.period == "" {
+		return ""
+	}
+	return date.Format(f.period)
+}
+
+// Number formats the seq-th invoice number of the period holding date.
+func (f *Format) Number(date time.Time, seq int) string {
+	var b strings.Builder
+	for _, p := range f.parts {
+		switch {
+		case p.seq:
+			fmt.Fprintf(&b, "%0*d", p.digits, seq)
+		case p.layout != "":
+			b.WriteString(date.Format(p.layout))
+		default:
+			b.WriteString(p.text)
+		}
+	}
+	return b.String()
+}
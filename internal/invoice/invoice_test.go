@@ -0,0 +1,60 @@
+package invoice_test
+
+import (
+	"errors"
+	"kasir-api/internal/invoice"
+	"testing"
+	"time"
+)
+
+func TestNumberAndPeriod(t *testing.T) {
+	date := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		format     string
+		seq        int
+		wantNumber string
+		wantPeriod string
+	}{
+		{invoice.DefaultFormat, 7, "INV/JKT01/20260301/0007", "2026-03-01"},
+		{"{store}-{yy}{MM}-{seq:6}", 42, "JKT01-2603-000042", "2026-03"},
+		{"F{yyyy}/{seq:2}", 123, "F2026/123", "2026"},
+		{"{store}/{seq}", 1, "JKT01/0001", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			f, err := invoice.New(tt.format, "JKT01")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := f.Number(date, tt.seq); got != tt.wantNumber {
+				t.Errorf("expected number %s, got %s", tt.wantNumber, got)
+			}
+			if got := f.Period(date); got != tt.wantPeriod {
+				t.Errorf("expected period %q, got %q", tt.wantPeriod, got)
+			}
+		})
+	}
+}
+
+func TestNewRejectsInvalidFormats(t *testing.T) {
+	for _, format := range []string{
+		"INV/{yyyyMMdd}",              // no sequence
+		"{seq}/{seq}",                 // two sequences
+		"INV/{dd}/{seq}",              // day without month and year
+		"INV/{MMdd}/{seq}",            // month without year
+		"INV/{date}/{seq}",            // unknown placeholder
+		"INV/{seq:0}",                 // bad width
+		"INV/{seq",                    // unclosed
+		"INV}/{seq}",                  // stray brace
+		"INV/{store}/{yyyyMMdd}/{seq", // unclosed at the end
+	} {
+		if _, err := invoice.New(format, "JKT01"); !errors.Is(err, invoice.ErrInvalidFormat) {
+			t.Errorf("%s: expected ErrInvalidFormat, got %v", format, err)
+		}
+	}
+
+	if _, err := invoice.New(invoice.DefaultFormat, ""); !errors.Is(err, invoice.ErrInvalidFormat) {
+		t.Errorf("expected {store} without a store code to fail, got %v", err)
+	}
+}
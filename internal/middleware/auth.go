@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"encoding/json"
+	"kasir-api/internal/auth"
+	"net/http"
+	"strings"
+)
+
+// APIKeyHeader carries the credential of API client principals.
+const APIKeyHeader = "X-API-Key"
+
+type Authenticator interface {
+	Authenticate(token string) (*auth.Principal, error)
+	AuthenticateAPIKey(key string) (*auth.Principal, error)
+}
+
+// RequireAuth rejects requests without valid credentials, either an
+// "Authorization: Bearer <token>" header or an X-API-Key header, and stores
+// the authenticated principal in the request context.
+func RequireAuth(authenticator Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key := r.Header.Get(APIKeyHeader); key != "" {
+				principal, err := authenticator.AuthenticateAPIKey(key)
+				if err != nil {
+					http.Error(w, "Invalid or revoked API key", http.StatusUnauthorized)
+					return
+				}
+				next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+				return
+			}
+
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="kasir-api"`)
+				http.Error(w, "Authentication required", http.StatusUnauthorized)
+				return
+			}
+
+			principal, err := authenticator.Authenticate(token)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="kasir-api", error="invalid_token"`)
+				http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+// Forbidden reasons reported in the 403 body.
+const (
+	ReasonMissingPermission = "missing_permission"
+	ReasonNotAuthenticated  = "not_authenticated"
+)
+
+type ForbiddenResponse struct {
+	Error      string `json:"error"`
+	Reason     string `json:"reason"`
+	Permission string `json:"permission,omitempty"`
+	Role       string `json:"role,omitempty"`
+}
+
+// Permissions maps an HTTP method to the permission it requires. The "*" key,
+// if present, applies to methods not listed explicitly.
+type Permissions map[string]string
+
+// RequirePermissions lets a request through only when the principal's role
+// grants the permission mapped to the request method. It must run after RequireAuth.
+func RequirePermissions(perms Permissions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			required, ok := perms[r.Method]
+			if !ok {
+				required, ok = perms["*"]
+			}
+			if !ok {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+
+			principal := auth.PrincipalFromContext(r.Context())
+			if principal == nil {
+				writeForbidden(w, ForbiddenResponse{Reason: ReasonNotAuthenticated, Permission: required})
+				return
+			}
+			if !principal.Can(required) {
+				writeForbidden(w, ForbiddenResponse{Reason: ReasonMissingPermission, Permission: required, Role: principal.Role})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequirePermission is RequirePermissions with the same permission for every method.
+func RequirePermission(permission string) func(http.Handler) http.Handler {
+	return RequirePermissions(Permissions{"*": permission})
+}
+
+func writeForbidden(w http.ResponseWriter, body ForbiddenResponse) {
+	body.Error = "forbidden"
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,103 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"errors"
+	"kasir-api/internal/auth"
+	"kasir-api/internal/middleware"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type stubAuthenticator struct {
+	principal *auth.Principal
+}
+
+func (s stubAuthenticator) Authenticate(token string) (*auth.Principal, error) {
+	if token != "good-token" {
+		return nil, errors.New("bad token")
+	}
+	return s.principal, nil
+}
+
+func (s stubAuthenticator) AuthenticateAPIKey(key string) (*auth.Principal, error) {
+	if key != "good-key" {
+		return nil, errors.New("bad key")
+	}
+	return &auth.Principal{Type: auth.PrincipalAPIClient, APIClientID: 9, Role: "integration"}, nil
+}
+
+func TestRequireAuth(t *testing.T) {
+	var seen *auth.Principal
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = auth.PrincipalFromContext(r.Context())
+	})
+	h := middleware.RequireAuth(stubAuthenticator{principal: &auth.Principal{Type: auth.PrincipalUser, UserID: 1, Role: "cashier"}})(next)
+
+	tests := []struct {
+		name     string
+		header   string
+		value    string
+		want     int
+		wantType string
+	}{
+		{"missing header", "", "", http.StatusUnauthorized, ""},
+		{"wrong scheme", "Authorization", "Basic abc", http.StatusUnauthorized, ""},
+		{"invalid token", "Authorization", "Bearer nope", http.StatusUnauthorized, ""},
+		{"valid token", "Authorization", "Bearer good-token", http.StatusOK, auth.PrincipalUser},
+		{"invalid api key", middleware.APIKeyHeader, "nope", http.StatusUnauthorized, ""},
+		{"valid api key", middleware.APIKeyHeader, "good-key", http.StatusOK, auth.PrincipalAPIClient},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen = nil
+			req := httptest.NewRequest("GET", "/products", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+
+			if rr.Code != tt.want {
+				t.Errorf("got status %d, want %d", rr.Code, tt.want)
+			}
+			if tt.want == http.StatusOK && (seen == nil || seen.Type != tt.wantType) {
+				t.Errorf("expected %s principal in context, got %+v", tt.wantType, seen)
+			}
+		})
+	}
+}
+
+func TestRequirePermissions(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := middleware.RequirePermissions(middleware.Permissions{
+		http.MethodGet: auth.PermProductsRead,
+		"*":            auth.PermProductsWrite,
+	})(next)
+	cashier := &auth.Principal{UserID: 2, Role: "cashier", Permissions: []string{auth.PermProductsRead}}
+
+	req := httptest.NewRequest("GET", "/products/1", nil)
+	req = req.WithContext(auth.WithPrincipal(req.Context(), cashier))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Errorf("cashier GET: got status %d, want %d", rr.Code, http.StatusOK)
+	}
+
+	req = httptest.NewRequest("PUT", "/products/1", nil)
+	req = req.WithContext(auth.WithPrincipal(req.Context(), cashier))
+	rr = httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("cashier PUT: got status %d, want %d", rr.Code, http.StatusForbidden)
+	}
+
+	var body middleware.ForbiddenResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode 403 body: %v", err)
+	}
+	if body.Reason != middleware.ReasonMissingPermission || body.Permission != auth.PermProductsWrite || body.Role != "cashier" {
+		t.Errorf("unexpected 403 body: %+v", body)
+	}
+}
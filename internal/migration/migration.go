@@ -0,0 +1,351 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var embeddedMigrations embed.FS
+
+// advisoryLockKey serializes migrations across instances starting at the same time.
+const advisoryLockKey int64 = 5_273_820_145
+
+// ErrDirty is returned when a previous migration failed half-way after part of
+// it had committed. The schema has to be repaired by hand and marked clean with
+// Force before anything else runs.
+var ErrDirty = errors.New("database schema is dirty")
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	Dirty     bool
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New returns a Migrator for the SQL files embedded in this package.
+func New(db *sql.DB) (*Migrator, error) {
+	sub, err := fs.Sub(embeddedMigrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return NewFromFS(db, sub)
+}
+
+func NewFromFS(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Load reads <version>_<name>.up.sql / .down.sql pairs from the root of fsys,
+// sorted by version. Every version needs an up file; down files are optional.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", entry.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d used by both %q and %q", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+type appliedRow struct {
+	Name      string
+	Dirty     bool
+	AppliedAt time.Time
+}
+
+// Up applies every pending migration in version order and returns how many ran.
+func (m *Migrator) Up() (int, error) {
+	count := 0
+	err := m.withLock(func(ctx context.Context, conn *sql.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := checkClean(applied); err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := runStep(ctx, conn, mig, mig.Up, true); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Down rolls back the most recently applied migrations, up to steps of them.
+func (m *Migrator) Down(steps int) (int, error) {
+	count := 0
+	err := m.withLock(func(ctx context.Context, conn *sql.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := checkClean(applied); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if mig.Down == "" {
+				return fmt.Errorf("migration %d_%s cannot be rolled back: no down file", mig.Version, mig.Name)
+			}
+			if err := runStep(ctx, conn, mig, mig.Down, false); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Force marks version as applied and clean, and forgets any dirty attempt at a
+// later version. Use it after repairing the schema by hand. Version 0 only
+// forgets the dirty attempts, for when the first migration failed.
+func (m *Migrator) Force(version int64) error {
+	var target *Migration
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			target = &m.migrations[i]
+		}
+	}
+	if target == nil && version != 0 {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.withLock(func(ctx context.Context, conn *sql.Conn) error {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version > $1 AND dirty", version); err != nil {
+			return err
+		}
+		if target == nil {
+			return tx.Commit()
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO schema_migrations (version, name, dirty) VALUES ($1, $2, FALSE)
+			ON CONFLICT (version) DO UPDATE SET dirty = FALSE
+		`, target.Version, target.Name)
+		if err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// Status reports every known migration along with whether it has been applied.
+func (m *Migrator) Status() ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(func(ctx context.Context, conn *sql.Conn) error {
+		applied, err := loadApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, mig := range m.migrations {
+			s := Status{Version: mig.Version, Name: mig.Name}
+			if row, ok := applied[mig.Version]; ok {
+				appliedAt := row.AppliedAt
+				s.Applied = true
+				s.Dirty = row.Dirty
+				s.AppliedAt = &appliedAt
+			}
+			statuses = append(statuses, s)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// withLock runs fn on a single connection holding the migration advisory lock,
+// after making sure the bookkeeping table exists.
+func (m *Migrator) withLock(fn func(ctx context.Context, conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", advisoryLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", advisoryLockKey)
+
+	_, err = conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name TEXT NOT NULL,
+			dirty BOOLEAN NOT NULL DEFAULT FALSE,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	return fn(ctx, conn)
+}
+
+func loadApplied(ctx context.Context, conn *sql.Conn) (map[int64]appliedRow, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, name, dirty, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]appliedRow)
+	for rows.Next() {
+		var version int64
+		var row appliedRow
+		if err := rows.Scan(&version, &row.Name, &row.Dirty, &row.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = row
+	}
+	return applied, rows.Err()
+}
+
+// checkClean fails on a dirty version, naming both ways out: forcing the
+// version once its script has been completed by hand, or forcing the version
+// before it once the partial changes have been undone.
+func checkClean(applied map[int64]appliedRow) error {
+	for version, row := range applied {
+		if !row.Dirty {
+			continue
+		}
+		var previous int64
+		for v, r := range applied {
+			if v < version && v > previous && !r.Dirty {
+				previous = v
+			}
+		}
+		return fmt.Errorf("%w: migration %d_%s did not finish; complete it by hand and run 'migrate force %d', or undo its changes and run 'migrate force %d'",
+			ErrDirty, version, row.Name, version, previous)
+	}
+	return nil
+}
+
+// runStep flags the version dirty, runs the script and clears the flag in one
+// transaction, so a failed step normally rolls back whole and leaves the
+// previous version clean. The flag only survives when the script commits part
+// of its work itself and then fails, and keeps later runs off that half.
+func runStep(ctx context.Context, conn *sql.Conn, mig Migration, script string, up bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	direction := "down"
+	if up {
+		direction = "up"
+		_, err = tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name, dirty) VALUES ($1, $2, TRUE)", mig.Version, mig.Name)
+	} else {
+		_, err = tx.ExecContext(ctx, "UPDATE schema_migrations SET dirty = TRUE WHERE version = $1", mig.Version)
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("migration %d_%s (%s) failed: %w", mig.Version, mig.Name, direction, err)
+	}
+
+	if up {
+		_, err = tx.ExecContext(ctx, "UPDATE schema_migrations SET dirty = FALSE, applied_at = CURRENT_TIMESTAMP WHERE version = $1", mig.Version)
+	} else {
+		_, err = tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", mig.Version)
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
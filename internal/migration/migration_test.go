@@ -0,0 +1,65 @@
+package migration_test
+
+import (
+	"kasir-api/internal/migration"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadSortsAndPairsFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000002_add_orders.up.sql":   {Data: []byte("CREATE TABLE orders (id INT);")},
+		"000002_add_orders.down.sql": {Data: []byte("DROP TABLE orders;")},
+		"000001_init.up.sql":         {Data: []byte("CREATE TABLE items (id INT);")},
+		"000010_add_index.up.sql":    {Data: []byte("CREATE INDEX ON orders (id);")},
+		"000010_add_index.down.sql":  {Data: []byte("DROP INDEX orders_id_idx;")},
+		"000001_init.down.sql":       {Data: []byte("DROP TABLE items;")},
+	}
+
+	migrations, err := migration.Load(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(migrations) != 3 {
+		t.Fatalf("expected 3 migrations, got %d", len(migrations))
+	}
+	wantVersions := []int64{1, 2, 10}
+	for i, m := range migrations {
+		if m.Version != wantVersions[i] {
+			t.Errorf("migration %d: expected version %d, got %d", i, wantVersions[i], m.Version)
+		}
+		if m.Up == "" || m.Down == "" {
+			t.Errorf("migration %d_%s is missing up or down script", m.Version, m.Name)
+		}
+	}
+	if migrations[1].Name != "add_orders" {
+		t.Errorf("expected name add_orders, got %q", migrations[1].Name)
+	}
+}
+
+func TestLoadRejectsMissingUpFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000001_init.down.sql": {Data: []byte("DROP TABLE items;")},
+	}
+
+	if _, err := migration.Load(fsys); err == nil {
+		t.Error("expected an error for a migration without an up file")
+	}
+}
+
+func TestLoadRejectsBadFileName(t *testing.T) {
+	fsys := fstest.MapFS{
+		"init.sql": {Data: []byte("SELECT 1;")},
+	}
+
+	if _, err := migration.Load(fsys); err == nil {
+		t.Error("expected an error for an unversioned file name")
+	}
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	if _, err := migration.New(nil); err != nil {
+		t.Fatalf("embedded migrations failed to load: %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+-- Baseline schema. Every statement is idempotent so databases created by the
+-- old start-up runMigrations can adopt the versioned migrations as-is.
+CREATE TABLE IF NOT EXISTS categories (
+	id SERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	description TEXT
+);
+
+CREATE TABLE IF NOT EXISTS products (
+	id SERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	price INT NOT NULL,
+	stock INT NOT NULL,
+	category_id INT REFERENCES categories(id)
+);
+
+CREATE TABLE IF NOT EXISTS transactions (
+	id SERIAL PRIMARY KEY,
+	total_amount INT NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS transaction_details (
+	id SERIAL PRIMARY KEY,
+	transaction_id INT REFERENCES transactions(id) ON DELETE CASCADE,
+	product_id INT REFERENCES products(id),
+	quantity INT NOT NULL,
+	subtotal INT NOT NULL
+);
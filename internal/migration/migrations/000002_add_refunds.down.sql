@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS refund_items;
+DROP TABLE IF EXISTS refunds;
+ALTER TABLE transactions DROP COLUMN IF EXISTS status;
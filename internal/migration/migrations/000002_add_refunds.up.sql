@@ -0,0 +1,19 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'completed';
+
+CREATE TABLE IF NOT EXISTS refunds (
+	id SERIAL PRIMARY KEY,
+	transaction_id INT NOT NULL REFERENCES transactions(id),
+	type TEXT NOT NULL,
+	reason TEXT,
+	total_amount INT NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS refund_items (
+	id SERIAL PRIMARY KEY,
+	refund_id INT REFERENCES refunds(id) ON DELETE CASCADE,
+	transaction_detail_id INT REFERENCES transaction_details(id),
+	product_id INT REFERENCES products(id),
+	quantity INT NOT NULL,
+	amount INT NOT NULL
+);
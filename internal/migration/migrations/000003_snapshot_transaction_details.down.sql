@@ -0,0 +1,13 @@
+ALTER TABLE refund_items DROP CONSTRAINT IF EXISTS refund_items_product_id_fkey;
+ALTER TABLE refund_items ADD CONSTRAINT refund_items_product_id_fkey
+	FOREIGN KEY (product_id) REFERENCES products(id);
+
+ALTER TABLE transaction_details DROP CONSTRAINT IF EXISTS transaction_details_product_id_fkey;
+ALTER TABLE transaction_details ADD CONSTRAINT transaction_details_product_id_fkey
+	FOREIGN KEY (product_id) REFERENCES products(id);
+
+ALTER TABLE transaction_details
+	DROP COLUMN IF EXISTS product_name,
+	DROP COLUMN IF EXISTS unit_price,
+	DROP COLUMN IF EXISTS category_id,
+	DROP COLUMN IF EXISTS category_name;
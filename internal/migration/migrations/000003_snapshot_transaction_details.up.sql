@@ -0,0 +1,23 @@
+ALTER TABLE transaction_details
+	ADD COLUMN IF NOT EXISTS product_name TEXT,
+	ADD COLUMN IF NOT EXISTS unit_price INT,
+	ADD COLUMN IF NOT EXISTS category_id INT,
+	ADD COLUMN IF NOT EXISTS category_name TEXT;
+
+UPDATE transaction_details td
+SET product_name = p.name,
+	unit_price = td.subtotal / NULLIF(td.quantity, 0),
+	category_id = p.category_id,
+	category_name = c.name
+FROM products p
+LEFT JOIN categories c ON p.category_id = c.id
+WHERE td.product_id = p.id AND td.product_name IS NULL;
+
+-- Sold products may be deleted; history keeps its snapshot instead
+ALTER TABLE transaction_details DROP CONSTRAINT IF EXISTS transaction_details_product_id_fkey;
+ALTER TABLE transaction_details ADD CONSTRAINT transaction_details_product_id_fkey
+	FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE SET NULL;
+
+ALTER TABLE refund_items DROP CONSTRAINT IF EXISTS refund_items_product_id_fkey;
+ALTER TABLE refund_items ADD CONSTRAINT refund_items_product_id_fkey
+	FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE SET NULL;
@@ -0,0 +1,54 @@
+CREATE TABLE IF NOT EXISTS roles (
+	name TEXT PRIMARY KEY,
+	description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+	role TEXT NOT NULL REFERENCES roles(name) ON UPDATE CASCADE ON DELETE CASCADE,
+	permission TEXT NOT NULL,
+	PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, description) VALUES
+	('admin', 'Full access, including users and roles'),
+	('manager', 'Manages the catalog, refunds and reports'),
+	('cashier', 'Rings up sales and looks up products')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+	('admin', 'categories:read'),
+	('admin', 'categories:write'),
+	('admin', 'products:read'),
+	('admin', 'products:write'),
+	('admin', 'checkout'),
+	('admin', 'transactions:read'),
+	('admin', 'transactions:refund'),
+	('admin', 'reports:read'),
+	('admin', 'users:manage'),
+	('admin', 'roles:manage'),
+	('manager', 'categories:read'),
+	('manager', 'categories:write'),
+	('manager', 'products:read'),
+	('manager', 'products:write'),
+	('manager', 'checkout'),
+	('manager', 'transactions:read'),
+	('manager', 'transactions:refund'),
+	('manager', 'reports:read'),
+	('cashier', 'categories:read'),
+	('cashier', 'products:read'),
+	('cashier', 'checkout'),
+	('cashier', 'transactions:read')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE users ADD CONSTRAINT users_role_fkey
+	FOREIGN KEY (role) REFERENCES roles(name) ON UPDATE CASCADE;
+
+CREATE TABLE IF NOT EXISTS api_clients (
+	id SERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	key_prefix TEXT NOT NULL,
+	key_hash TEXT NOT NULL UNIQUE,
+	role TEXT NOT NULL REFERENCES roles(name) ON UPDATE CASCADE,
+	active BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
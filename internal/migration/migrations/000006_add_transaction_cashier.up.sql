@@ -0,0 +1,5 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS cashier_id INT REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS terminal_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_cashier_id ON transactions(cashier_id);
+CREATE INDEX IF NOT EXISTS idx_transactions_terminal_id ON transactions(terminal_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS transaction_payments;
+ALTER TABLE transactions DROP COLUMN IF EXISTS change_amount;
+ALTER TABLE transactions DROP COLUMN IF EXISTS paid_amount;
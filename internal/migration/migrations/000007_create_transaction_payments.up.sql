@@ -0,0 +1,23 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS paid_amount INT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS change_amount INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS transaction_payments (
+	id SERIAL PRIMARY KEY,
+	transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+	method TEXT NOT NULL,
+	amount INT NOT NULL,
+	change_amount INT NOT NULL DEFAULT 0,
+	reference TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_payments_transaction_id ON transaction_payments(transaction_id);
+
+-- Sales made before payments were recorded are taken as paid in exact cash
+INSERT INTO transaction_payments (transaction_id, method, amount, created_at)
+SELECT t.id, 'cash', t.total_amount, t.created_at
+FROM transactions t
+WHERE t.total_amount > 0
+	AND NOT EXISTS (SELECT 1 FROM transaction_payments p WHERE p.transaction_id = t.id);
+
+UPDATE transactions SET paid_amount = total_amount WHERE paid_amount = 0;
@@ -0,0 +1,7 @@
+DELETE FROM role_permissions WHERE permission = 'shifts:manage';
+
+ALTER TABLE refunds DROP COLUMN IF EXISTS shift_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS shift_id;
+
+DROP TABLE IF EXISTS cash_movements;
+DROP TABLE IF EXISTS shifts;
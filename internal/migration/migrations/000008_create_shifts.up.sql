@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS shifts (
+	id SERIAL PRIMARY KEY,
+	terminal_id TEXT NOT NULL,
+	cashier_id INT REFERENCES users(id) ON DELETE SET NULL,
+	status TEXT NOT NULL DEFAULT 'open',
+	opening_float INT NOT NULL DEFAULT 0,
+	expected_cash INT,
+	counted_cash INT,
+	discrepancy INT,
+	note TEXT,
+	opened_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	closed_at TIMESTAMP,
+	closed_by INT REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- A terminal has at most one open drawer at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shifts_open_terminal ON shifts(terminal_id) WHERE status = 'open';
+
+CREATE TABLE IF NOT EXISTS cash_movements (
+	id SERIAL PRIMARY KEY,
+	shift_id INT NOT NULL REFERENCES shifts(id) ON DELETE CASCADE,
+	type TEXT NOT NULL,
+	amount INT NOT NULL,
+	reason TEXT NOT NULL DEFAULT '',
+	created_by INT REFERENCES users(id) ON DELETE SET NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS shift_id INT REFERENCES shifts(id);
+ALTER TABLE refunds ADD COLUMN IF NOT EXISTS shift_id INT REFERENCES shifts(id);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_shift_id ON transactions(shift_id);
+CREATE INDEX IF NOT EXISTS idx_refunds_shift_id ON refunds(shift_id);
+
+INSERT INTO role_permissions (role, permission) VALUES
+	('admin', 'shifts:manage'),
+	('manager', 'shifts:manage'),
+	('cashier', 'shifts:manage')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,17 @@
+DELETE FROM role_permissions WHERE permission = 'taxes:manage';
+
+ALTER TABLE refund_items DROP COLUMN IF EXISTS tax_amount;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS tax_amount;
+ALTER TABLE transactions DROP COLUMN IF EXISTS subtotal_amount;
+
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS line_total;
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS tax_amount;
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS tax_inclusive;
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS tax_rate_bp;
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS tax_name;
+
+ALTER TABLE products DROP COLUMN IF EXISTS tax_rate_id;
+ALTER TABLE categories DROP COLUMN IF EXISTS tax_rate_id;
+
+DROP TABLE IF EXISTS tax_rates;
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS tax_rates (
+	id SERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	rate_bp INT NOT NULL,
+	inclusive BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+INSERT INTO tax_rates (name, rate_bp, inclusive)
+SELECT 'PPN 11%', 1100, FALSE
+WHERE NOT EXISTS (SELECT 1 FROM tax_rates);
+
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS tax_rate_id INT REFERENCES tax_rates(id) ON DELETE SET NULL;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS tax_rate_id INT REFERENCES tax_rates(id) ON DELETE SET NULL;
+
+-- Tax is snapshotted per line like the product name and price
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS tax_name TEXT;
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS tax_rate_bp INT NOT NULL DEFAULT 0;
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS tax_amount INT NOT NULL DEFAULT 0;
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS line_total INT;
+UPDATE transaction_details SET line_total = subtotal WHERE line_total IS NULL;
+ALTER TABLE transaction_details ALTER COLUMN line_total SET NOT NULL;
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS subtotal_amount INT;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS tax_amount INT NOT NULL DEFAULT 0;
+UPDATE transactions SET subtotal_amount = total_amount WHERE subtotal_amount IS NULL;
+ALTER TABLE transactions ALTER COLUMN subtotal_amount SET NOT NULL;
+
+ALTER TABLE refund_items ADD COLUMN IF NOT EXISTS tax_amount INT NOT NULL DEFAULT 0;
+
+INSERT INTO role_permissions (role, permission) VALUES
+	('admin', 'taxes:manage'),
+	('manager', 'taxes:manage')
+ON CONFLICT DO NOTHING;
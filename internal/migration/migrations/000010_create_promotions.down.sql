@@ -0,0 +1,8 @@
+DELETE FROM role_permissions WHERE permission = 'promotions:manage';
+
+DROP TABLE IF EXISTS transaction_promotions;
+ALTER TABLE transactions DROP COLUMN IF EXISTS discount_amount;
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS discount_amount;
+
+DROP TABLE IF EXISTS promotion_items;
+DROP TABLE IF EXISTS promotions;
@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS promotions (
+	id SERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	type TEXT NOT NULL,
+	percent_bp INT NOT NULL DEFAULT 0,
+	amount INT NOT NULL DEFAULT 0,
+	buy_qty INT NOT NULL DEFAULT 0,
+	get_qty INT NOT NULL DEFAULT 0,
+	min_spend INT NOT NULL DEFAULT 0,
+	priority INT NOT NULL DEFAULT 0,
+	active BOOLEAN NOT NULL DEFAULT TRUE,
+	starts_at TIMESTAMP,
+	ends_at TIMESTAMP,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS promotion_items (
+	id SERIAL PRIMARY KEY,
+	promotion_id INT NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+	product_id INT REFERENCES products(id) ON DELETE CASCADE,
+	category_id INT REFERENCES categories(id) ON DELETE CASCADE,
+	quantity INT NOT NULL DEFAULT 1
+);
+
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS discount_amount INT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS discount_amount INT NOT NULL DEFAULT 0;
+
+-- Promotions applied at checkout; the name is kept in case the promotion is deleted
+CREATE TABLE IF NOT EXISTS transaction_promotions (
+	id SERIAL PRIMARY KEY,
+	transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+	transaction_detail_id INT NOT NULL REFERENCES transaction_details(id) ON DELETE CASCADE,
+	promotion_id INT REFERENCES promotions(id) ON DELETE SET NULL,
+	promotion_name TEXT NOT NULL,
+	amount INT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_transaction_promotions_transaction_id ON transaction_promotions(transaction_id);
+
+INSERT INTO role_permissions (role, permission) VALUES
+	('admin', 'promotions:manage'),
+	('manager', 'promotions:manage')
+ON CONFLICT DO NOTHING;
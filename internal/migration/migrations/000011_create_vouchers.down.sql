@@ -0,0 +1,7 @@
+DELETE FROM role_permissions WHERE permission = 'vouchers:manage';
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS voucher_discount;
+ALTER TABLE transactions DROP COLUMN IF EXISTS voucher_code;
+
+DROP TABLE IF EXISTS voucher_redemptions;
+DROP TABLE IF EXISTS vouchers;
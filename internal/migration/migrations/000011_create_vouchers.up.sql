@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS vouchers (
+	id SERIAL PRIMARY KEY,
+	code TEXT NOT NULL UNIQUE,
+	type TEXT NOT NULL,
+	percent_bp INT NOT NULL DEFAULT 0,
+	amount INT NOT NULL DEFAULT 0,
+	max_discount INT NOT NULL DEFAULT 0,
+	min_spend INT NOT NULL DEFAULT 0,
+	usage_limit INT NOT NULL DEFAULT 0,
+	per_customer_limit INT NOT NULL DEFAULT 0,
+	active BOOLEAN NOT NULL DEFAULT TRUE,
+	expires_at TIMESTAMP,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per checkout that used a voucher; the usage limits are checked
+-- against these while the voucher row is locked
+CREATE TABLE IF NOT EXISTS voucher_redemptions (
+	id SERIAL PRIMARY KEY,
+	voucher_id INT NOT NULL REFERENCES vouchers(id) ON DELETE CASCADE,
+	transaction_id INT NOT NULL UNIQUE REFERENCES transactions(id) ON DELETE CASCADE,
+	customer_ref TEXT,
+	amount INT NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_voucher_redemptions_voucher_id ON voucher_redemptions(voucher_id, customer_ref);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS voucher_code TEXT;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS voucher_discount INT NOT NULL DEFAULT 0;
+
+INSERT INTO role_permissions (role, permission) VALUES
+	('admin', 'vouchers:manage'),
+	('manager', 'vouchers:manage')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,11 @@
+DELETE FROM role_permissions WHERE permission IN ('customers:manage', 'loyalty:manage');
+
+DROP TABLE IF EXISTS loyalty_point_entries;
+
+DROP INDEX IF EXISTS idx_transactions_customer_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS points_redeemed;
+ALTER TABLE transactions DROP COLUMN IF EXISTS points_earned;
+ALTER TABLE transactions DROP COLUMN IF EXISTS customer_id;
+
+DROP TABLE IF EXISTS loyalty_rules;
+DROP TABLE IF EXISTS customers;
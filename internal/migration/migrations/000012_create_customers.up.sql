@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS customers (
+	id SERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	phone TEXT UNIQUE,
+	member_number TEXT UNIQUE,
+	points INT NOT NULL DEFAULT 0,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS loyalty_rules (
+	id SERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	category_id INT REFERENCES categories(id) ON DELETE CASCADE,
+	spend_amount INT NOT NULL,
+	points INT NOT NULL,
+	active BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS customer_id INT REFERENCES customers(id) ON DELETE SET NULL;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS points_earned INT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS points_redeemed INT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_customer_id ON transactions(customer_id);
+
+-- Every change to a customer's points balance; customers.points is the running total
+CREATE TABLE IF NOT EXISTS loyalty_point_entries (
+	id SERIAL PRIMARY KEY,
+	customer_id INT NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+	transaction_id INT REFERENCES transactions(id) ON DELETE SET NULL,
+	type TEXT NOT NULL,
+	points INT NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_point_entries_customer_id ON loyalty_point_entries(customer_id);
+CREATE INDEX IF NOT EXISTS idx_loyalty_point_entries_transaction_id ON loyalty_point_entries(transaction_id);
+
+INSERT INTO role_permissions (role, permission) VALUES
+	('admin', 'customers:manage'),
+	('manager', 'customers:manage'),
+	('cashier', 'customers:manage'),
+	('admin', 'loyalty:manage'),
+	('manager', 'loyalty:manage')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,39 @@
+-- Append-only ledger of every change to products.stock. product_id and
+-- user_id carry no foreign keys so deleting a product or user never has to
+-- touch the history.
+CREATE TABLE IF NOT EXISTS stock_movements (
+	id SERIAL PRIMARY KEY,
+	product_id INT NOT NULL,
+	type TEXT NOT NULL,
+	quantity INT NOT NULL,
+	stock_after INT NOT NULL,
+	reason TEXT,
+	reference_type TEXT,
+	reference_id INT,
+	user_id INT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product_id ON stock_movements(product_id, created_at);
+
+CREATE OR REPLACE FUNCTION stock_movements_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'stock_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS stock_movements_no_update ON stock_movements;
+CREATE TRIGGER stock_movements_no_update
+	BEFORE UPDATE OR DELETE ON stock_movements
+	FOR EACH ROW EXECUTE FUNCTION stock_movements_append_only();
+
+DROP TRIGGER IF EXISTS stock_movements_no_truncate ON stock_movements;
+CREATE TRIGGER stock_movements_no_truncate
+	BEFORE TRUNCATE ON stock_movements
+	FOR EACH STATEMENT EXECUTE FUNCTION stock_movements_append_only();
+
+-- Open the ledger with the stock each product has today
+INSERT INTO stock_movements (product_id, type, quantity, stock_after, reason)
+SELECT p.id, 'initial', p.stock, p.stock, 'opening balance'
+FROM products p
+WHERE NOT EXISTS (SELECT 1 FROM stock_movements sm WHERE sm.product_id = p.id);
@@ -0,0 +1,2 @@
+-- Free-text note next to the reason code of manual adjustments
+ALTER TABLE stock_movements ADD COLUMN IF NOT EXISTS note TEXT;
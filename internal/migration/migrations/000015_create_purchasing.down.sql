@@ -0,0 +1,10 @@
+DELETE FROM role_permissions WHERE permission = 'purchasing:manage';
+
+DROP TABLE IF EXISTS goods_receipt_lines;
+DROP TABLE IF EXISTS goods_receipts;
+DROP TABLE IF EXISTS purchase_order_lines;
+DROP TABLE IF EXISTS purchase_orders;
+
+ALTER TABLE products DROP COLUMN IF EXISTS cost_price;
+
+DROP TABLE IF EXISTS suppliers;
@@ -0,0 +1,63 @@
+CREATE TABLE IF NOT EXISTS suppliers (
+	id SERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	phone TEXT,
+	email TEXT,
+	address TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- What the shop paid per unit on the last goods receipt
+ALTER TABLE products ADD COLUMN IF NOT EXISTS cost_price INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS purchase_orders (
+	id SERIAL PRIMARY KEY,
+	supplier_id INT NOT NULL REFERENCES suppliers(id) ON DELETE RESTRICT,
+	status TEXT NOT NULL DEFAULT 'open',
+	note TEXT,
+	created_by INT REFERENCES users(id) ON DELETE SET NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	closed_by INT REFERENCES users(id) ON DELETE SET NULL,
+	closed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_supplier_id ON purchase_orders(supplier_id);
+
+-- The product name is kept so the order still reads correctly after the
+-- product is deleted
+CREATE TABLE IF NOT EXISTS purchase_order_lines (
+	id SERIAL PRIMARY KEY,
+	purchase_order_id INT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+	product_id INT REFERENCES products(id) ON DELETE SET NULL,
+	product_name TEXT NOT NULL,
+	quantity INT NOT NULL,
+	quantity_received INT NOT NULL DEFAULT 0,
+	unit_cost INT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_order_lines_purchase_order_id ON purchase_order_lines(purchase_order_id);
+
+CREATE TABLE IF NOT EXISTS goods_receipts (
+	id SERIAL PRIMARY KEY,
+	purchase_order_id INT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+	note TEXT,
+	received_by INT REFERENCES users(id) ON DELETE SET NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_goods_receipts_purchase_order_id ON goods_receipts(purchase_order_id);
+
+CREATE TABLE IF NOT EXISTS goods_receipt_lines (
+	id SERIAL PRIMARY KEY,
+	goods_receipt_id INT NOT NULL REFERENCES goods_receipts(id) ON DELETE CASCADE,
+	purchase_order_line_id INT NOT NULL REFERENCES purchase_order_lines(id) ON DELETE CASCADE,
+	quantity INT NOT NULL,
+	unit_cost INT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_goods_receipt_lines_goods_receipt_id ON goods_receipt_lines(goods_receipt_id);
+
+INSERT INTO role_permissions (role, permission) VALUES
+	('admin', 'purchasing:manage'),
+	('manager', 'purchasing:manage')
+ON CONFLICT DO NOTHING;
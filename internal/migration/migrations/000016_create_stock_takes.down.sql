@@ -0,0 +1,5 @@
+DELETE FROM role_permissions WHERE permission = 'stocktakes:manage';
+
+DROP TABLE IF EXISTS stock_take_counts;
+DROP TABLE IF EXISTS stock_take_lines;
+DROP TABLE IF EXISTS stock_takes;
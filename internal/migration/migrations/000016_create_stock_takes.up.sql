@@ -0,0 +1,41 @@
+-- A stock count of one category, or of the whole store when category_id is
+-- NULL. category_id has no foreign key: the lines fix the scope at opening
+CREATE TABLE IF NOT EXISTS stock_takes (
+	id SERIAL PRIMARY KEY,
+	category_id INT,
+	status TEXT NOT NULL DEFAULT 'open',
+	note TEXT,
+	opened_by INT REFERENCES users(id) ON DELETE SET NULL,
+	opened_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	closed_by INT REFERENCES users(id) ON DELETE SET NULL,
+	closed_at TIMESTAMP
+);
+
+-- One line per product in scope, with its stock when the session was opened
+CREATE TABLE IF NOT EXISTS stock_take_lines (
+	id SERIAL PRIMARY KEY,
+	stock_take_id INT NOT NULL REFERENCES stock_takes(id) ON DELETE CASCADE,
+	product_id INT REFERENCES products(id) ON DELETE SET NULL,
+	product_name TEXT NOT NULL,
+	snapshot_stock INT NOT NULL,
+	UNIQUE (stock_take_id, product_id)
+);
+
+-- What each device counted of a product. stock_at_count is the system stock
+-- when the count came in, so sales made while counting do not show up as
+-- variance
+CREATE TABLE IF NOT EXISTS stock_take_counts (
+	id SERIAL PRIMARY KEY,
+	stock_take_line_id INT NOT NULL REFERENCES stock_take_lines(id) ON DELETE CASCADE,
+	device TEXT NOT NULL,
+	quantity INT NOT NULL,
+	stock_at_count INT NOT NULL,
+	counted_by INT REFERENCES users(id) ON DELETE SET NULL,
+	counted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (stock_take_line_id, device)
+);
+
+INSERT INTO role_permissions (role, permission) VALUES
+	('admin', 'stocktakes:manage'),
+	('manager', 'stocktakes:manage')
+ON CONFLICT DO NOTHING;
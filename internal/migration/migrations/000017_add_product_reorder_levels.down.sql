@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_stock_movements_reference;
+
+ALTER TABLE products DROP COLUMN IF EXISTS reorder_quantity;
+ALTER TABLE products DROP COLUMN IF EXISTS min_stock;
@@ -0,0 +1,7 @@
+-- A product is low on stock once its stock drops below min_stock; zero
+-- turns the alert off. reorder_quantity is how much to order when it does
+ALTER TABLE products ADD COLUMN IF NOT EXISTS min_stock INT NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS reorder_quantity INT NOT NULL DEFAULT 0;
+
+-- Finds the movements of one document, e.g. the sales of a checkout
+CREATE INDEX IF NOT EXISTS idx_stock_movements_reference ON stock_movements(reference_type, reference_id);
@@ -0,0 +1 @@
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS unit_cost;
@@ -0,0 +1,11 @@
+-- The cost of goods sold is snapshotted per line like the price, so margins
+-- do not move when the cost price changes. Lines sold before costs were
+-- tracked take the product's current cost as the best estimate
+ALTER TABLE transaction_details ADD COLUMN IF NOT EXISTS unit_cost INT;
+UPDATE transaction_details td
+SET unit_cost = COALESCE(p.cost_price, 0)
+FROM products p
+WHERE td.product_id = p.id AND td.unit_cost IS NULL;
+UPDATE transaction_details SET unit_cost = 0 WHERE unit_cost IS NULL;
+ALTER TABLE transaction_details ALTER COLUMN unit_cost SET DEFAULT 0;
+ALTER TABLE transaction_details ALTER COLUMN unit_cost SET NOT NULL;
@@ -0,0 +1,6 @@
+DELETE FROM role_permissions WHERE permission = 'reports:close';
+
+DROP TABLE IF EXISTS z_report_taxes;
+DROP TABLE IF EXISTS z_report_payments;
+DROP TABLE IF EXISTS z_reports;
+DROP FUNCTION IF EXISTS z_reports_immutable();
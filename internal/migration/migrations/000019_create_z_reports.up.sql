@@ -0,0 +1,82 @@
+-- A Z report closes a business day. Its totals are frozen when it is taken,
+-- z_number runs without gaps, and once a day is closed no further sales or
+-- refunds can be written into it. closed_by carries no foreign key so
+-- deleting a user never has to touch a closed day.
+CREATE TABLE IF NOT EXISTS z_reports (
+	id SERIAL PRIMARY KEY,
+	z_number INT NOT NULL UNIQUE,
+	business_date DATE NOT NULL UNIQUE,
+	gross_sales INT NOT NULL,
+	total_refund INT NOT NULL,
+	net_sales INT NOT NULL,
+	transaction_count INT NOT NULL,
+	void_count INT NOT NULL,
+	refund_count INT NOT NULL,
+	total_tax INT NOT NULL,
+	total_discount INT NOT NULL,
+	note TEXT,
+	closed_by INT,
+	closed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS z_report_payments (
+	id SERIAL PRIMARY KEY,
+	z_report_id INT NOT NULL REFERENCES z_reports(id),
+	method TEXT NOT NULL,
+	amount INT NOT NULL,
+	transaction_count INT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS z_report_taxes (
+	id SERIAL PRIMARY KEY,
+	z_report_id INT NOT NULL REFERENCES z_reports(id),
+	tax_name TEXT NOT NULL,
+	rate_bp INT NOT NULL,
+	inclusive BOOLEAN NOT NULL,
+	base INT NOT NULL,
+	tax INT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_z_report_payments_report ON z_report_payments(z_report_id);
+CREATE INDEX IF NOT EXISTS idx_z_report_taxes_report ON z_report_taxes(z_report_id);
+
+CREATE OR REPLACE FUNCTION z_reports_immutable() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION '% is immutable', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS z_reports_no_update ON z_reports;
+CREATE TRIGGER z_reports_no_update
+	BEFORE UPDATE OR DELETE ON z_reports
+	FOR EACH ROW EXECUTE FUNCTION z_reports_immutable();
+
+DROP TRIGGER IF EXISTS z_reports_no_truncate ON z_reports;
+CREATE TRIGGER z_reports_no_truncate
+	BEFORE TRUNCATE ON z_reports
+	FOR EACH STATEMENT EXECUTE FUNCTION z_reports_immutable();
+
+DROP TRIGGER IF EXISTS z_report_payments_no_update ON z_report_payments;
+CREATE TRIGGER z_report_payments_no_update
+	BEFORE UPDATE OR DELETE ON z_report_payments
+	FOR EACH ROW EXECUTE FUNCTION z_reports_immutable();
+
+DROP TRIGGER IF EXISTS z_report_payments_no_truncate ON z_report_payments;
+CREATE TRIGGER z_report_payments_no_truncate
+	BEFORE TRUNCATE ON z_report_payments
+	FOR EACH STATEMENT EXECUTE FUNCTION z_reports_immutable();
+
+DROP TRIGGER IF EXISTS z_report_taxes_no_update ON z_report_taxes;
+CREATE TRIGGER z_report_taxes_no_update
+	BEFORE UPDATE OR DELETE ON z_report_taxes
+	FOR EACH ROW EXECUTE FUNCTION z_reports_immutable();
+
+DROP TRIGGER IF EXISTS z_report_taxes_no_truncate ON z_report_taxes;
+CREATE TRIGGER z_report_taxes_no_truncate
+	BEFORE TRUNCATE ON z_report_taxes
+	FOR EACH STATEMENT EXECUTE FUNCTION z_reports_immutable();
+
+INSERT INTO role_permissions (role, permission) VALUES
+	('admin', 'reports:close'),
+	('manager', 'reports:close')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_refunds_created_at;
+DROP INDEX IF EXISTS idx_transactions_created_at;
+
+ALTER TABLE stock_movements ALTER COLUMN created_at TYPE TIMESTAMP;
+ALTER TABLE refunds ALTER COLUMN created_at TYPE TIMESTAMP;
+ALTER TABLE transactions ALTER COLUMN created_at TYPE TIMESTAMP;
@@ -0,0 +1,10 @@
+-- Sales, refunds and stock movements are stored as instants so reports can
+-- resolve business days in the store's timezone whatever the server's is.
+-- Existing values were written with CURRENT_TIMESTAMP in the server's
+-- timezone and are converted in that same timezone
+ALTER TABLE transactions ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+ALTER TABLE refunds ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+ALTER TABLE stock_movements ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_created_at ON transactions(created_at);
+CREATE INDEX IF NOT EXISTS idx_refunds_created_at ON refunds(created_at);
@@ -0,0 +1,13 @@
+-- Every receipt handed out for a transaction, in any format, so reprints
+-- can be marked as such and audited. print_number runs from 1 (the
+-- original) per transaction. printed_by carries no foreign key so deleting
+-- a user never has to touch the history.
+CREATE TABLE IF NOT EXISTS receipt_prints (
+	id SERIAL PRIMARY KEY,
+	transaction_id INT NOT NULL REFERENCES transactions(id),
+	print_number INT NOT NULL,
+	format TEXT NOT NULL,
+	printed_by INT,
+	printed_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (transaction_id, print_number)
+);
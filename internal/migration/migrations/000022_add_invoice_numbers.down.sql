@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS invoice_sequences;
+
+DROP INDEX IF EXISTS idx_transactions_store_invoice_number;
+ALTER TABLE transactions DROP COLUMN IF EXISTS invoice_number;
+ALTER TABLE transactions DROP COLUMN IF EXISTS store_code;
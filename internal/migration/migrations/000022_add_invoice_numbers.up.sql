@@ -0,0 +1,17 @@
+-- Sales get a human-readable invoice number, unique per store, handed out
+-- without gaps from invoice_sequences. Sales made before numbering was
+-- introduced keep a NULL number.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS store_code TEXT;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS invoice_number TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_store_invoice_number ON transactions (store_code, invoice_number);
+
+-- The last sequence number used per store and period. period is the
+-- business date cut to the finest unit the invoice format shows (e.g.
+-- 2026-03-01 or 2026-03), or empty when the sequence never restarts. The row
+-- stays locked until the sale commits, so a rolled back sale frees its number.
+CREATE TABLE IF NOT EXISTS invoice_sequences (
+	store_code TEXT NOT NULL,
+	period TEXT NOT NULL,
+	last_seq INT NOT NULL,
+	PRIMARY KEY (store_code, period)
+);
@@ -0,0 +1,35 @@
+-- How each refund was handed back, per payment method, so only the cash part
+-- counts against the drawer.
+CREATE TABLE IF NOT EXISTS refund_payments (
+	id SERIAL PRIMARY KEY,
+	refund_id INT NOT NULL REFERENCES refunds(id) ON DELETE CASCADE,
+	method TEXT NOT NULL,
+	amount INT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_refund_payments_refund_id ON refund_payments(refund_id);
+
+-- Refunds issued before tenders were recorded are spread over the money kept
+-- from their sale per method; the rounding residue goes to the largest one
+WITH kept AS (
+	SELECT transaction_id, method, SUM(amount - change_amount) AS amount
+	FROM transaction_payments
+	GROUP BY transaction_id, method
+), shares AS (
+	SELECT rf.id AS refund_id, rf.total_amount, k.method,
+		COALESCE(FLOOR(rf.total_amount * k.amount / NULLIF(SUM(k.amount) OVER (PARTITION BY rf.id), 0)), 0)::int AS amount,
+		ROW_NUMBER() OVER (PARTITION BY rf.id ORDER BY k.amount DESC, k.method) AS rank
+	FROM refunds rf
+	JOIN kept k ON k.transaction_id = rf.transaction_id
+)
+INSERT INTO refund_payments (refund_id, method, amount)
+SELECT refund_id, method,
+	amount + CASE WHEN rank = 1 THEN total_amount - SUM(amount) OVER (PARTITION BY refund_id) ELSE 0 END
+FROM shares;
+
+INSERT INTO refund_payments (refund_id, method, amount)
+SELECT rf.id, 'cash', rf.total_amount
+FROM refunds rf
+WHERE NOT EXISTS (SELECT 1 FROM refund_payments rp WHERE rp.refund_id = rf.id);
+
+DELETE FROM refund_payments WHERE amount = 0;
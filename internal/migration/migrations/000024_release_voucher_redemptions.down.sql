@@ -0,0 +1,5 @@
+ALTER TABLE voucher_redemptions DROP CONSTRAINT IF EXISTS voucher_redemptions_voucher_id_fkey;
+ALTER TABLE voucher_redemptions ADD CONSTRAINT voucher_redemptions_voucher_id_fkey
+	FOREIGN KEY (voucher_id) REFERENCES vouchers(id) ON DELETE CASCADE;
+
+ALTER TABLE voucher_redemptions DROP COLUMN IF EXISTS released_at;
@@ -0,0 +1,14 @@
+-- A void hands its voucher use back: the redemption stays for the record but
+-- is marked released and no longer counts against the usage limits.
+ALTER TABLE voucher_redemptions ADD COLUMN IF NOT EXISTS released_at TIMESTAMPTZ;
+
+UPDATE voucher_redemptions vr
+SET released_at = COALESCE((SELECT MAX(rf.created_at) FROM refunds rf WHERE rf.transaction_id = vr.transaction_id AND rf.type = 'void'), CURRENT_TIMESTAMP)
+FROM transactions t
+WHERE t.id = vr.transaction_id AND t.status = 'voided';
+
+-- Vouchers that have been redeemed can no longer be deleted along with their
+-- history; they are deactivated instead
+ALTER TABLE voucher_redemptions DROP CONSTRAINT IF EXISTS voucher_redemptions_voucher_id_fkey;
+ALTER TABLE voucher_redemptions ADD CONSTRAINT voucher_redemptions_voucher_id_fkey
+	FOREIGN KEY (voucher_id) REFERENCES vouchers(id) ON DELETE RESTRICT;
@@ -0,0 +1,3 @@
+ALTER TABLE z_report_payments DROP COLUMN IF EXISTS refunded;
+
+ALTER TABLE z_reports ALTER COLUMN closed_at TYPE TIMESTAMP;
@@ -0,0 +1,8 @@
+-- Z reports record the close as an instant, like the sales they freeze, and
+-- each payment method carries what was handed back in it that day. Existing
+-- closes were stamped in the server's timezone and are converted in that
+-- same timezone. Reports taken before refunds were frozen per method keep
+-- refunded NULL: their breakdown was never taken and is not made up now
+ALTER TABLE z_reports ALTER COLUMN closed_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE z_report_payments ADD COLUMN IF NOT EXISTS refunded INT;
@@ -0,0 +1,2 @@
+ALTER TABLE promotions ALTER COLUMN ends_at TYPE TIMESTAMP;
+ALTER TABLE promotions ALTER COLUMN starts_at TYPE TIMESTAMP;
@@ -0,0 +1,6 @@
+-- Promotion windows are stored as instants, so a campaign given with its
+-- offset starts and ends at that moment whatever the server's timezone.
+-- Existing values lost their offset when stored and are read in the
+-- server's timezone, as in 000020
+ALTER TABLE promotions ALTER COLUMN starts_at TYPE TIMESTAMPTZ;
+ALTER TABLE promotions ALTER COLUMN ends_at TYPE TIMESTAMPTZ;
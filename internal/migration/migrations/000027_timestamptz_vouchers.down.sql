@@ -0,0 +1 @@
+ALTER TABLE vouchers ALTER COLUMN expires_at TYPE TIMESTAMP;
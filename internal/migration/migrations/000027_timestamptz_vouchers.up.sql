@@ -0,0 +1,5 @@
+-- Voucher expiry is stored as an instant, so a code given with its offset
+-- expires at that moment whatever the server's timezone. Existing values
+-- lost their offset when stored and are read in the server's timezone, as
+-- in 000020
+ALTER TABLE vouchers ALTER COLUMN expires_at TYPE TIMESTAMPTZ;
@@ -0,0 +1,316 @@
+package migration_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"kasir-api/internal/migration"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// fakeDB stands in for PostgreSQL: it keeps schema_migrations and the list of
+// scripts that ran, and rolls both back with the transaction around them. A
+// script containing FAIL fails; one containing COMMIT; first commits what ran
+// before it, as a script managing its own transactions would.
+type fakeDB struct {
+	mu      sync.Mutex
+	rows    map[int64]fakeRow
+	scripts []string
+}
+
+type fakeRow struct {
+	name  string
+	dirty bool
+}
+
+type fakeState struct {
+	rows    map[int64]fakeRow
+	scripts []string
+}
+
+func newFakeDB() *fakeDB {
+	return &fakeDB{rows: map[int64]fakeRow{}}
+}
+
+func (f *fakeDB) snapshot() fakeState {
+	rows := make(map[int64]fakeRow, len(f.rows))
+	for v, r := range f.rows {
+		rows[v] = r
+	}
+	return fakeState{rows: rows, scripts: append([]string(nil), f.scripts...)}
+}
+
+func (f *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: f}, nil }
+func (f *fakeDB) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct {
+	db *fakeDB
+	tx *fakeState
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("fakeDB: prepared statements are not supported")
+}
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	state := c.db.snapshot()
+	c.tx = &state
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	c.tx = nil
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	if c.tx != nil {
+		c.db.rows, c.db.scripts = c.tx.rows, c.tx.scripts
+		c.tx = nil
+	}
+	return nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	rows := c.db.rows
+
+	switch {
+	case strings.Contains(query, "pg_advisory"), strings.Contains(query, "CREATE TABLE IF NOT EXISTS schema_migrations"):
+	case strings.HasPrefix(query, "INSERT INTO schema_migrations (version, name, dirty) VALUES ($1, $2, TRUE)"):
+		version := args[0].Value.(int64)
+		if _, ok := rows[version]; ok {
+			return nil, errors.New("fakeDB: duplicate version")
+		}
+		rows[version] = fakeRow{name: args[1].Value.(string), dirty: true}
+	case strings.Contains(query, "INSERT INTO schema_migrations (version, name, dirty) VALUES ($1, $2, FALSE)"):
+		rows[args[0].Value.(int64)] = fakeRow{name: args[1].Value.(string)}
+	case strings.HasPrefix(query, "UPDATE schema_migrations SET dirty = TRUE"):
+		version := args[0].Value.(int64)
+		rows[version] = fakeRow{name: rows[version].name, dirty: true}
+	case strings.HasPrefix(query, "UPDATE schema_migrations SET dirty = FALSE"):
+		version := args[0].Value.(int64)
+		rows[version] = fakeRow{name: rows[version].name}
+	case strings.HasPrefix(query, "DELETE FROM schema_migrations WHERE version > $1 AND dirty"):
+		for v, r := range rows {
+			if v > args[0].Value.(int64) && r.dirty {
+				delete(rows, v)
+			}
+		}
+	case strings.HasPrefix(query, "DELETE FROM schema_migrations WHERE version = $1"):
+		delete(rows, args[0].Value.(int64))
+	default:
+		if before, _, ok := strings.Cut(query, "COMMIT;"); ok {
+			c.db.scripts = append(c.db.scripts, before)
+			c.tx = nil
+		}
+		if strings.Contains(query, "FAIL") {
+			return nil, errors.New("fakeDB: script failed")
+		}
+		c.db.scripts = append(c.db.scripts, query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if !strings.HasPrefix(query, "SELECT version, name, dirty, applied_at FROM schema_migrations") {
+		return nil, errors.New("fakeDB: unexpected query " + query)
+	}
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	rows := &fakeRows{}
+	for v, r := range c.db.rows {
+		rows.values = append(rows.values, []driver.Value{v, r.name, r.dirty, time.Now()})
+	}
+	return rows, nil
+}
+
+type fakeRows struct {
+	values [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return []string{"version", "name", "dirty", "applied_at"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// applied lists the versions recorded in schema_migrations, with a * after
+// dirty ones.
+func (f *fakeDB) applied() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var versions []string
+	for _, r := range f.rows {
+		s := r.name
+		if r.dirty {
+			s += "*"
+		}
+		versions = append(versions, s)
+	}
+	sort.Strings(versions)
+	return strings.Join(versions, ",")
+}
+
+func newTestMigrator(t *testing.T, fsys fstest.MapFS) (*migration.Migrator, *fakeDB) {
+	t.Helper()
+	fake := newFakeDB()
+	db := sql.OpenDB(fake)
+	t.Cleanup(func() { db.Close() })
+	m, err := migration.NewFromFS(db, fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, fake
+}
+
+var testMigrations = fstest.MapFS{
+	"000001_a.up.sql":   {Data: []byte("CREATE TABLE a (id INT);")},
+	"000001_a.down.sql": {Data: []byte("DROP TABLE a;")},
+	"000002_b.up.sql":   {Data: []byte("CREATE TABLE b (id INT);")},
+	"000002_b.down.sql": {Data: []byte("DROP TABLE b;")},
+	"000003_c.up.sql":   {Data: []byte("CREATE TABLE c (id INT);")},
+	"000003_c.down.sql": {Data: []byte("DROP TABLE c;")},
+}
+
+func TestUpAndDown(t *testing.T) {
+	m, fake := newTestMigrator(t, testMigrations)
+
+	n, err := m.Up()
+	if err != nil || n != 3 {
+		t.Fatalf("expected 3 migrations applied, got %d, %v", n, err)
+	}
+	if got := fake.applied(); got != "a,b,c" {
+		t.Errorf("expected a,b,c applied, got %q", got)
+	}
+	if n, err := m.Up(); err != nil || n != 0 {
+		t.Errorf("expected nothing left to apply, got %d, %v", n, err)
+	}
+
+	n, err = m.Down(2)
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 migrations rolled back, got %d, %v", n, err)
+	}
+	if got := fake.applied(); got != "a" {
+		t.Errorf("expected only a applied, got %q", got)
+	}
+	if last := fake.scripts[len(fake.scripts)-1]; last != "DROP TABLE b;" {
+		t.Errorf("expected b to be rolled back last, got %q", last)
+	}
+}
+
+func TestFailedUpLeavesPreviousVersionClean(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000001_a.up.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+		"000002_b.up.sql": {Data: []byte("CREATE TABLE b (id INT); FAIL")},
+	}
+	m, fake := newTestMigrator(t, fsys)
+
+	n, err := m.Up()
+	if err == nil || n != 1 {
+		t.Fatalf("expected the second migration to fail after one applied, got %d, %v", n, err)
+	}
+	if got := fake.applied(); got != "a" {
+		t.Errorf("expected only a applied and clean, got %q", got)
+	}
+	if len(fake.scripts) != 1 {
+		t.Errorf("expected the failed script to roll back, got %v", fake.scripts)
+	}
+}
+
+func TestFailedDownKeepsVersionApplied(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000001_a.up.sql":   {Data: []byte("CREATE TABLE a (id INT);")},
+		"000001_a.down.sql": {Data: []byte("DROP TABLE a; FAIL")},
+	}
+	m, fake := newTestMigrator(t, fsys)
+
+	if _, err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Down(1); err == nil {
+		t.Fatal("expected the down migration to fail")
+	}
+	if got := fake.applied(); got != "a" {
+		t.Errorf("expected a still applied and clean, got %q", got)
+	}
+}
+
+func TestDirtyStepNeedsForce(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000001_a.up.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+		"000002_b.up.sql": {Data: []byte("CREATE TABLE b (id INT); COMMIT; FAIL")},
+		"000003_c.up.sql": {Data: []byte("CREATE TABLE c (id INT);")},
+	}
+	m, fake := newTestMigrator(t, fsys)
+
+	if _, err := m.Up(); err == nil {
+		t.Fatal("expected the second migration to fail")
+	}
+	if got := fake.applied(); got != "a,b*" {
+		t.Fatalf("expected b left dirty after committing part of it, got %q", got)
+	}
+
+	_, err := m.Up()
+	if !errors.Is(err, migration.ErrDirty) {
+		t.Fatalf("expected ErrDirty, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "'migrate force 2'") || !strings.Contains(err.Error(), "'migrate force 1'") {
+		t.Errorf("expected the error to offer forcing 2 or the previous version 1, got %v", err)
+	}
+	if _, err := m.Down(1); !errors.Is(err, migration.ErrDirty) {
+		t.Errorf("expected down to refuse a dirty schema, got %v", err)
+	}
+
+	// The partial change was undone by hand: go back to 1 and retry 2
+	if err := m.Force(1); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.applied(); got != "a" {
+		t.Fatalf("expected force 1 to forget the dirty attempt, got %q", got)
+	}
+	fsys["000002_b.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE b (id INT);")}
+	m, err = migration.NewFromFS(sql.OpenDB(fake), fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := m.Up(); err != nil || n != 2 {
+		t.Errorf("expected b and c applied after the retry, got %d, %v", n, err)
+	}
+}
+
+func TestForce(t *testing.T) {
+	m, fake := newTestMigrator(t, testMigrations)
+
+	if err := m.Force(2); err != nil {
+		t.Fatal(err)
+	}
+	if got := fake.applied(); got != "b" {
+		t.Errorf("expected b marked applied, got %q", got)
+	}
+	if err := m.Force(0); err != nil {
+		t.Errorf("expected force 0 to be accepted, got %v", err)
+	}
+	if err := m.Force(9); err == nil {
+		t.Error("expected an unknown version to be rejected")
+	}
+}
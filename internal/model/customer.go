@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// Customer is a registered member. Points is the current loyalty balance; it
+// can drop below zero when a refund takes back points already spent.
+type Customer struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	Phone        string    `json:"phone,omitempty"`
+	MemberNumber string    `json:"member_number"`
+	Points       int       `json:"points"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+const (
+	PointEntryEarn    = "earn"
+	PointEntryRedeem  = "redeem"
+	PointEntryReverse = "reverse"
+	PointEntryReturn  = "return"
+)
+
+// PointEntry is one line of a customer's points ledger. Points is positive
+// for points earned or given back by a refund of a sale paid with points, and
+// negative for points redeemed or taken back by a refund.
+type PointEntry struct {
+	ID            int       `json:"id"`
+	CustomerID    int       `json:"customer_id"`
+	TransactionID int       `json:"transaction_id,omitempty"`
+	Type          string    `json:"type"`
+	Points        int       `json:"points"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// LoyaltyRule earns Points for every full SpendAmount paid at checkout. With
+// CategoryID set only spend on that category counts. Active rules stack.
+type LoyaltyRule struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	CategoryID  *int   `json:"category_id,omitempty"`
+	SpendAmount int    `json:"spend_amount"`
+	Points      int    `json:"points"`
+	Active      bool   `json:"active"`
+}
@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// DayReport is the end-of-day summary of one business day. An X report is
+// taken from the live sales and can be printed any number of times; a Z
+// report closes the day and freezes the same totals under the next ZNumber,
+// after which no sales or refunds can be written into the day.
+//
+// GrossSales is every sale rung up that day, including ones voided later.
+// TotalRefund is every refund and void issued that day, so NetSales is what
+// the day actually kept. TotalTax is net of refunds; TotalDiscount is what
+// was given at checkout. Payments break the day's money down per method.
+type DayReport struct {
+	ZNumber          int                `json:"z_number,omitempty"`
+	BusinessDate     string             `json:"business_date"`
+	GrossSales       int                `json:"gross_sales"`
+	TotalRefund      int                `json:"total_refund"`
+	NetSales         int                `json:"net_sales"`
+	TransactionCount int                `json:"transaction_count"`
+	VoidCount        int                `json:"void_count"`
+	RefundCount      int                `json:"refund_count"`
+	TotalTax         int                `json:"total_tax"`
+	TotalDiscount    int                `json:"total_discount"`
+	Payments         []DayReportPayment `json:"payments"`
+	Taxes            []DayReportTax     `json:"taxes"`
+	Note             string             `json:"note,omitempty"`
+	ClosedBy         int                `json:"closed_by,omitempty"`
+	ClosedAt         *time.Time         `json:"closed_at,omitempty"`
+}
+
+// DayReportPayment is the money of one method: Amount is what the day's sales
+// took, net of change, and Refunded what the day's refunds and voids handed
+// back, so Net is what the method holds at the close. Sales voided the same
+// day are left out of both. Z reports taken before refunds were frozen per
+// method have no Refunded or Net; their Amount still includes same-day voids.
+type DayReportPayment struct {
+	Method           string `json:"method"`
+	Amount           int    `json:"amount"`
+	Refunded         *int   `json:"refunded"`
+	Net              *int   `json:"net"`
+	TransactionCount int    `json:"transaction_count"`
+}
+
+// SetRefunded records what the method handed back and the net it leaves.
+func (p *DayReportPayment) SetRefunded(refunded int) {
+	net := p.Amount - refunded
+	p.Refunded = &refunded
+	p.Net = &net
+}
+
+// DayReportTax is the tax collected at one rate; Base is the taxable base.
+type DayReportTax struct {
+	Name      string `json:"name"`
+	RateBP    int    `json:"rate_bp"`
+	Inclusive bool   `json:"inclusive"`
+	Base      int    `json:"base"`
+	Tax       int    `json:"tax"`
+}
+
+// CloseDayRequest takes the Z report of BusinessDate (YYYY-MM-DD), today when
+// empty. ClosedBy comes from the authenticated principal.
+type CloseDayRequest struct {
+	BusinessDate string `json:"business_date,omitempty"`
+	Note         string `json:"note,omitempty"`
+	ClosedBy     int    `json:"-"`
+}
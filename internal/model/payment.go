@@ -0,0 +1,42 @@
+package model
+
+const (
+	PaymentMethodCash       = "cash"
+	PaymentMethodDebitCard  = "debit_card"
+	PaymentMethodCreditCard = "credit_card"
+	PaymentMethodQRIS       = "qris"
+	PaymentMethodEWallet    = "e_wallet"
+	// PaymentMethodPoints spends the customer's loyalty points, one point per
+	// rupiah.
+	PaymentMethodPoints = "points"
+)
+
+// PaymentMethods lists every accepted payment method.
+var PaymentMethods = []string{
+	PaymentMethodCash,
+	PaymentMethodDebitCard,
+	PaymentMethodCreditCard,
+	PaymentMethodQRIS,
+	PaymentMethodEWallet,
+	PaymentMethodPoints,
+}
+
+// Payment is one tender used to settle a transaction. Amount is what the
+// customer handed over; ChangeAmount is what was given back from it, so the
+// money kept is Amount - ChangeAmount. Only cash payments give change.
+type Payment struct {
+	ID            int    `json:"id"`
+	TransactionID int    `json:"transaction_id"`
+	Method        string `json:"method"`
+	Amount        int    `json:"amount"`
+	ChangeAmount  int    `json:"change_amount"`
+	Reference     string `json:"reference,omitempty"`
+}
+
+// PaymentRequest is a tender in the checkout payload. Reference holds the card
+// approval code, QRIS transaction ID or similar for non-cash payments.
+type PaymentRequest struct {
+	Method    string `json:"method"`
+	Amount    int    `json:"amount"`
+	Reference string `json:"reference,omitempty"`
+}
@@ -1,10 +1,35 @@
 package model
 
+// Product is a catalog item. Updates leave Stock alone unless SetStock is
+// true; stock is normally changed through stock adjustments and goods
+// receipts. CostPrice is the weighted average cost of the stock on hand; it
+// is only set by receipts, or on create for stock already held. The product is
+// low on stock once Stock drops below MinStock, when ReorderQuantity is the
+// amount to order; a zero MinStock turns the alert off. UserID is the
+// user creating or editing it, taken from the authenticated principal so
+// stock changes can be attributed.
 type Product struct {
-	ID         int       `json:"id"`
-	Name       string    `json:"name"`
-	Price      int       `json:"price"`
-	Stock      int       `json:"stock"`
-	CategoryID int       `json:"category_id"`
-	Category   *Category `json:"category,omitempty"`
+	ID              int       `json:"id"`
+	Name            string    `json:"name"`
+	Price           int       `json:"price"`
+	Stock           int       `json:"stock"`
+	CostPrice       int       `json:"cost_price"`
+	MinStock        int       `json:"min_stock"`
+	ReorderQuantity int       `json:"reorder_quantity"`
+	CategoryID      int       `json:"category_id"`
+	TaxRateID       *int      `json:"tax_rate_id,omitempty"`
+	Category        *Category `json:"category,omitempty"`
+	SetStock        bool      `json:"-"`
+	UserID          int       `json:"-"`
+}
+
+// LowStockAlert reports a checkout that took a product below its minimum
+// stock. Stock is what was left right after the sale.
+type LowStockAlert struct {
+	ProductID       int    `json:"product_id"`
+	ProductName     string `json:"product_name"`
+	Stock           int    `json:"stock"`
+	MinStock        int    `json:"min_stock"`
+	ReorderQuantity int    `json:"reorder_quantity"`
+	TransactionID   int    `json:"transaction_id"`
 }
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+const (
+	// PromotionPercentage takes PercentBP off every eligible unit.
+	PromotionPercentage = "percentage"
+	// PromotionFixedAmount takes Amount off every eligible unit.
+	PromotionFixedAmount = "fixed_amount"
+	// PromotionBuyXGetY gives GetQty units free for every BuyQty bought; the
+	// cheapest eligible units are the free ones.
+	PromotionBuyXGetY = "buy_x_get_y"
+	// PromotionBundle sells the item quantities listed together for Amount.
+	PromotionBundle = "bundle"
+	// PromotionMinSpend takes PercentBP or Amount off the whole sale once it
+	// reaches MinSpend after the other promotions.
+	PromotionMinSpend = "min_spend"
+)
+
+// Promotion is a discount rule evaluated at checkout while it is active and
+// inside its validity window. Items restrict it to products or categories;
+// percentage, fixed amount and minimum spend promotions without items apply
+// to everything. Lower Priority values are evaluated first, and a unit
+// discounted by one line promotion is not discounted again by another.
+type Promotion struct {
+	ID        int             `json:"id"`
+	Name      string          `json:"name"`
+	Type      string          `json:"type"`
+	PercentBP int             `json:"percent_bp,omitempty"`
+	Amount    int             `json:"amount,omitempty"`
+	BuyQty    int             `json:"buy_qty,omitempty"`
+	GetQty    int             `json:"get_qty,omitempty"`
+	MinSpend  int             `json:"min_spend,omitempty"`
+	Priority  int             `json:"priority"`
+	Active    bool            `json:"active"`
+	StartsAt  *time.Time      `json:"starts_at,omitempty"`
+	EndsAt    *time.Time      `json:"ends_at,omitempty"`
+	Items     []PromotionItem `json:"items"`
+}
+
+// PromotionItem names a product or a whole category a promotion applies to.
+// Quantity is only used by bundles: the number of units of the product that
+// make up one bundle.
+type PromotionItem struct {
+	ProductID  int `json:"product_id,omitempty"`
+	CategoryID int `json:"category_id,omitempty"`
+	Quantity   int `json:"quantity,omitempty"`
+}
+
+// AppliedPromotion records the discount a promotion gave on one transaction
+// line.
+type AppliedPromotion struct {
+	PromotionID         int    `json:"promotion_id,omitempty"`
+	Name                string `json:"name"`
+	TransactionDetailID int    `json:"transaction_detail_id"`
+	Amount              int    `json:"amount"`
+}
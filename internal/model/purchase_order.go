@@ -0,0 +1,104 @@
+package model
+
+import "time"
+
+const (
+	PurchaseOrderStatusOpen              = "open"
+	PurchaseOrderStatusPartiallyReceived = "partially_received"
+	PurchaseOrderStatusReceived          = "received"
+	PurchaseOrderStatusClosed            = "closed"
+)
+
+// PurchaseOrder is stock ordered from a supplier. It stays open for goods
+// receipts until every line is received in full, or until it is closed with
+// the rest still outstanding. TotalCost is the expected cost of the order;
+// ReceivedCost is what the receipts so far came to.
+type PurchaseOrder struct {
+	ID           int                 `json:"id"`
+	SupplierID   int                 `json:"supplier_id"`
+	SupplierName string              `json:"supplier_name"`
+	Status       string              `json:"status"`
+	Note         string              `json:"note,omitempty"`
+	TotalCost    int                 `json:"total_cost"`
+	ReceivedCost int                 `json:"received_cost"`
+	CreatedBy    int                 `json:"created_by,omitempty"`
+	CreatedAt    time.Time           `json:"created_at"`
+	ClosedBy     int                 `json:"closed_by,omitempty"`
+	ClosedAt     *time.Time          `json:"closed_at,omitempty"`
+	Lines        []PurchaseOrderLine `json:"lines,omitempty"`
+	Receipts     []GoodsReceipt      `json:"receipts,omitempty"`
+}
+
+// PurchaseOrderLine is one product on a purchase order at its expected unit
+// cost. ProductID is zero once the product has been deleted.
+type PurchaseOrderLine struct {
+	ID               int    `json:"id"`
+	ProductID        int    `json:"product_id"`
+	ProductName      string `json:"product_name"`
+	Quantity         int    `json:"quantity"`
+	ReceivedQuantity int    `json:"received_quantity"`
+	UnitCost         int    `json:"unit_cost"`
+}
+
+// GoodsReceipt is one delivery booked against a purchase order.
+type GoodsReceipt struct {
+	ID              int                `json:"id"`
+	PurchaseOrderID int                `json:"purchase_order_id"`
+	Note            string             `json:"note,omitempty"`
+	ReceivedBy      int                `json:"received_by,omitempty"`
+	CreatedAt       time.Time          `json:"created_at"`
+	Lines           []GoodsReceiptLine `json:"lines"`
+}
+
+// GoodsReceiptLine is the quantity of one order line delivered, at the unit
+// cost actually paid.
+type GoodsReceiptLine struct {
+	PurchaseOrderLineID int    `json:"purchase_order_line_id"`
+	ProductID           int    `json:"product_id"`
+	ProductName         string `json:"product_name"`
+	Quantity            int    `json:"quantity"`
+	UnitCost            int    `json:"unit_cost"`
+}
+
+type PurchaseOrderItem struct {
+	ProductID int `json:"product_id"`
+	Quantity  int `json:"quantity"`
+	UnitCost  int `json:"unit_cost"`
+}
+
+// PurchaseOrderRequest places an order with a supplier. Each product may
+// appear once. CreatedBy comes from the authenticated principal.
+type PurchaseOrderRequest struct {
+	SupplierID int                 `json:"supplier_id"`
+	Note       string              `json:"note,omitempty"`
+	Items      []PurchaseOrderItem `json:"items"`
+	CreatedBy  int                 `json:"-"`
+}
+
+// GoodsReceiptItem books delivered units of a product on the order. A zero
+// UnitCost means the unit cost expected on the order.
+type GoodsReceiptItem struct {
+	ProductID int `json:"product_id"`
+	Quantity  int `json:"quantity"`
+	UnitCost  int `json:"unit_cost,omitempty"`
+}
+
+// GoodsReceiptRequest books a delivery, which may cover only part of the
+// order. ReceivedBy comes from the authenticated principal.
+type GoodsReceiptRequest struct {
+	Note       string             `json:"note,omitempty"`
+	Items      []GoodsReceiptItem `json:"items"`
+	ReceivedBy int                `json:"-"`
+}
+
+// ClosePurchaseOrderRequest closes an order that will not be delivered in
+// full. ClosedBy comes from the authenticated principal.
+type ClosePurchaseOrderRequest struct {
+	Note     string `json:"note,omitempty"`
+	ClosedBy int    `json:"-"`
+}
+
+type PurchaseOrderFilter struct {
+	SupplierID int
+	Status     string
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+const (
+	ReceiptFormatText   = "text"
+	ReceiptFormatESCPOS = "escpos"
+	ReceiptFormatHTML   = "html"
+)
+
+// ReceiptRequest asks for the receipt of a transaction. Width is the paper
+// width in characters for the text and ESC/POS formats, the configured
+// default when zero. PrintedBy comes from the authenticated principal.
+type ReceiptRequest struct {
+	Format    string
+	Width     int
+	PrintedBy int
+}
+
+// ReceiptPrint records one paper receipt, text or ESC/POS, handed out for a
+// transaction. PrintNumber is 1 for the original and counts up with every
+// reprint; it is 0 on an HTML copy, which is not recorded.
+type ReceiptPrint struct {
+	TransactionID int       `json:"transaction_id"`
+	PrintNumber   int       `json:"print_number"`
+	Format        string    `json:"format"`
+	PrintedBy     int       `json:"printed_by,omitempty"`
+	PrintedAt     time.Time `json:"printed_at"`
+}
@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+const (
+	RefundTypeRefund = "refund"
+	RefundTypeVoid   = "void"
+)
+
+// Refund gives back part or all of a sale. Payments say how TotalAmount was
+// handed back: split over the sale's payment methods in proportion to what
+// was paid with each, so only the cash part leaves the drawer. Points spent
+// on the sale are returned to the customer's balance, not paid out as money.
+type Refund struct {
+	ID            int             `json:"id"`
+	TransactionID int             `json:"transaction_id"`
+	Type          string          `json:"type"`
+	Reason        string          `json:"reason"`
+	TotalAmount   int             `json:"total_amount"`
+	ShiftID       int             `json:"shift_id,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Items         []RefundItem    `json:"items"`
+	Payments      []RefundPayment `json:"payments"`
+}
+
+// RefundPayment is the part of a refund handed back in one payment method.
+type RefundPayment struct {
+	ID       int    `json:"id"`
+	RefundID int    `json:"refund_id"`
+	Method   string `json:"method"`
+	Amount   int    `json:"amount"`
+}
+
+type RefundItem struct {
+	ID                  int    `json:"id"`
+	RefundID            int    `json:"refund_id"`
+	TransactionDetailID int    `json:"transaction_detail_id"`
+	ProductID           int    `json:"product_id"`
+	ProductName         string `json:"product_name,omitempty"`
+	Quantity            int    `json:"quantity"`
+	Amount              int    `json:"amount"`
+	TaxAmount           int    `json:"tax_amount"`
+}
+
+type RefundItemRequest struct {
+	TransactionDetailID int `json:"transaction_detail_id"`
+	Quantity            int `json:"quantity"`
+}
+
+// RefundRequest refunds the listed detail lines. An empty Items list refunds
+// everything that has not been refunded yet. TerminalID is the terminal paying
+// the refund out and UserID who issued it, both taken from the authenticated
+// principal.
+type RefundRequest struct {
+	Reason     string              `json:"reason"`
+	Items      []RefundItemRequest `json:"items"`
+	TerminalID string              `json:"-"`
+	UserID     int                 `json:"-"`
+}
+
+type VoidRequest struct {
+	Reason     string `json:"reason"`
+	TerminalID string `json:"-"`
+	UserID     int    `json:"-"`
+}
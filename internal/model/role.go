@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+type Role struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type APIClient struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	KeyPrefix string    `json:"key_prefix"`
+	KeyHash   string    `json:"-"`
+	Role      string    `json:"role"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type APIClientRequest struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// APIClientCredentials is returned once when an API client is created; the
+// plain key is never stored and cannot be retrieved again.
+type APIClientCredentials struct {
+	APIClient
+	Key string `json:"key"`
+}
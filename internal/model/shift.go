@@ -0,0 +1,80 @@
+package model
+
+import "time"
+
+const (
+	ShiftStatusOpen   = "open"
+	ShiftStatusClosed = "closed"
+)
+
+const (
+	CashMovementPayIn  = "pay_in"
+	CashMovementPayOut = "pay_out"
+)
+
+// Shift is one cash drawer session at a terminal. Sales and refunds rung up
+// at the terminal while the shift is open are attributed to it.
+//
+// ExpectedCash is OpeningFloat + CashSales - CashRefunds + PayIns - PayOuts.
+// CashRefunds is only the cash part of the refunds paid out at the terminal;
+// refunds of card, QRIS or points sales go back the way they were paid.
+// It is fixed when the shift is closed, together with the counted cash and
+// the discrepancy between the two (negative when the drawer is short).
+type Shift struct {
+	ID            int            `json:"id"`
+	TerminalID    string         `json:"terminal_id"`
+	CashierID     int            `json:"cashier_id,omitempty"`
+	CashierName   string         `json:"cashier_name,omitempty"`
+	Status        string         `json:"status"`
+	OpeningFloat  int            `json:"opening_float"`
+	CashSales     int            `json:"cash_sales"`
+	CashRefunds   int            `json:"cash_refunds"`
+	PayIns        int            `json:"pay_ins"`
+	PayOuts       int            `json:"pay_outs"`
+	ExpectedCash  int            `json:"expected_cash"`
+	CountedCash   *int           `json:"counted_cash,omitempty"`
+	Discrepancy   *int           `json:"discrepancy,omitempty"`
+	Note          string         `json:"note,omitempty"`
+	OpenedAt      time.Time      `json:"opened_at"`
+	ClosedAt      *time.Time     `json:"closed_at,omitempty"`
+	ClosedBy      int            `json:"closed_by,omitempty"`
+	CashMovements []CashMovement `json:"cash_movements,omitempty"`
+}
+
+// CashMovement is cash put into (pay_in) or taken out of (pay_out) the drawer
+// outside of a sale, e.g. topping up change or paying a supplier.
+type CashMovement struct {
+	ID        int       `json:"id"`
+	ShiftID   int       `json:"shift_id"`
+	Type      string    `json:"type"`
+	Amount    int       `json:"amount"`
+	Reason    string    `json:"reason"`
+	CreatedBy int       `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OpenShiftRequest opens a shift at the caller's terminal. TerminalID and
+// CashierID come from the authenticated principal.
+type OpenShiftRequest struct {
+	OpeningFloat int    `json:"opening_float"`
+	TerminalID   string `json:"-"`
+	CashierID    int    `json:"-"`
+}
+
+type CashMovementRequest struct {
+	Type      string `json:"type"`
+	Amount    int    `json:"amount"`
+	Reason    string `json:"reason"`
+	CreatedBy int    `json:"-"`
+}
+
+type CloseShiftRequest struct {
+	CountedCash int    `json:"counted_cash"`
+	Note        string `json:"note,omitempty"`
+	ClosedBy    int    `json:"-"`
+}
+
+type ShiftFilter struct {
+	TerminalID string
+	Status     string
+}
@@ -0,0 +1,74 @@
+package model
+
+import "time"
+
+const (
+	StockMovementInitial    = "initial"
+	StockMovementSale       = "sale"
+	StockMovementRefund     = "refund"
+	StockMovementVoid       = "void"
+	StockMovementAdjustment = "adjustment"
+	StockMovementReceiving  = "receiving"
+	StockMovementStockTake  = "stock_take"
+)
+
+const (
+	StockReferenceTransaction  = "transaction"
+	StockReferenceRefund       = "refund"
+	StockReferenceGoodsReceipt = "goods_receipt"
+	StockReferenceStockTake    = "stock_take"
+)
+
+// Reason codes of manual stock adjustments.
+const (
+	StockReasonDamaged     = "damaged"
+	StockReasonExpired     = "expired"
+	StockReasonLost        = "lost"
+	StockReasonFound       = "found"
+	StockReasonInternalUse = "internal_use"
+	StockReasonCorrection  = "correction"
+)
+
+// StockReasonCodes lists the reason codes an adjustment may give.
+var StockReasonCodes = []string{
+	StockReasonDamaged,
+	StockReasonExpired,
+	StockReasonLost,
+	StockReasonFound,
+	StockReasonInternalUse,
+	StockReasonCorrection,
+}
+
+// StockMovement is one entry of the append-only stock ledger. Quantity is the
+// signed change and StockAfter the product's stock once it was applied.
+// ReferenceType and ReferenceID point at the document that caused it, such as
+// a transaction or a refund; UserID is who made the change.
+type StockMovement struct {
+	ID            int       `json:"id"`
+	ProductID     int       `json:"product_id"`
+	Type          string    `json:"type"`
+	Quantity      int       `json:"quantity"`
+	StockAfter    int       `json:"stock_after"`
+	Reason        string    `json:"reason,omitempty"`
+	Note          string    `json:"note,omitempty"`
+	ReferenceType string    `json:"reference_type,omitempty"`
+	ReferenceID   int       `json:"reference_id,omitempty"`
+	UserID        int       `json:"user_id,omitempty"`
+	UserName      string    `json:"user_name,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// StockAdjustmentRequest changes a product's stock by Quantity, negative to
+// write stock off. UserID is taken from the authenticated principal.
+type StockAdjustmentRequest struct {
+	Quantity   int    `json:"quantity"`
+	ReasonCode string `json:"reason_code"`
+	Note       string `json:"note,omitempty"`
+	UserID     int    `json:"-"`
+}
+
+type StockMovementFilter struct {
+	Type      string
+	StartDate string
+	EndDate   string
+}
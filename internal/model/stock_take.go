@@ -0,0 +1,82 @@
+package model
+
+import "time"
+
+const (
+	StockTakeStatusOpen      = "open"
+	StockTakeStatusPosted    = "posted"
+	StockTakeStatusCancelled = "cancelled"
+)
+
+// StockTake is a stock count (stock opname) of one category, or of the whole
+// store when CategoryID is zero. Opening it fixes the products in scope and
+// snapshots their stock. Posting it adjusts the stock of every counted
+// product by its variance; products nobody counted are left alone.
+// ClosedBy and ClosedAt record who posted or cancelled it, and when.
+type StockTake struct {
+	ID           int             `json:"id"`
+	CategoryID   int             `json:"category_id,omitempty"`
+	CategoryName string          `json:"category_name,omitempty"`
+	Status       string          `json:"status"`
+	Note         string          `json:"note,omitempty"`
+	ProductCount int             `json:"product_count"`
+	CountedCount int             `json:"counted_count"`
+	OpenedBy     int             `json:"opened_by,omitempty"`
+	OpenedAt     time.Time       `json:"opened_at"`
+	ClosedBy     int             `json:"closed_by,omitempty"`
+	ClosedAt     *time.Time      `json:"closed_at,omitempty"`
+	Lines        []StockTakeLine `json:"lines,omitempty"`
+}
+
+// StockTakeLine is one product of a stock take. SnapshotStock is its stock
+// when the session was opened. Once counted, CountedQuantity adds up the
+// counts of every device and ExpectedStock is the system stock when the last
+// of them came in, so sales made while the shop was being counted are not
+// mistaken for missing stock. Variance is CountedQuantity - ExpectedStock and
+// VarianceAmount values it at the current cost price.
+type StockTakeLine struct {
+	ProductID       int          `json:"product_id"`
+	ProductName     string       `json:"product_name"`
+	SnapshotStock   int          `json:"snapshot_stock"`
+	CountedQuantity *int         `json:"counted_quantity,omitempty"`
+	ExpectedStock   *int         `json:"expected_stock,omitempty"`
+	Variance        *int         `json:"variance,omitempty"`
+	VarianceAmount  int          `json:"variance_amount"`
+	Counts          []StockCount `json:"counts,omitempty"`
+}
+
+// StockCount is what one device counted of a product. A device counting the
+// same product again replaces its earlier count.
+type StockCount struct {
+	Device       string    `json:"device"`
+	Quantity     int       `json:"quantity"`
+	StockAtCount int       `json:"stock_at_count"`
+	CountedBy    int       `json:"counted_by,omitempty"`
+	CountedAt    time.Time `json:"counted_at"`
+}
+
+// OpenStockTakeRequest opens a stock take; a zero CategoryID counts the whole
+// store. OpenedBy comes from the authenticated principal.
+type OpenStockTakeRequest struct {
+	CategoryID int    `json:"category_id,omitempty"`
+	Note       string `json:"note,omitempty"`
+	OpenedBy   int    `json:"-"`
+}
+
+type StockCountItem struct {
+	ProductID int `json:"product_id"`
+	Quantity  int `json:"quantity"`
+}
+
+// StockCountRequest submits counted quantities. Device and CountedBy come
+// from the authenticated principal: the device is the caller's terminal, or
+// their username when they have none.
+type StockCountRequest struct {
+	Items     []StockCountItem `json:"items"`
+	Device    string           `json:"-"`
+	CountedBy int              `json:"-"`
+}
+
+type StockTakeFilter struct {
+	Status string
+}
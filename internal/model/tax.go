@@ -0,0 +1,13 @@
+package model
+
+// TaxRate is a configurable tax such as PPN. Rate is in basis points
+// (1100 = 11%). Inclusive rates are already contained in the product price;
+// exclusive rates are added on top at checkout.
+//
+// A product uses its own tax rate if it has one, otherwise its category's.
+type TaxRate struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Rate      int    `json:"rate_bp"`
+	Inclusive bool   `json:"inclusive"`
+}
@@ -2,20 +2,68 @@ package model
 
 import "time"
 
+const (
+	TransactionStatusCompleted         = "completed"
+	TransactionStatusPartiallyRefunded = "partially_refunded"
+	TransactionStatusRefunded          = "refunded"
+	TransactionStatusVoided            = "voided"
+)
+
+// Transaction is a completed sale. SubtotalAmount is the sum of the lines at
+// shelf price; TotalAmount takes DiscountAmount off and adds the exclusive
+// taxes on top. DiscountAmount covers both promotions and VoucherDiscount;
+// TaxAmount covers both inclusive and exclusive tax. InvoiceNumber is unique
+// per StoreCode and empty for sales made before invoices were numbered.
 type Transaction struct {
-	ID          int                 `json:"id"`
-	TotalAmount int                 `json:"total_amount"`
-	CreatedAt   time.Time           `json:"created_at"`
-	Details     []TransactionDetail `json:"details"`
+	ID              int                 `json:"id"`
+	InvoiceNumber   string              `json:"invoice_number,omitempty"`
+	StoreCode       string              `json:"store_code,omitempty"`
+	SubtotalAmount  int                 `json:"subtotal_amount"`
+	DiscountAmount  int                 `json:"discount_amount"`
+	TaxAmount       int                 `json:"tax_amount"`
+	TotalAmount     int                 `json:"total_amount"`
+	PaidAmount      int                 `json:"paid_amount"`
+	ChangeAmount    int                 `json:"change_amount"`
+	Status          string              `json:"status"`
+	CashierID       int                 `json:"cashier_id,omitempty"`
+	CashierName     string              `json:"cashier_name,omitempty"`
+	TerminalID      string              `json:"terminal_id,omitempty"`
+	ShiftID         int                 `json:"shift_id,omitempty"`
+	VoucherCode     string              `json:"voucher_code,omitempty"`
+	VoucherDiscount int                 `json:"voucher_discount,omitempty"`
+	CustomerID      int                 `json:"customer_id,omitempty"`
+	PointsEarned    int                 `json:"points_earned,omitempty"`
+	PointsRedeemed  int                 `json:"points_redeemed,omitempty"`
+	CreatedAt       time.Time           `json:"created_at"`
+	Details         []TransactionDetail `json:"details"`
+	Payments        []Payment           `json:"payments"`
+	Promotions      []AppliedPromotion  `json:"promotions,omitempty"`
+	Refunds         []Refund            `json:"refunds,omitempty"`
 }
 
+// TransactionDetail keeps a snapshot of the product name, unit price, category
+// and tax taken at sale time, so later catalog edits do not rewrite history.
+// Subtotal is UnitPrice * Quantity; LineTotal is what the customer paid for
+// the line, i.e. Subtotal less DiscountAmount plus any exclusive tax.
+// ProductID is zero once the product has been deleted.
 type TransactionDetail struct {
-	ID            int    `json:"id"`
-	TransactionID int    `json:"transaction_id"`
-	ProductID     int    `json:"product_id"`
-	ProductName   string `json:"product_name,omitempty"`
-	Quantity      int    `json:"quantity"`
-	Subtotal      int    `json:"subtotal"`
+	ID               int    `json:"id"`
+	TransactionID    int    `json:"transaction_id"`
+	ProductID        int    `json:"product_id"`
+	ProductName      string `json:"product_name,omitempty"`
+	UnitPrice        int    `json:"unit_price"`
+	UnitCost         int    `json:"unit_cost"`
+	CategoryID       int    `json:"category_id,omitempty"`
+	CategoryName     string `json:"category_name,omitempty"`
+	Quantity         int    `json:"quantity"`
+	Subtotal         int    `json:"subtotal"`
+	DiscountAmount   int    `json:"discount_amount"`
+	TaxName          string `json:"tax_name,omitempty"`
+	TaxRate          int    `json:"tax_rate_bp"`
+	TaxInclusive     bool   `json:"tax_inclusive"`
+	TaxAmount        int    `json:"tax_amount"`
+	LineTotal        int    `json:"line_total"`
+	RefundedQuantity int    `json:"refunded_quantity"`
 }
 
 type CheckoutItem struct {
@@ -23,6 +71,67 @@ type CheckoutItem struct {
 	Quantity  int `json:"quantity"`
 }
 
+// CheckoutRequest is the checkout payload. Payments may be left empty for an
+// exact cash sale. CustomerID attaches a registered customer, who earns
+// loyalty points and may pay with them. CustomerRef identifies the customer
+// for vouchers limited per customer; with CustomerID it is the customer's
+// member number and may only be left empty or repeat it. CashierID and TerminalID come from the authenticated
+// principal, never from the request body.
 type CheckoutRequest struct {
-	Items []CheckoutItem `json:"items"`
+	Items       []CheckoutItem   `json:"items"`
+	Payments    []PaymentRequest `json:"payments"`
+	VoucherCode string           `json:"voucher_code,omitempty"`
+	CustomerID  int              `json:"customer_id,omitempty"`
+	CustomerRef string           `json:"customer_ref,omitempty"`
+	CashierID   int              `json:"-"`
+	TerminalID  string           `json:"-"`
+}
+
+// TransactionFilter narrows a transaction listing. Invoice matches any part
+// of the invoice number, ignoring case.
+type TransactionFilter struct {
+	StartDate  string
+	EndDate    string
+	MinAmount  *int
+	MaxAmount  *int
+	ProductID  int
+	CashierID  int
+	TerminalID string
+	ShiftID    int
+	CustomerID int
+	Invoice    string
+	StoreCode  string
+	Page       int
+	Limit      int
+}
+
+const (
+	ReportGroupByCashier  = "cashier"
+	ReportGroupByProduct  = "product"
+	ReportGroupByCategory = "category"
+	ReportGroupByDay      = "day"
+)
+
+const (
+	ReportIntervalHour = "hour"
+	ReportIntervalDay  = "day"
+)
+
+// ReportFilter selects the sales a report covers. Top is how many products
+// the top lists hold and Interval the bucket size of the time series.
+type ReportFilter struct {
+	StartDate  string
+	EndDate    string
+	CashierID  int
+	TerminalID string
+	GroupBy    string
+	Top        int
+	Interval   string
+}
+
+type TransactionList struct {
+	Data  []Transaction `json:"data"`
+	Page  int           `json:"page"`
+	Limit int           `json:"limit"`
+	Total int           `json:"total"`
 }
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// Built-in roles; their permissions are configurable through /roles.
+const (
+	RoleCashier = "cashier"
+	RoleManager = "manager"
+	RoleAdmin   = "admin"
+)
+
+type User struct {
+	ID           int       `json:"id"`
+	Username     string    `json:"username"`
+	Name         string    `json:"name"`
+	Role         string    `json:"role"`
+	Active       bool      `json:"active"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// UserRequest is the payload for creating or updating a user. Password is
+// optional on update and left unchanged when empty.
+type UserRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password,omitempty"`
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	Active   *bool  `json:"active,omitempty"`
+}
+
+// LoginRequest authenticates a user. TerminalID optionally names the till the
+// user is working at; it is carried in the token and stamped on every sale.
+type LoginRequest struct {
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+	TerminalID string `json:"terminal_id,omitempty"`
+}
+
+type LoginResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	User      User      `json:"user"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+const (
+	// VoucherPercentage takes PercentBP off the sale, capped at MaxDiscount
+	// when that is set.
+	VoucherPercentage = "percentage"
+	// VoucherFixedAmount takes Amount off the sale.
+	VoucherFixedAmount = "fixed_amount"
+)
+
+// Voucher is a coupon code redeemed at checkout. Codes are matched case
+// insensitively and stored upper case. A zero UsageLimit or PerCustomerLimit
+// means unlimited; Redeemed counts the checkouts that used the code,
+// leaving out sales voided since.
+type Voucher struct {
+	ID               int        `json:"id"`
+	Code             string     `json:"code"`
+	Type             string     `json:"type"`
+	PercentBP        int        `json:"percent_bp,omitempty"`
+	Amount           int        `json:"amount,omitempty"`
+	MaxDiscount      int        `json:"max_discount,omitempty"`
+	MinSpend         int        `json:"min_spend,omitempty"`
+	UsageLimit       int        `json:"usage_limit"`
+	PerCustomerLimit int        `json:"per_customer_limit"`
+	Active           bool       `json:"active"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	Redeemed         int        `json:"redeemed"`
+}
@@ -0,0 +1,70 @@
+// Package notify delivers low-stock alerts to whoever restocks the shop.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"kasir-api/internal/model"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Notifier delivers low-stock alerts. Implementations must be safe for use
+// by one goroutine at a time; they are called from the background checker.
+type Notifier interface {
+	NotifyLowStock(alerts []model.LowStockAlert) error
+}
+
+// LogNotifier writes alerts to the standard logger.
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) NotifyLowStock(alerts []model.LowStockAlert) error {
+	for _, a := range alerts {
+		log.Printf("low stock: %s (product %d) is down to %d, minimum %d, reorder %d (transaction %d)",
+			a.ProductName, a.ProductID, a.Stock, a.MinStock, a.ReorderQuantity, a.TransactionID)
+	}
+	return nil
+}
+
+// WebhookNotifier posts alerts as JSON to a URL, e.g. a chat integration or
+// the purchasing team's own service.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// WebhookPayload is the body posted by WebhookNotifier.
+type WebhookPayload struct {
+	Event  string                `json:"event"`
+	Alerts []model.LowStockAlert `json:"alerts"`
+}
+
+const EventLowStock = "low_stock"
+
+func (n *WebhookNotifier) NotifyLowStock(alerts []model.LowStockAlert) error {
+	body, err := json.Marshal(WebhookPayload{Event: EventLowStock, Alerts: alerts})
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("low stock webhook returned %s", resp.Status)
+	}
+	return nil
+}
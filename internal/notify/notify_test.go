@@ -0,0 +1,45 @@
+package notify_test
+
+import (
+	"encoding/json"
+	"kasir-api/internal/model"
+	"kasir-api/internal/notify"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookNotifierPostsAlerts(t *testing.T) {
+	var got notify.WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("unexpected content type %q", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode webhook body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	alerts := []model.LowStockAlert{{ProductID: 3, ProductName: "Beras 5kg", Stock: 4, MinStock: 5, ReorderQuantity: 20, TransactionID: 81}}
+	if err := notify.NewWebhookNotifier(server.URL).NotifyLowStock(alerts); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Event != notify.EventLowStock || len(got.Alerts) != 1 || got.Alerts[0] != alerts[0] {
+		t.Errorf("unexpected payload: %+v", got)
+	}
+}
+
+func TestWebhookNotifierReportsFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := notify.NewWebhookNotifier(server.URL).NotifyLowStock([]model.LowStockAlert{{ProductID: 3}})
+	if err == nil {
+		t.Error("expected an error for a failed webhook call")
+	}
+}
@@ -0,0 +1,118 @@
+package payment
+
+import (
+	"errors"
+	"fmt"
+	"kasir-api/internal/model"
+)
+
+var ErrInvalidPayment = errors.New("invalid payment")
+
+// Settle checks the tenders offered for a sale of total and works out the
+// change. With no tenders the sale is taken as paid in exact cash.
+//
+// Non-cash tenders are charged exactly, so together they may not exceed the
+// total; any overpayment has to come from cash and is handed back as change,
+// taken from the last cash tender first.
+func Settle(total int, tenders []model.PaymentRequest) ([]model.Payment, int, error) {
+	if len(tenders) == 0 {
+		return []model.Payment{{Method: model.PaymentMethodCash, Amount: total}}, 0, nil
+	}
+
+	payments := make([]model.Payment, 0, len(tenders))
+	tendered, nonCash := 0, 0
+	for _, t := range tenders {
+		if !isKnownMethod(t.Method) {
+			return nil, 0, fmt.Errorf("%w: unknown payment method %q", ErrInvalidPayment, t.Method)
+		}
+		if t.Amount <= 0 {
+			return nil, 0, fmt.Errorf("%w: payment amount must be greater than zero", ErrInvalidPayment)
+		}
+		tendered += t.Amount
+		if t.Method != model.PaymentMethodCash {
+			nonCash += t.Amount
+		}
+		payments = append(payments, model.Payment{Method: t.Method, Amount: t.Amount, Reference: t.Reference})
+	}
+
+	if tendered < total {
+		return nil, 0, fmt.Errorf("%w: tendered amount %d is less than total %d", ErrInvalidPayment, tendered, total)
+	}
+	if nonCash > total {
+		return nil, 0, fmt.Errorf("%w: non-cash payments of %d exceed total %d", ErrInvalidPayment, nonCash, total)
+	}
+
+	change := tendered - total
+	remaining := change
+	for i := len(payments) - 1; i >= 0 && remaining > 0; i-- {
+		if payments[i].Method != model.PaymentMethodCash {
+			continue
+		}
+		given := min(payments[i].Amount, remaining)
+		payments[i].ChangeAmount = given
+		remaining -= given
+	}
+
+	return payments, change, nil
+}
+
+// SplitRefund spreads a refund of amount over the methods a sale was paid
+// with, in proportion to what each still holds, so money goes back the way it
+// came in. held lists per method the money kept from the sale less what
+// earlier refunds handed back; a refund of everything left returns exactly
+// held. Anything beyond held, such as a sale without recorded payments, is
+// refunded in cash.
+func SplitRefund(amount int, held []model.RefundPayment) []model.RefundPayment {
+	total := 0
+	for _, h := range held {
+		total += max(h.Amount, 0)
+	}
+
+	split := make([]model.RefundPayment, len(held))
+	given := 0
+	for i, h := range held {
+		split[i].Method = h.Method
+		if total > 0 && h.Amount > 0 {
+			split[i].Amount = min(amount, total) * h.Amount / total
+			given += split[i].Amount
+		}
+	}
+	// Rounding leftovers go to the methods in order, never beyond what they hold
+	for i := 0; i < len(split) && given < amount; i++ {
+		extra := min(held[i].Amount-split[i].Amount, amount-given)
+		if extra > 0 {
+			split[i].Amount += extra
+			given += extra
+		}
+	}
+	if given < amount {
+		split = addRefund(split, model.PaymentMethodCash, amount-given)
+	}
+
+	refunds := split[:0]
+	for _, s := range split {
+		if s.Amount > 0 {
+			refunds = append(refunds, s)
+		}
+	}
+	return refunds
+}
+
+func addRefund(split []model.RefundPayment, method string, amount int) []model.RefundPayment {
+	for i := range split {
+		if split[i].Method == method {
+			split[i].Amount += amount
+			return split
+		}
+	}
+	return append(split, model.RefundPayment{Method: method, Amount: amount})
+}
+
+func isKnownMethod(method string) bool {
+	for _, m := range model.PaymentMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
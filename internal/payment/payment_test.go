@@ -0,0 +1,93 @@
+package payment_test
+
+import (
+	"errors"
+	"fmt"
+	"kasir-api/internal/model"
+	"kasir-api/internal/payment"
+	"testing"
+)
+
+func TestSettleDefaultsToExactCash(t *testing.T) {
+	payments, change, err := payment.Settle(15000, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change != 0 || len(payments) != 1 || payments[0].Method != model.PaymentMethodCash || payments[0].Amount != 15000 {
+		t.Errorf("unexpected settlement: %+v, change %d", payments, change)
+	}
+}
+
+func TestSettleGivesChangeFromCash(t *testing.T) {
+	payments, change, err := payment.Settle(47000, []model.PaymentRequest{
+		{Method: model.PaymentMethodQRIS, Amount: 20000, Reference: "QR-1"},
+		{Method: model.PaymentMethodCash, Amount: 50000},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change != 23000 {
+		t.Errorf("expected change 23000, got %d", change)
+	}
+	if payments[0].ChangeAmount != 0 || payments[1].ChangeAmount != 23000 {
+		t.Errorf("change should come from the cash tender: %+v", payments)
+	}
+}
+
+func TestSettleRejectsInvalidTenders(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int
+		tenders []model.PaymentRequest
+	}{
+		{"short", 10000, []model.PaymentRequest{{Method: model.PaymentMethodCash, Amount: 5000}}},
+		{"card overpays", 10000, []model.PaymentRequest{{Method: model.PaymentMethodDebitCard, Amount: 12000}}},
+		{"unknown method", 10000, []model.PaymentRequest{{Method: "cheque", Amount: 10000}}},
+		{"zero amount", 0, []model.PaymentRequest{{Method: model.PaymentMethodCash, Amount: 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := payment.Settle(tt.total, tt.tenders)
+			if !errors.Is(err, payment.ErrInvalidPayment) {
+				t.Errorf("expected ErrInvalidPayment, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSplitRefund(t *testing.T) {
+	held := []model.RefundPayment{
+		{Method: model.PaymentMethodQRIS, Amount: 20000},
+		{Method: model.PaymentMethodCash, Amount: 10001},
+	}
+
+	tests := []struct {
+		name   string
+		amount int
+		held   []model.RefundPayment
+		want   string
+	}{
+		{"proportional", 9000, held, "[{qris 6000} {cash 3000}]"},
+		{"rounding leftover", 10000, held, "[{qris 6667} {cash 3333}]"},
+		{"everything left", 30001, held, "[{qris 20000} {cash 10001}]"},
+		{"method used up", 5000, []model.RefundPayment{{Method: model.PaymentMethodDebitCard, Amount: 0}, {Method: model.PaymentMethodCash, Amount: 8000}}, "[{cash 5000}]"},
+		{"no payments recorded", 7000, nil, "[{cash 7000}]"},
+		{"more than held", 12000, []model.RefundPayment{{Method: model.PaymentMethodQRIS, Amount: 10000}}, "[{qris 10000} {cash 2000}]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			split := payment.SplitRefund(tt.amount, tt.held)
+			got := "["
+			for i, s := range split {
+				if i > 0 {
+					got += " "
+				}
+				got += fmt.Sprintf("{%s %d}", s.Method, s.Amount)
+			}
+			got += "]"
+			if got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package pricing
+
+import "kasir-api/internal/model"
+
+// EarnedPoints works out the loyalty points a sale earns. lineTotals is what
+// was charged for each of lines; the part of the sale paid with points,
+// paidWithPoints, is taken off every rule's spend pro rata so points do not
+// earn points.
+func EarnedPoints(lines []Line, lineTotals []int, paidWithPoints int, rules []model.LoyaltyRule) int {
+	total := 0
+	for _, t := range lineTotals {
+		total += t
+	}
+	if total <= 0 {
+		return 0
+	}
+
+	points := 0
+	for _, rule := range rules {
+		if rule.SpendAmount <= 0 || rule.Points <= 0 {
+			continue
+		}
+		spend := 0
+		for i, l := range lines {
+			if rule.CategoryID == nil || *rule.CategoryID == l.CategoryID {
+				spend += lineTotals[i]
+			}
+		}
+		spend -= paidWithPoints * spend / total
+		points += spend / rule.SpendAmount * rule.Points
+	}
+	return points
+}
@@ -0,0 +1,30 @@
+package pricing_test
+
+import (
+	"kasir-api/internal/model"
+	"kasir-api/internal/pricing"
+	"testing"
+)
+
+func TestEarnedPoints(t *testing.T) {
+	drinks := 2
+	lines := []pricing.Line{
+		{ProductID: 1, CategoryID: 1, UnitPrice: 25000, Quantity: 2},
+		{ProductID: 2, CategoryID: drinks, UnitPrice: 15000, Quantity: 2},
+	}
+	lineTotals := []int{50000, 30000}
+	rules := []model.LoyaltyRule{
+		{SpendAmount: 10000, Points: 100},
+		{SpendAmount: 10000, Points: 50, CategoryID: &drinks},
+	}
+
+	// 800 for the 80000 spent plus 150 for the 30000 on drinks
+	if got := pricing.EarnedPoints(lines, lineTotals, 0, rules); got != 950 {
+		t.Errorf("expected 950 points, got %d", got)
+	}
+
+	// A quarter paid with points: 60000 and 22500 left to earn on
+	if got := pricing.EarnedPoints(lines, lineTotals, 20000, rules); got != 700 {
+		t.Errorf("expected 700 points, got %d", got)
+	}
+}
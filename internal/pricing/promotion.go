@@ -0,0 +1,267 @@
+package pricing
+
+import (
+	"kasir-api/internal/model"
+	"sort"
+)
+
+// Line is a checkout line as seen by the promotion engine.
+type Line struct {
+	ProductID  int
+	CategoryID int
+	UnitPrice  int
+	Quantity   int
+}
+
+func (l Line) subtotal() int {
+	return l.UnitPrice * l.Quantity
+}
+
+// Discount is what one promotion takes off one line. Line and Promotion are
+// indexes into the slices given to ApplyPromotions.
+type Discount struct {
+	Line      int
+	Promotion int
+	Amount    int
+}
+
+// ApplyPromotions works out the discounts promos give on lines. The caller
+// passes only promotions that are currently active.
+//
+// Line promotions run in Priority order (then by ID) and each unit is
+// discounted by at most one of them. Minimum spend promotions run last on what
+// is left to pay; only the one giving the largest discount is applied.
+func ApplyPromotions(lines []Line, promos []model.Promotion) []Discount {
+	order := make([]int, len(promos))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		pa, pb := promos[order[a]], promos[order[b]]
+		if pa.Priority != pb.Priority {
+			return pa.Priority < pb.Priority
+		}
+		return pa.ID < pb.ID
+	})
+
+	e := &engine{lines: lines, promos: promos, free: make([]int, len(lines)), discounted: make([]int, len(lines))}
+	for i, l := range lines {
+		e.free[i] = l.Quantity
+	}
+
+	minSpend := []int{}
+	for _, pi := range order {
+		switch promos[pi].Type {
+		case model.PromotionPercentage, model.PromotionFixedAmount:
+			e.applyPerUnit(pi)
+		case model.PromotionBuyXGetY:
+			e.applyBuyXGetY(pi)
+		case model.PromotionBundle:
+			e.applyBundle(pi)
+		case model.PromotionMinSpend:
+			minSpend = append(minSpend, pi)
+		}
+	}
+	e.applyBestMinSpend(minSpend)
+
+	return e.discounts
+}
+
+type engine struct {
+	lines      []Line
+	promos     []model.Promotion
+	free       []int // units per line not yet discounted by a line promotion
+	discounted []int // discount given per line so far
+	discounts  []Discount
+}
+
+func (e *engine) add(line, promo, amount int) {
+	if amount <= 0 {
+		return
+	}
+	e.discounted[line] += amount
+	e.discounts = append(e.discounts, Discount{Line: line, Promotion: promo, Amount: amount})
+}
+
+func (e *engine) eligible(promo, line int) bool {
+	p, l := e.promos[promo], e.lines[line]
+	if len(p.Items) == 0 {
+		return true
+	}
+	for _, item := range p.Items {
+		if (item.ProductID != 0 && item.ProductID == l.ProductID) || (item.CategoryID != 0 && item.CategoryID == l.CategoryID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *engine) applyPerUnit(promo int) {
+	p := e.promos[promo]
+	for i, l := range e.lines {
+		if e.free[i] == 0 || !e.eligible(promo, i) {
+			continue
+		}
+		units := e.free[i]
+		e.free[i] = 0
+		if p.Type == model.PromotionPercentage {
+			e.add(i, promo, divRound(units*l.UnitPrice*min(p.PercentBP, BasisPoints), BasisPoints))
+		} else {
+			e.add(i, promo, units*min(p.Amount, l.UnitPrice))
+		}
+	}
+}
+
+func (e *engine) applyBuyXGetY(promo int) {
+	p := e.promos[promo]
+	group := p.BuyQty + p.GetQty
+	if p.BuyQty <= 0 || p.GetQty <= 0 {
+		return
+	}
+
+	type unit struct{ line, price int }
+	units := []unit{}
+	for i, l := range e.lines {
+		if e.free[i] == 0 || !e.eligible(promo, i) {
+			continue
+		}
+		for k := 0; k < e.free[i]; k++ {
+			units = append(units, unit{line: i, price: l.UnitPrice})
+		}
+	}
+	sort.SliceStable(units, func(a, b int) bool { return units[a].price > units[b].price })
+
+	// Most expensive first, so the last GetQty units of each group are the
+	// cheapest of that group and go free
+	used := len(units) / group * group
+	perLine := make(map[int]int)
+	for k := 0; k < used; k++ {
+		u := units[k]
+		e.free[u.line]--
+		if k%group >= p.BuyQty {
+			perLine[u.line] += u.price
+		}
+	}
+	for i := range e.lines {
+		e.add(i, promo, perLine[i])
+	}
+}
+
+func (e *engine) applyBundle(promo int) {
+	p := e.promos[promo]
+	if len(p.Items) == 0 {
+		return
+	}
+
+	// Number of complete bundles the free units can make up
+	bundles := -1
+	for _, item := range p.Items {
+		if item.ProductID == 0 || item.Quantity <= 0 {
+			return
+		}
+		available := 0
+		for i, l := range e.lines {
+			if l.ProductID == item.ProductID {
+				available += e.free[i]
+			}
+		}
+		if n := available / item.Quantity; bundles < 0 || n < bundles {
+			bundles = n
+		}
+	}
+	if bundles <= 0 {
+		return
+	}
+
+	taken := make([]int, len(e.lines))
+	normal := 0
+	for _, item := range p.Items {
+		need := bundles * item.Quantity
+		for i, l := range e.lines {
+			if need == 0 {
+				break
+			}
+			if l.ProductID != item.ProductID {
+				continue
+			}
+			take := min(e.free[i]-taken[i], need)
+			taken[i] += take
+			need -= take
+			normal += take * l.UnitPrice
+		}
+	}
+
+	discount := normal - bundles*p.Amount
+	if discount <= 0 {
+		return
+	}
+
+	weights := make([]int, len(e.lines))
+	for i, l := range e.lines {
+		e.free[i] -= taken[i]
+		weights[i] = taken[i] * l.UnitPrice
+	}
+	for i, amount := range allocate(discount, weights) {
+		e.add(i, promo, amount)
+	}
+}
+
+func (e *engine) applyBestMinSpend(candidates []int) {
+	best, bestAmount := -1, 0
+	var bestWeights []int
+	for _, pi := range candidates {
+		p := e.promos[pi]
+		weights := make([]int, len(e.lines))
+		spend := 0
+		for i, l := range e.lines {
+			if e.eligible(pi, i) {
+				weights[i] = l.subtotal() - e.discounted[i]
+				spend += weights[i]
+			}
+		}
+		if spend <= 0 || spend < p.MinSpend {
+			continue
+		}
+
+		amount := min(p.Amount, spend)
+		if p.PercentBP > 0 {
+			amount = divRound(spend*min(p.PercentBP, BasisPoints), BasisPoints)
+		}
+		if amount > bestAmount {
+			best, bestAmount, bestWeights = pi, amount, weights
+		}
+	}
+	if best < 0 {
+		return
+	}
+
+	for i, amount := range allocate(bestAmount, bestWeights) {
+		e.add(i, best, amount)
+	}
+}
+
+// allocate splits amount over weights proportionally without giving any entry
+// more than its weight; rounding leftovers go to the last entries.
+func allocate(amount int, weights []int) []int {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	shares := make([]int, len(weights))
+	if total <= 0 {
+		return shares
+	}
+	amount = min(amount, total)
+
+	given := 0
+	for i, w := range weights {
+		shares[i] = amount * w / total
+		given += shares[i]
+	}
+	for i := len(weights) - 1; i >= 0 && given < amount; i-- {
+		extra := min(weights[i]-shares[i], amount-given)
+		shares[i] += extra
+		given += extra
+	}
+	return shares
+}
@@ -0,0 +1,102 @@
+package pricing_test
+
+import (
+	"kasir-api/internal/model"
+	"kasir-api/internal/pricing"
+	"testing"
+)
+
+// lineDiscounts sums the discounts per line.
+func lineDiscounts(n int, discounts []pricing.Discount) []int {
+	sums := make([]int, n)
+	for _, d := range discounts {
+		sums[d.Line] += d.Amount
+	}
+	return sums
+}
+
+func TestPercentageAndFixedPromotions(t *testing.T) {
+	lines := []pricing.Line{
+		{ProductID: 1, CategoryID: 10, UnitPrice: 10000, Quantity: 2},
+		{ProductID: 2, CategoryID: 20, UnitPrice: 5000, Quantity: 3},
+	}
+	promos := []model.Promotion{
+		{ID: 1, Type: model.PromotionPercentage, PercentBP: 1000, Items: []model.PromotionItem{{CategoryID: 10}}},
+		{ID: 2, Type: model.PromotionFixedAmount, Amount: 500, Items: []model.PromotionItem{{ProductID: 2}}},
+	}
+
+	got := lineDiscounts(len(lines), pricing.ApplyPromotions(lines, promos))
+	if got[0] != 2000 || got[1] != 1500 {
+		t.Errorf("expected discounts [2000 1500], got %v", got)
+	}
+}
+
+func TestLinePromotionsDoNotStack(t *testing.T) {
+	lines := []pricing.Line{{ProductID: 1, UnitPrice: 10000, Quantity: 1}}
+	promos := []model.Promotion{
+		{ID: 1, Type: model.PromotionPercentage, PercentBP: 5000, Priority: 2},
+		{ID: 2, Type: model.PromotionFixedAmount, Amount: 1000, Priority: 1},
+	}
+
+	discounts := pricing.ApplyPromotions(lines, promos)
+	if len(discounts) != 1 || discounts[0].Promotion != 1 || discounts[0].Amount != 1000 {
+		t.Errorf("expected only the higher priority promotion, got %+v", discounts)
+	}
+}
+
+func TestBuyXGetYFreesCheapestUnits(t *testing.T) {
+	lines := []pricing.Line{
+		{ProductID: 1, CategoryID: 5, UnitPrice: 8000, Quantity: 2},
+		{ProductID: 2, CategoryID: 5, UnitPrice: 6000, Quantity: 2},
+	}
+	promos := []model.Promotion{
+		{ID: 1, Type: model.PromotionBuyXGetY, BuyQty: 1, GetQty: 1, Items: []model.PromotionItem{{CategoryID: 5}}},
+	}
+
+	got := lineDiscounts(len(lines), pricing.ApplyPromotions(lines, promos))
+	if got[0] != 8000 || got[1] != 6000 {
+		t.Errorf("expected one unit of each line free, got %v", got)
+	}
+}
+
+func TestBundlePrice(t *testing.T) {
+	lines := []pricing.Line{
+		{ProductID: 1, UnitPrice: 15000, Quantity: 3},
+		{ProductID: 2, UnitPrice: 5000, Quantity: 1},
+	}
+	promos := []model.Promotion{
+		{ID: 1, Type: model.PromotionBundle, Amount: 17000, Items: []model.PromotionItem{
+			{ProductID: 1, Quantity: 1},
+			{ProductID: 2, Quantity: 1},
+		}},
+	}
+
+	got := lineDiscounts(len(lines), pricing.ApplyPromotions(lines, promos))
+	if got[0]+got[1] != 3000 {
+		t.Errorf("expected a 3000 bundle discount, got %v", got)
+	}
+	if got[0] != 2250 || got[1] != 750 {
+		t.Errorf("expected the discount split by value, got %v", got)
+	}
+}
+
+func TestMinSpendUsesBestRuleAfterLineDiscounts(t *testing.T) {
+	lines := []pricing.Line{{ProductID: 1, UnitPrice: 50000, Quantity: 2}}
+	promos := []model.Promotion{
+		{ID: 1, Type: model.PromotionPercentage, PercentBP: 1000},
+		{ID: 2, Type: model.PromotionMinSpend, MinSpend: 100000, Amount: 20000},
+		{ID: 3, Type: model.PromotionMinSpend, MinSpend: 75000, Amount: 5000},
+	}
+
+	discounts := pricing.ApplyPromotions(lines, promos)
+	total := 0
+	for _, d := range discounts {
+		total += d.Amount
+		if d.Promotion == 1 {
+			t.Errorf("min spend of 100000 should not be reached after the 10%% discount")
+		}
+	}
+	if total != 15000 {
+		t.Errorf("expected 10000 + 5000 discount, got %d (%+v)", total, discounts)
+	}
+}
@@ -0,0 +1,42 @@
+// Package pricing holds the pure money calculations used at checkout and on
+// refunds. Amounts are whole rupiah; rounding is half up.
+package pricing
+
+// BasisPoints is the denominator of tax rates: a rate of 1100 is 11%.
+const BasisPoints = 10000
+
+// Tax is a tax rate applied to a line. Inclusive rates are already contained
+// in the shelf price; exclusive rates are added on top of it.
+type Tax struct {
+	Rate      int
+	Inclusive bool
+}
+
+// ApplyTax returns the tax on a line worth amount at shelf prices and what
+// the customer pays for it.
+func ApplyTax(amount int, t Tax) (tax, total int) {
+	if t.Rate <= 0 || amount <= 0 {
+		return 0, amount
+	}
+	if t.Inclusive {
+		net := divRound(amount*BasisPoints, BasisPoints+t.Rate)
+		return amount - net, amount
+	}
+	tax = divRound(amount*t.Rate, BasisPoints)
+	return tax, amount + tax
+}
+
+// Prorate returns the part of amount, spread over sold units, that belongs to
+// qty of them. already is what earlier calls handed out for the refunded
+// units before; taking the last units returns whatever is left so rounding
+// never leaves a residue behind.
+func Prorate(amount, sold, refunded, already, qty int) int {
+	if refunded+qty >= sold {
+		return amount - already
+	}
+	return amount * qty / sold
+}
+
+func divRound(n, d int) int {
+	return (n + d/2) / d
+}
@@ -0,0 +1,43 @@
+package pricing_test
+
+import (
+	"kasir-api/internal/pricing"
+	"testing"
+)
+
+func TestApplyTax(t *testing.T) {
+	tests := []struct {
+		name      string
+		amount    int
+		tax       pricing.Tax
+		wantTax   int
+		wantTotal int
+	}{
+		{"untaxed", 15000, pricing.Tax{}, 0, 15000},
+		{"exclusive 11%", 15000, pricing.Tax{Rate: 1100}, 1650, 16650},
+		{"exclusive rounds half up", 4545, pricing.Tax{Rate: 1100}, 500, 5045},
+		{"inclusive 11%", 11100, pricing.Tax{Rate: 1100, Inclusive: true}, 1100, 11100},
+		{"inclusive rounds", 10000, pricing.Tax{Rate: 1100, Inclusive: true}, 991, 10000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tax, total := pricing.ApplyTax(tt.amount, tt.tax)
+			if tax != tt.wantTax || total != tt.wantTotal {
+				t.Errorf("ApplyTax(%d, %+v) = %d, %d; want %d, %d", tt.amount, tt.tax, tax, total, tt.wantTax, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestProrateLeavesNoResidue(t *testing.T) {
+	// 3 units for 10000: refunding them one by one must add back up to 10000
+	const amount, sold = 10000, 3
+	refunded, already := 0, 0
+	for i := 0; i < sold; i++ {
+		already += pricing.Prorate(amount, sold, refunded, already, 1)
+		refunded++
+	}
+	if already != amount {
+		t.Errorf("refunded %d in total, want %d", already, amount)
+	}
+}
@@ -0,0 +1,28 @@
+package pricing
+
+import "kasir-api/internal/model"
+
+// ApplyVoucher works out what voucher v takes off each line. It applies to
+// what is left to pay once the discounts already given per line are taken off,
+// and is split over the lines in proportion to that. It returns nil when the
+// sale does not reach the voucher's minimum spend.
+func ApplyVoucher(lines []Line, discounted []int, v model.Voucher) []int {
+	weights := make([]int, len(lines))
+	spend := 0
+	for i, l := range lines {
+		weights[i] = max(l.subtotal()-discounted[i], 0)
+		spend += weights[i]
+	}
+	if spend < v.MinSpend {
+		return nil
+	}
+
+	amount := v.Amount
+	if v.Type == model.VoucherPercentage {
+		amount = divRound(spend*min(v.PercentBP, BasisPoints), BasisPoints)
+		if v.MaxDiscount > 0 {
+			amount = min(amount, v.MaxDiscount)
+		}
+	}
+	return allocate(amount, weights)
+}
@@ -0,0 +1,39 @@
+package pricing_test
+
+import (
+	"kasir-api/internal/model"
+	"kasir-api/internal/pricing"
+	"testing"
+)
+
+func TestVoucherAppliesAfterPromotions(t *testing.T) {
+	lines := []pricing.Line{
+		{ProductID: 1, UnitPrice: 10000, Quantity: 3},
+		{ProductID: 2, UnitPrice: 20000, Quantity: 1},
+	}
+	// 10% off the 40000 left to pay, split 20000:20000
+	v := model.Voucher{Type: model.VoucherPercentage, PercentBP: 1000}
+	got := pricing.ApplyVoucher(lines, []int{10000, 0}, v)
+	if len(got) != 2 || got[0] != 2000 || got[1] != 2000 {
+		t.Errorf("expected [2000 2000], got %v", got)
+	}
+}
+
+func TestVoucherCapsAndMinimumSpend(t *testing.T) {
+	lines := []pricing.Line{{ProductID: 1, UnitPrice: 50000, Quantity: 2}}
+
+	capped := model.Voucher{Type: model.VoucherPercentage, PercentBP: 5000, MaxDiscount: 15000}
+	if got := pricing.ApplyVoucher(lines, []int{0}, capped); got[0] != 15000 {
+		t.Errorf("expected discount capped at 15000, got %v", got)
+	}
+
+	fixed := model.Voucher{Type: model.VoucherFixedAmount, Amount: 250000}
+	if got := pricing.ApplyVoucher(lines, []int{0}, fixed); got[0] != 100000 {
+		t.Errorf("expected discount limited to the sale, got %v", got)
+	}
+
+	minSpend := model.Voucher{Type: model.VoucherFixedAmount, Amount: 5000, MinSpend: 100000}
+	if got := pricing.ApplyVoucher(lines, []int{1}, minSpend); got != nil {
+		t.Errorf("expected no discount below minimum spend, got %v", got)
+	}
+}
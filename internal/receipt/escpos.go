@@ -0,0 +1,58 @@
+package receipt
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// ESC/POS commands understood by virtually every thermal receipt printer.
+var (
+	escInit        = []byte{0x1b, 0x40}             // ESC @: reset
+	escCodePage437 = []byte{0x1b, 0x74, 0x00}       // ESC t 0: code page PC437
+	escBoldOn      = []byte{0x1b, 0x45, 0x01}       // ESC E 1
+	escBoldOff     = []byte{0x1b, 0x45, 0x00}       // ESC E 0
+	escTallOn      = []byte{0x1d, 0x21, 0x01}       // GS ! 1: double height, same width
+	escTallOff     = []byte{0x1d, 0x21, 0x00}       // GS ! 0
+	escFeed        = []byte{0x1b, 0x64, 0x04}       // ESC d 4: feed four lines
+	escCut         = []byte{0x1d, 0x56, 0x42, 0x00} // GS V 66 0: feed to the cutter and cut
+)
+
+// writeESCPOS sends the text layout with bold and tall lines switched on by
+// printer commands, then feeds and cuts the paper. Characters outside ASCII
+// print as '?', since printers differ in the code pages they carry.
+func writeESCPOS(w io.Writer, lines []line, width int) error {
+	bw := bufio.NewWriter(w)
+	bw.Write(escInit)
+	bw.Write(escCodePage437)
+	for _, l := range lines {
+		if l.Bold {
+			bw.Write(escBoldOn)
+		}
+		if l.Large {
+			bw.Write(escTallOn)
+		}
+		for _, s := range l.layout(width) {
+			bw.WriteString(toASCII(strings.TrimRight(s, " ")))
+			bw.WriteByte('\n')
+		}
+		if l.Large {
+			bw.Write(escTallOff)
+		}
+		if l.Bold {
+			bw.Write(escBoldOff)
+		}
+	}
+	bw.Write(escFeed)
+	bw.Write(escCut)
+	return bw.Flush()
+}
+
+func toASCII(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return '?'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,38 @@
+package receipt
+
+import (
+	"html/template"
+	"io"
+)
+
+// The receipt is a single table with inline styles, which is what email
+// clients render most faithfully.
+var htmlReceipt = template.Must(template.New("receipt").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Struk {{with .Receipt.Transaction.InvoiceNumber}}{{.}}{{else}}{{.Receipt.Transaction.ID}}{{end}}</title>
+</head>
+<body style="margin:0;padding:16px;background:#f4f4f4">
+<table role="presentation" cellpadding="0" cellspacing="0" style="width:100%;max-width:380px;margin:0 auto;padding:16px;background:#ffffff;font-family:'Courier New',Courier,monospace;font-size:14px;color:#222222">
+{{- range .Lines}}
+{{- if .Rule}}
+<tr><td colspan="2" style="padding:6px 0"><div style="border-top:1px dashed #999999"></div></td></tr>
+{{- else if .Center}}
+<tr><td colspan="2" style="text-align:center;padding:1px 0{{if .Bold}};font-weight:bold{{end}}">{{.Left}}</td></tr>
+{{- else}}
+<tr style="{{if .Bold}}font-weight:bold;{{end}}{{if .Large}}font-size:18px;{{end}}"><td style="padding:1px 0{{if .Indent}} 1px 16px{{end}}">{{.Left}}</td><td style="text-align:right;white-space:nowrap;padding:1px 0 1px 12px">{{.Right}}</td></tr>
+{{- end}}
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+func writeHTML(w io.Writer, lines []line, rc Receipt) error {
+	return htmlReceipt.Execute(w, struct {
+		Receipt Receipt
+		Lines   []line
+	}{rc, lines})
+}
@@ -0,0 +1,273 @@
+// Package receipt renders customer receipts for a transaction: plain text for
+// 32 or 48 column printers, a raw ESC/POS stream for thermal printers, and
+// HTML for email. All three are laid out from the same lines, so they always
+// carry the same items and totals.
+package receipt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"kasir-api/internal/model"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	// Width58mm and Width80mm are the characters per line of the common
+	// thermal paper rolls.
+	Width58mm = 32
+	Width80mm = 48
+)
+
+var ErrUnknownFormat = errors.New("unknown receipt format")
+
+// ValidWidth reports whether width is a supported paper width.
+func ValidWidth(width int) bool {
+	return width == Width58mm || width == Width80mm
+}
+
+// Receipt is what a receipt is rendered from; the header and footer
+// templates see it as their data, e.g. {{.Transaction.CashierName}}.
+type Receipt struct {
+	Transaction model.Transaction
+	Print       model.ReceiptPrint
+}
+
+// Reprint reports whether this is not the first receipt of the transaction.
+func (r Receipt) Reprint() bool {
+	return r.Print.PrintNumber > 1
+}
+
+type Renderer struct {
+	header *template.Template
+	footer *template.Template
+	width  int
+}
+
+// New returns a renderer printing the header and footer text/templates
+// above and below every receipt, each of their lines centered. width is the
+// default paper width in characters.
+func New(header, footer string, width int) (*Renderer, error) {
+	if !ValidWidth(width) {
+		return nil, fmt.Errorf("receipt width must be %d or %d characters", Width58mm, Width80mm)
+	}
+	h, err := template.New("header").Parse(header)
+	if err != nil {
+		return nil, fmt.Errorf("receipt header: %w", err)
+	}
+	f, err := template.New("footer").Parse(footer)
+	if err != nil {
+		return nil, fmt.Errorf("receipt footer: %w", err)
+	}
+	return &Renderer{header: h, footer: f, width: width}, nil
+}
+
+// Render writes the receipt in format. width applies to the text and
+// ESC/POS formats; zero means the renderer's default.
+func (r *Renderer) Render(w io.Writer, format string, rc Receipt, width int) error {
+	if width == 0 {
+		width = r.width
+	}
+	if !ValidWidth(width) {
+		return fmt.Errorf("receipt width must be %d or %d characters", Width58mm, Width80mm)
+	}
+	lines, err := r.lines(rc)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case model.ReceiptFormatText:
+		return writeText(w, lines, width)
+	case model.ReceiptFormatESCPOS:
+		return writeESCPOS(w, lines, width)
+	case model.ReceiptFormatHTML:
+		return writeHTML(w, lines, rc)
+	}
+	return fmt.Errorf("%w %q", ErrUnknownFormat, format)
+}
+
+// line is one line of a receipt before layout: Left text with an optional
+// Right-aligned amount, or a centered text, or a rule.
+type line struct {
+	Left, Right  string
+	Center, Rule bool
+	Indent       bool
+	Bold, Large  bool
+}
+
+var methodLabels = map[string]string{
+	model.PaymentMethodCash:       "Tunai",
+	model.PaymentMethodDebitCard:  "Kartu Debit",
+	model.PaymentMethodCreditCard: "Kartu Kredit",
+	model.PaymentMethodQRIS:       "QRIS",
+	model.PaymentMethodEWallet:    "E-Wallet",
+	model.PaymentMethodPoints:     "Poin",
+}
+
+var statusMarkers = map[string]string{
+	model.TransactionStatusVoided:            "*** DIBATALKAN ***",
+	model.TransactionStatusRefunded:          "*** DIREFUND ***",
+	model.TransactionStatusPartiallyRefunded: "*** REFUND SEBAGIAN ***",
+}
+
+const timeLayout = "02/01/2006 15:04"
+
+func (r *Renderer) lines(rc Receipt) ([]line, error) {
+	t := rc.Transaction
+	var lines []line
+
+	header, err := templateLines(r.header, rc)
+	if err != nil {
+		return nil, fmt.Errorf("receipt header: %w", err)
+	}
+	lines = append(lines, header...)
+	lines = append(lines, line{Rule: true})
+
+	if rc.Reprint() {
+		lines = append(lines,
+			line{Left: "*** CETAK ULANG ***", Center: true, Bold: true},
+			line{Left: "Cetakan ke", Right: strconv.Itoa(rc.Print.PrintNumber)},
+			line{Left: "Dicetak", Right: rc.Print.PrintedAt.Format(timeLayout)},
+		)
+	}
+	// Sales made before invoices were numbered show their ID instead
+	if t.InvoiceNumber != "" {
+		lines = append(lines, line{Left: "No. Nota", Right: t.InvoiceNumber})
+	} else {
+		lines = append(lines, line{Left: "No. Transaksi", Right: strconv.Itoa(t.ID)})
+	}
+	lines = append(lines, line{Left: "Tanggal", Right: t.CreatedAt.Format(timeLayout)})
+	if t.CashierName != "" {
+		lines = append(lines, line{Left: "Kasir", Right: t.CashierName})
+	}
+	if t.TerminalID != "" {
+		lines = append(lines, line{Left: "Terminal", Right: t.TerminalID})
+	}
+	if marker, ok := statusMarkers[t.Status]; ok {
+		lines = append(lines, line{Left: marker, Center: true, Bold: true})
+	}
+	lines = append(lines, line{Rule: true})
+
+	promotions := make(map[int][]model.AppliedPromotion)
+	for _, p := range t.Promotions {
+		promotions[p.TransactionDetailID] = append(promotions[p.TransactionDetailID], p)
+	}
+	type taxKey struct {
+		name      string
+		rate      int
+		inclusive bool
+	}
+	var taxKeys []taxKey
+	taxes := make(map[taxKey]int)
+	for _, d := range t.Details {
+		name := d.ProductName
+		if name == "" {
+			name = "Produk #" + strconv.Itoa(d.ProductID)
+		}
+		lines = append(lines,
+			line{Left: name},
+			line{Left: fmt.Sprintf("%d x %s", d.Quantity, formatAmount(d.UnitPrice)), Right: formatAmount(d.Subtotal), Indent: true},
+		)
+		for _, p := range promotions[d.ID] {
+			lines = append(lines, line{Left: p.Name, Right: formatAmount(-p.Amount), Indent: true})
+		}
+		if d.TaxAmount != 0 {
+			k := taxKey{d.TaxName, d.TaxRate, d.TaxInclusive}
+			if _, ok := taxes[k]; !ok {
+				taxKeys = append(taxKeys, k)
+			}
+			taxes[k] += d.TaxAmount
+		}
+	}
+	lines = append(lines, line{Rule: true})
+
+	lines = append(lines, line{Left: "Subtotal", Right: formatAmount(t.SubtotalAmount)})
+	if promo := t.DiscountAmount - t.VoucherDiscount; promo != 0 {
+		lines = append(lines, line{Left: "Diskon", Right: formatAmount(-promo)})
+	}
+	if t.VoucherDiscount != 0 {
+		lines = append(lines, line{Left: "Voucher " + t.VoucherCode, Right: formatAmount(-t.VoucherDiscount)})
+	}
+	for _, k := range taxKeys {
+		label := k.name
+		if label == "" {
+			label = "Pajak"
+		}
+		label += " " + formatRate(k.rate)
+		if k.inclusive {
+			label += " (termasuk)"
+		}
+		lines = append(lines, line{Left: label, Right: formatAmount(taxes[k])})
+	}
+	lines = append(lines, line{Left: "TOTAL", Right: formatAmount(t.TotalAmount), Bold: true, Large: true})
+
+	for _, p := range t.Payments {
+		label, ok := methodLabels[p.Method]
+		if !ok {
+			label = p.Method
+		}
+		lines = append(lines, line{Left: label, Right: formatAmount(p.Amount)})
+	}
+	if t.ChangeAmount != 0 {
+		lines = append(lines, line{Left: "Kembali", Right: formatAmount(t.ChangeAmount)})
+	}
+	if t.PointsRedeemed != 0 {
+		lines = append(lines, line{Left: "Poin dipakai", Right: formatAmount(t.PointsRedeemed)})
+	}
+	if t.PointsEarned != 0 {
+		lines = append(lines, line{Left: "Poin didapat", Right: formatAmount(t.PointsEarned)})
+	}
+
+	footer, err := templateLines(r.footer, rc)
+	if err != nil {
+		return nil, fmt.Errorf("receipt footer: %w", err)
+	}
+	if len(footer) > 0 {
+		lines = append(lines, line{Rule: true})
+		lines = append(lines, footer...)
+	}
+	return lines, nil
+}
+
+// templateLines runs tmpl and centers each line of its output.
+func templateLines(tmpl *template.Template, rc Receipt) ([]line, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, rc); err != nil {
+		return nil, err
+	}
+	text := strings.TrimRight(b.String(), "\n")
+	if text == "" {
+		return nil, nil
+	}
+	var lines []line
+	for _, s := range strings.Split(text, "\n") {
+		lines = append(lines, line{Left: strings.TrimSpace(s), Center: true})
+	}
+	return lines, nil
+}
+
+// formatAmount groups thousands the Indonesian way, e.g. 1.250.000.
+func formatAmount(n int) string {
+	s := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+	var b strings.Builder
+	for i, d := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	return sign + b.String()
+}
+
+// formatRate turns basis points into a percentage, e.g. 1100 into 11% and
+// 1250 into 12,5%.
+func formatRate(bp int) string {
+	s := strconv.FormatFloat(float64(bp)/100, 'f', -1, 64)
+	return strings.Replace(s, ".", ",", 1) + "%"
+}
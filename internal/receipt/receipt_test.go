@@ -0,0 +1,176 @@
+package receipt
+
+import (
+	"bytes"
+	"errors"
+	"kasir-api/internal/model"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func sampleReceipt(printNumber int) Receipt {
+	wib := time.FixedZone("WIB", 7*3600)
+	return Receipt{
+		Transaction: model.Transaction{
+			ID:              42,
+			SubtotalAmount:  95000,
+			DiscountAmount:  15000,
+			TaxAmount:       8800,
+			TotalAmount:     88800,
+			PaidAmount:      100000,
+			ChangeAmount:    11200,
+			Status:          model.TransactionStatusCompleted,
+			CashierName:     "Siti",
+			TerminalID:      "POS-1",
+			VoucherCode:     "HEMAT10",
+			VoucherDiscount: 10000,
+			CreatedAt:       time.Date(2026, 3, 1, 21, 5, 0, 0, wib),
+			Details: []model.TransactionDetail{
+				{ID: 1, ProductID: 3, ProductName: "Beras Premium Pulen Wangi Kemasan Lima Kilogram", UnitPrice: 75000, Quantity: 1, Subtotal: 75000,
+					TaxName: "PPN", TaxRate: 1100, TaxAmount: 7150},
+				{ID: 2, ProductID: 5, ProductName: "Teh Botol", UnitPrice: 5000, Quantity: 4, Subtotal: 20000, DiscountAmount: 5000,
+					TaxName: "PPN", TaxRate: 1100, TaxAmount: 1650},
+			},
+			Payments:   []model.Payment{{Method: model.PaymentMethodCash, Amount: 100000, ChangeAmount: 11200}},
+			Promotions: []model.AppliedPromotion{{Name: "Teh Hemat", TransactionDetailID: 2, Amount: 5000}},
+		},
+		Print: model.ReceiptPrint{TransactionID: 42, PrintNumber: printNumber, PrintedAt: time.Date(2026, 3, 2, 9, 0, 0, 0, wib)},
+	}
+}
+
+func render(t *testing.T, r *Renderer, format string, rc Receipt, width int) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := r.Render(&buf, format, rc, width); err != nil {
+		t.Fatalf("render %s: %v", format, err)
+	}
+	return buf.String()
+}
+
+func newRenderer(t *testing.T) *Renderer {
+	t.Helper()
+	r, err := New("Toko Maju\nJl. Merdeka 1", "Terima kasih, {{.Transaction.CashierName}}", Width58mm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRenderText(t *testing.T) {
+	r := newRenderer(t)
+
+	for _, width := range []int{Width58mm, Width80mm} {
+		out := render(t, r, model.ReceiptFormatText, sampleReceipt(1), width)
+		for _, l := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+			if n := utf8.RuneCountInString(l); n > width {
+				t.Errorf("width %d: line of %d characters: %q", width, n, l)
+			}
+		}
+		if strings.Contains(out, "CETAK ULANG") {
+			t.Errorf("width %d: original marked as reprint:\n%s", width, out)
+		}
+	}
+
+	out := render(t, r, model.ReceiptFormatText, sampleReceipt(1), 0)
+	for _, want := range []string{
+		"           Toko Maju\n",
+		"No. Transaksi                 42\n",
+		"Tanggal         01/03/2026 21:05\n",
+		"Beras Premium Pulen Wangi\nKemasan Lima Kilogram\n  1 x 75.000              75.000\n",
+		"  4 x 5.000               20.000\n  Teh Hemat               -5.000\n",
+		"Diskon                    -5.000\n",
+		"Voucher HEMAT10          -10.000\n",
+		"PPN 11%                    8.800\n",
+		"TOTAL                     88.800\n",
+		"Tunai                    100.000\n",
+		"Kembali                   11.200\n",
+		"      Terima kasih, Siti\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in receipt:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderReprint(t *testing.T) {
+	out := render(t, newRenderer(t), model.ReceiptFormatText, sampleReceipt(3), Width58mm)
+	for _, want := range []string{"*** CETAK ULANG ***", "Cetakan ke                     3", "Dicetak         02/03/2026 09:00"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in reprint:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderInvoiceNumber(t *testing.T) {
+	rc := sampleReceipt(1)
+	rc.Transaction.InvoiceNumber = "INV/JKT01/20260301/0007"
+	r := newRenderer(t)
+
+	if out := render(t, r, model.ReceiptFormatText, rc, Width58mm); !strings.Contains(out, "No. Nota INV/JKT01/20260301/0007\n") {
+		t.Errorf("expected the invoice number in:\n%s", out)
+	}
+	if out := render(t, r, model.ReceiptFormatHTML, rc, 0); !strings.Contains(out, "<title>Struk INV/JKT01/20260301/0007</title>") {
+		t.Errorf("expected the invoice number in the title:\n%s", out)
+	}
+}
+
+func TestRenderESCPOS(t *testing.T) {
+	rc := sampleReceipt(1)
+	rc.Transaction.Details[1].ProductName = "Kopi Café"
+	out := render(t, newRenderer(t), model.ReceiptFormatESCPOS, rc, Width58mm)
+
+	if !strings.HasPrefix(out, "\x1b@\x1bt\x00") {
+		t.Errorf("expected the printer to be reset first, got %q", out[:8])
+	}
+	if !strings.HasSuffix(out, "\x1bd\x04\x1dVB\x00") {
+		t.Errorf("expected a feed and cut at the end")
+	}
+	if !strings.Contains(out, "\x1bE\x01\x1d!\x01TOTAL                     88.800\n\x1d!\x00\x1bE\x00") {
+		t.Errorf("expected a bold, tall total line in %q", out)
+	}
+	if !strings.Contains(out, "Kopi Caf?\n") {
+		t.Errorf("expected non-ASCII characters replaced in %q", out)
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	r, err := New("<b>Toko & Co</b>", "", Width58mm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := render(t, r, model.ReceiptFormatHTML, sampleReceipt(2), 0)
+	for _, want := range []string{"&lt;b&gt;Toko &amp; Co&lt;/b&gt;", "*** CETAK ULANG ***", ">88.800</td>", "<title>Struk 42</title>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderRejects(t *testing.T) {
+	r := newRenderer(t)
+	if err := r.Render(&bytes.Buffer{}, "pdf", sampleReceipt(1), 0); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("expected ErrUnknownFormat, got %v", err)
+	}
+	if err := r.Render(&bytes.Buffer{}, model.ReceiptFormatText, sampleReceipt(1), 40); err == nil {
+		t.Error("expected an error for a 40 column receipt")
+	}
+	if _, err := New("{{.Missing", "", Width58mm); err == nil {
+		t.Error("expected an error for a broken header template")
+	}
+	if _, err := New("", "", 0); err == nil {
+		t.Error("expected an error for an unsupported default width")
+	}
+}
+
+func TestWrap(t *testing.T) {
+	got := wrap("Susu UHT Coklat Ultramilk 1000ml", 10)
+	want := []string{"Susu UHT", "Coklat", "Ultramilk", "1000ml"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("wrap = %q, want %q", got, want)
+	}
+	if got := wrap("Supercalifragilistic", 8); strings.Join(got, "|") != "Supercal|ifragili|stic" {
+		t.Errorf("expected long words cut, got %q", got)
+	}
+}
@@ -0,0 +1,84 @@
+package receipt
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const indent = "  "
+
+// layout breaks l into printed lines of at most width characters. Long text
+// wraps at spaces; an amount that no longer fits goes on a line of its own.
+func (l line) layout(width int) []string {
+	if l.Rule {
+		return []string{strings.Repeat("-", width)}
+	}
+
+	prefix := ""
+	if l.Indent {
+		prefix = indent
+	}
+	wrapped := wrap(l.Left, width-len(prefix))
+	for i := range wrapped {
+		wrapped[i] = prefix + wrapped[i]
+	}
+
+	if l.Center {
+		for i, s := range wrapped {
+			wrapped[i] = strings.Repeat(" ", (width-utf8.RuneCountInString(s))/2) + s
+		}
+		return wrapped
+	}
+	if l.Right == "" {
+		return wrapped
+	}
+
+	right := utf8.RuneCountInString(l.Right)
+	last := wrapped[len(wrapped)-1]
+	if gap := width - utf8.RuneCountInString(last) - right; gap >= 1 {
+		wrapped[len(wrapped)-1] = last + strings.Repeat(" ", gap) + l.Right
+		return wrapped
+	}
+	return append(wrapped, strings.Repeat(" ", max(width-right, 0))+l.Right)
+}
+
+// wrap splits s into lines of at most width characters, breaking at spaces
+// and cutting words longer than a line.
+func wrap(s string, width int) []string {
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(s) {
+		for utf8.RuneCountInString(word) > width {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			r := []rune(word)
+			lines = append(lines, string(r[:width]))
+			word = string(r[width:])
+		}
+		switch {
+		case current == "":
+			current = word
+		case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) <= width:
+			current += " " + word
+		default:
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	return append(lines, current)
+}
+
+func writeText(w io.Writer, lines []line, width int) error {
+	bw := bufio.NewWriter(w)
+	for _, l := range lines {
+		for _, s := range l.layout(width) {
+			bw.WriteString(strings.TrimRight(s, " "))
+			bw.WriteByte('\n')
+		}
+	}
+	return bw.Flush()
+}
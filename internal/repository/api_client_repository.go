@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"database/sql"
+	"kasir-api/internal/model"
+)
+
+type APIClientRepository interface {
+	Create(client model.APIClient) (model.APIClient, error)
+	GetAll() ([]model.APIClient, error)
+	GetByKeyHash(hash string) (model.APIClient, error)
+	Deactivate(id int) error
+}
+
+type postgresAPIClientRepository struct {
+	db *sql.DB
+}
+
+func NewAPIClientRepository(db *sql.DB) APIClientRepository {
+	return &postgresAPIClientRepository{db: db}
+}
+
+func (r *postgresAPIClientRepository) Create(client model.APIClient) (model.APIClient, error) {
+	query := `INSERT INTO api_clients (name, key_prefix, key_hash, role, active) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+	err := r.db.QueryRow(query, client.Name, client.KeyPrefix, client.KeyHash, client.Role, client.Active).Scan(&client.ID, &client.CreatedAt)
+	if err != nil {
+		return model.APIClient{}, err
+	}
+	return client, nil
+}
+
+func (r *postgresAPIClientRepository) GetAll() ([]model.APIClient, error) {
+	rows, err := r.db.Query(`SELECT id, name, key_prefix, key_hash, role, active, created_at FROM api_clients ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var clients []model.APIClient
+	for rows.Next() {
+		var c model.APIClient
+		if err := rows.Scan(&c.ID, &c.Name, &c.KeyPrefix, &c.KeyHash, &c.Role, &c.Active, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		clients = append(clients, c)
+	}
+	return clients, nil
+}
+
+func (r *postgresAPIClientRepository) GetByKeyHash(hash string) (model.APIClient, error) {
+	var c model.APIClient
+	err := r.db.QueryRow(`SELECT id, name, key_prefix, key_hash, role, active, created_at FROM api_clients WHERE key_hash = $1`, hash).
+		Scan(&c.ID, &c.Name, &c.KeyPrefix, &c.KeyHash, &c.Role, &c.Active, &c.CreatedAt)
+	if err != nil {
+		return model.APIClient{}, err
+	}
+	return c, nil
+}
+
+func (r *postgresAPIClientRepository) Deactivate(id int) error {
+	result, err := r.db.Exec(`UPDATE api_clients SET active = FALSE WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -22,8 +22,8 @@ func NewCategoryRepository(db *sql.DB) CategoryRepository {
 }
 
 func (r *postgresCategoryRepository) Create(category model.Category) (model.Category, error) {
-	query := `INSERT INTO categories (name, description) VALUES ($1, $2) RETURNING id`
-	err := r.db.QueryRow(query, category.Name, category.Description).Scan(&category.ID)
+	query := `INSERT INTO categories (name, description, tax_rate_id) VALUES ($1, $2, $3) RETURNING id`
+	err := r.db.QueryRow(query, category.Name, category.Description, category.TaxRateID).Scan(&category.ID)
 	if err != nil {
 		return model.Category{}, err
 	}
@@ -31,7 +31,7 @@ func (r *postgresCategoryRepository) Create(category model.Category) (model.Cate
 }
 
 func (r *postgresCategoryRepository) GetAll() ([]model.Category, error) {
-	rows, err := r.db.Query(`SELECT id, name, description FROM categories`)
+	rows, err := r.db.Query(`SELECT id, name, description, tax_rate_id FROM categories`)
 	if err != nil {
 		return nil, err
 	}
@@ -40,9 +40,11 @@ func (r *postgresCategoryRepository) GetAll() ([]model.Category, error) {
 	var categories []model.Category
 	for rows.Next() {
 		var c model.Category
-		if err := rows.Scan(&c.ID, &c.Name, &c.Description); err != nil {
+		var taxRateID sql.NullInt64
+		if err := rows.Scan(&c.ID, &c.Name, &c.Description, &taxRateID); err != nil {
 			return nil, err
 		}
+		c.TaxRateID = nullIntPtr(taxRateID)
 		categories = append(categories, c)
 	}
 	return categories, nil
@@ -50,20 +52,24 @@ func (r *postgresCategoryRepository) GetAll() ([]model.Category, error) {
 
 func (r *postgresCategoryRepository) GetByID(id int) (model.Category, error) {
 	var c model.Category
-	err := r.db.QueryRow(`SELECT id, name, description FROM categories WHERE id = $1`, id).Scan(&c.ID, &c.Name, &c.Description)
+	var taxRateID sql.NullInt64
+	err := r.db.QueryRow(`SELECT id, name, description, tax_rate_id FROM categories WHERE id = $1`, id).Scan(&c.ID, &c.Name, &c.Description, &taxRateID)
 	if err != nil {
 		return model.Category{}, err
 	}
+	c.TaxRateID = nullIntPtr(taxRateID)
 	return c, nil
 }
 
 func (r *postgresCategoryRepository) Update(id int, category model.Category) (model.Category, error) {
-	query := `UPDATE categories SET name = $1, description = $2 WHERE id = $3 RETURNING id, name, description`
+	query := `UPDATE categories SET name = $1, description = $2, tax_rate_id = $3 WHERE id = $4 RETURNING id, name, description, tax_rate_id`
 	var updated model.Category
-	err := r.db.QueryRow(query, category.Name, category.Description, id).Scan(&updated.ID, &updated.Name, &updated.Description)
+	var taxRateID sql.NullInt64
+	err := r.db.QueryRow(query, category.Name, category.Description, category.TaxRateID, id).Scan(&updated.ID, &updated.Name, &updated.Description, &taxRateID)
 	if err != nil {
 		return model.Category{}, err
 	}
+	updated.TaxRateID = nullIntPtr(taxRateID)
 	return updated, nil
 }
 
@@ -81,3 +87,12 @@ func (r *postgresCategoryRepository) Delete(id int) error {
 	}
 	return nil
 }
+
+// nullIntPtr converts a nullable integer column into an optional model field.
+func nullIntPtr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	i := int(v.Int64)
+	return &i
+}
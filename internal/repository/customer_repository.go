@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/internal/model"
+
+	"github.com/lib/pq"
+)
+
+var ErrInvalidCustomer = errors.New("invalid customer")
+
+type CustomerRepository interface {
+	Create(c model.Customer) (*model.Customer, error)
+	GetAll(search string) ([]model.Customer, error)
+	GetByID(id int) (*model.Customer, error)
+	Update(id int, c model.Customer) (*model.Customer, error)
+	Delete(id int) error
+	GetPointEntries(customerID int) ([]model.PointEntry, error)
+}
+
+type postgresCustomerRepository struct {
+	db *sql.DB
+}
+
+func NewCustomerRepository(db *sql.DB) CustomerRepository {
+	return &postgresCustomerRepository{db: db}
+}
+
+const customerColumns = `id, name, COALESCE(phone, ''), member_number, points, created_at`
+
+func scanCustomer(row rowScanner) (*model.Customer, error) {
+	var c model.Customer
+	if err := row.Scan(&c.ID, &c.Name, &c.Phone, &c.MemberNumber, &c.Points, &c.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// duplicateCustomer turns a unique violation on the phone or member number
+// into ErrInvalidCustomer.
+func duplicateCustomer(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return fmt.Errorf("%w: phone or member number is already registered", ErrInvalidCustomer)
+	}
+	return err
+}
+
+// Create registers a customer. Without a member number one is made up from
+// the new ID, e.g. M000042.
+func (r *postgresCustomerRepository) Create(c model.Customer) (*model.Customer, error) {
+	row := r.db.QueryRow(`
+		WITH next AS (SELECT nextval(pg_get_serial_sequence('customers', 'id')) AS id)
+		INSERT INTO customers (id, name, phone, member_number)
+		SELECT id, $1, NULLIF($2, ''), COALESCE(NULLIF($3, ''), 'M' || LPAD(id::text, 6, '0'))
+		FROM next
+		RETURNING `+customerColumns, c.Name, c.Phone, c.MemberNumber)
+	created, err := scanCustomer(row)
+	if err != nil {
+		return nil, duplicateCustomer(err)
+	}
+	return created, nil
+}
+
+// GetAll lists customers, optionally only those whose name, phone or member
+// number contains search.
+func (r *postgresCustomerRepository) GetAll(search string) ([]model.Customer, error) {
+	query := "SELECT " + customerColumns + " FROM customers"
+	args := []interface{}{}
+	if search != "" {
+		query += " WHERE name ILIKE $1 OR phone ILIKE $1 OR member_number ILIKE $1"
+		args = append(args, "%"+search+"%")
+	}
+	query += " ORDER BY name, id"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	customers := []model.Customer{}
+	for rows.Next() {
+		c, err := scanCustomer(rows)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, *c)
+	}
+	return customers, rows.Err()
+}
+
+func (r *postgresCustomerRepository) GetByID(id int) (*model.Customer, error) {
+	return scanCustomer(r.db.QueryRow("SELECT "+customerColumns+" FROM customers WHERE id = $1", id))
+}
+
+// Update changes a customer's details. The points balance only moves through
+// the ledger and is left alone.
+func (r *postgresCustomerRepository) Update(id int, c model.Customer) (*model.Customer, error) {
+	row := r.db.QueryRow(`
+		UPDATE customers
+		SET name = $1, phone = NULLIF($2, ''), member_number = COALESCE(NULLIF($3, ''), member_number)
+		WHERE id = $4
+		RETURNING `+customerColumns, c.Name, c.Phone, c.MemberNumber, id)
+	updated, err := scanCustomer(row)
+	if err != nil {
+		return nil, duplicateCustomer(err)
+	}
+	return updated, nil
+}
+
+func (r *postgresCustomerRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM customers WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetPointEntries returns a customer's points ledger, newest first.
+func (r *postgresCustomerRepository) GetPointEntries(customerID int) ([]model.PointEntry, error) {
+	rows, err := r.db.Query(`
+		SELECT id, customer_id, transaction_id, type, points, created_at
+		FROM loyalty_point_entries
+		WHERE customer_id = $1
+		ORDER BY id DESC
+	`, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []model.PointEntry{}
+	for rows.Next() {
+		var e model.PointEntry
+		var transactionID sql.NullInt64
+		if err := rows.Scan(&e.ID, &e.CustomerID, &transactionID, &e.Type, &e.Points, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		e.TransactionID = int(transactionID.Int64)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// lockCustomer loads a customer attached to a checkout, locking the row so
+// concurrent sales cannot spend the same points twice.
+func lockCustomer(tx *sql.Tx, id int) (*model.Customer, error) {
+	c, err := scanCustomer(tx.QueryRow("SELECT "+customerColumns+" FROM customers WHERE id = $1 FOR UPDATE", id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: customer %d not found", ErrInvalidCustomer, id)
+	}
+	return c, err
+}
+
+// addPoints appends an entry to a customer's points ledger and moves the
+// balance with it.
+func addPoints(tx *sql.Tx, customerID, transactionID int, entryType string, points int) error {
+	if points == 0 {
+		return nil
+	}
+	_, err := tx.Exec(
+		"INSERT INTO loyalty_point_entries (customer_id, transaction_id, type, points) VALUES ($1, $2, $3, $4)",
+		customerID, transactionID, entryType, points,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("UPDATE customers SET points = points + $1 WHERE id = $2", points, customerID)
+	return err
+}
@@ -0,0 +1,367 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/internal/businessday"
+	"kasir-api/internal/model"
+	"time"
+)
+
+var (
+	ErrInvalidDayClose = errors.New("invalid day close")
+	// ErrDayClosed is returned when a sale or refund would land in a business
+	// day that a Z report has already closed.
+	ErrDayClosed = errors.New("business day is closed")
+)
+
+type DayReportRepository interface {
+	GetXReport(businessDate string) (*model.DayReport, error)
+	CloseDay(req model.CloseDayRequest) (*model.DayReport, error)
+	GetZReports() ([]model.DayReport, error)
+	GetZReport(zNumber int) (*model.DayReport, error)
+}
+
+type postgresDayReportRepository struct {
+	db       *sql.DB
+	calendar *businessday.Calendar
+}
+
+func NewDayReportRepository(db *sql.DB, calendar *businessday.Calendar) DayReportRepository {
+	return &postgresDayReportRepository{db: db, calendar: calendar}
+}
+
+// rowQueryer is satisfied by both *sql.DB and *sql.Tx.
+type rowQueryer interface {
+	queryer
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// GetXReport returns the running totals of a business day, today when
+// businessDate is empty, without closing it.
+func (r *postgresDayReportRepository) GetXReport(businessDate string) (*model.DayReport, error) {
+	date, _, err := resolveBusinessDate(r.db, r.calendar, businessDate)
+	if err != nil {
+		return nil, err
+	}
+	return dayTotals(r.db, r.calendar, date)
+}
+
+// CloseDay takes the Z report of a business day. The z_reports table is
+// locked exclusively, which waits for sales and refunds in flight to commit
+// and keeps new ones out until the totals are frozen; see ensureDayOpen.
+// After the first Z report, days are closed one after the other without
+// gaps, so every day from the first close on has exactly one Z number.
+func (r *postgresDayReportRepository) CloseDay(req model.CloseDayRequest) (*model.DayReport, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("LOCK TABLE z_reports IN EXCLUSIVE MODE"); err != nil {
+		return nil, err
+	}
+
+	date, today, err := resolveBusinessDate(tx, r.calendar, req.BusinessDate)
+	if err != nil {
+		return nil, err
+	}
+	if date > today {
+		return nil, fmt.Errorf("%w: business day %s has not started yet", ErrInvalidDayClose, date)
+	}
+
+	zNumber := 1
+	var lastNumber int
+	var lastDate string
+	err = tx.QueryRow("SELECT z_number, TO_CHAR(business_date, 'YYYY-MM-DD') FROM z_reports ORDER BY z_number DESC LIMIT 1").Scan(&lastNumber, &lastDate)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if err == nil {
+		if date <= lastDate {
+			return nil, fmt.Errorf("%w: business days up to %s are already closed (Z %d)", ErrInvalidDayClose, lastDate, lastNumber)
+		}
+		last, err := time.Parse("2006-01-02", lastDate)
+		if err != nil {
+			return nil, err
+		}
+		if next := last.AddDate(0, 0, 1).Format("2006-01-02"); date != next {
+			return nil, fmt.Errorf("%w: business day %s has to be closed before %s", ErrInvalidDayClose, next, date)
+		}
+		zNumber = lastNumber + 1
+	}
+
+	report, err := dayTotals(tx, r.calendar, date)
+	if err != nil {
+		return nil, err
+	}
+
+	closedBy := sql.NullInt64{Int64: int64(req.ClosedBy), Valid: req.ClosedBy != 0}
+	var id int
+	err = tx.QueryRow(`
+		INSERT INTO z_reports (z_number, business_date, gross_sales, total_refund, net_sales, transaction_count, void_count, refund_count,
+			total_tax, total_discount, note, closed_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), $12)
+		RETURNING id
+	`, zNumber, date, report.GrossSales, report.TotalRefund, report.NetSales, report.TransactionCount, report.VoidCount, report.RefundCount,
+		report.TotalTax, report.TotalDiscount, req.Note, closedBy).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range report.Payments {
+		_, err := tx.Exec("INSERT INTO z_report_payments (z_report_id, method, amount, refunded, transaction_count) VALUES ($1, $2, $3, $4, $5)",
+			id, p.Method, p.Amount, p.Refunded, p.TransactionCount)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, t := range report.Taxes {
+		_, err := tx.Exec("INSERT INTO z_report_taxes (z_report_id, tax_name, rate_bp, inclusive, base, tax) VALUES ($1, $2, $3, $4, $5, $6)",
+			id, t.Name, t.RateBP, t.Inclusive, t.Base, t.Tax)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetZReport(zNumber)
+}
+
+const zReportColumns = `id, z_number, TO_CHAR(business_date, 'YYYY-MM-DD'), gross_sales, total_refund, net_sales, transaction_count,
+	void_count, refund_count, total_tax, total_discount, COALESCE(note, ''), closed_by, closed_at`
+
+func scanZReport(row rowScanner) (int, model.DayReport, error) {
+	var id int
+	var z model.DayReport
+	var closedBy sql.NullInt64
+	var closedAt time.Time
+	err := row.Scan(&id, &z.ZNumber, &z.BusinessDate, &z.GrossSales, &z.TotalRefund, &z.NetSales, &z.TransactionCount,
+		&z.VoidCount, &z.RefundCount, &z.TotalTax, &z.TotalDiscount, &z.Note, &closedBy, &closedAt)
+	if err != nil {
+		return 0, model.DayReport{}, err
+	}
+	z.ClosedBy = int(closedBy.Int64)
+	z.ClosedAt = &closedAt
+	return id, z, nil
+}
+
+// GetZReports returns every Z report, newest first, without the payment and
+// tax breakdowns.
+func (r *postgresDayReportRepository) GetZReports() ([]model.DayReport, error) {
+	rows, err := r.db.Query("SELECT " + zReportColumns + " FROM z_reports ORDER BY z_number DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []model.DayReport{}
+	for rows.Next() {
+		_, z, err := scanZReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, z)
+	}
+	return reports, rows.Err()
+}
+
+func (r *postgresDayReportRepository) GetZReport(zNumber int) (*model.DayReport, error) {
+	id, z, err := scanZReport(r.db.QueryRow("SELECT "+zReportColumns+" FROM z_reports WHERE z_number = $1", zNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query("SELECT method, amount, refunded, transaction_count FROM z_report_payments WHERE z_report_id = $1 ORDER BY amount - COALESCE(refunded, 0) DESC, method", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	z.Payments = []model.DayReportPayment{}
+	for rows.Next() {
+		var p model.DayReportPayment
+		var refunded sql.NullInt64
+		if err := rows.Scan(&p.Method, &p.Amount, &refunded, &p.TransactionCount); err != nil {
+			return nil, err
+		}
+		// Z reports taken before refunds were frozen per method have none
+		if refunded.Valid {
+			p.SetRefunded(int(refunded.Int64))
+		}
+		z.Payments = append(z.Payments, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	taxRows, err := r.db.Query("SELECT tax_name, rate_bp, inclusive, base, tax FROM z_report_taxes WHERE z_report_id = $1 ORDER BY rate_bp DESC, tax_name", id)
+	if err != nil {
+		return nil, err
+	}
+	defer taxRows.Close()
+	z.Taxes = []model.DayReportTax{}
+	for taxRows.Next() {
+		var t model.DayReportTax
+		if err := taxRows.Scan(&t.Name, &t.RateBP, &t.Inclusive, &t.Base, &t.Tax); err != nil {
+			return nil, err
+		}
+		z.Taxes = append(z.Taxes, t)
+	}
+	if err := taxRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &z, nil
+}
+
+// resolveBusinessDate returns businessDate, or today when it is empty, and
+// today, both as YYYY-MM-DD. Today is taken from the database clock, the
+// same one that stamps sales.
+func resolveBusinessDate(q rowQueryer, calendar *businessday.Calendar, businessDate string) (date, today string, err error) {
+	err = q.QueryRow("SELECT TO_CHAR(" + calendar.SQLDate("CURRENT_TIMESTAMP") + ", 'YYYY-MM-DD')").Scan(&today)
+	if err != nil {
+		return "", "", err
+	}
+	if businessDate == "" {
+		return today, today, nil
+	}
+	return businessDate, today, nil
+}
+
+// dayTotals adds up the sales and refunds of one business day.
+func dayTotals(q rowQueryer, calendar *businessday.Calendar, date string) (*model.DayReport, error) {
+	report := model.DayReport{BusinessDate: date, Payments: []model.DayReportPayment{}, Taxes: []model.DayReportTax{}}
+
+	from, to, err := calendar.Bounds(date, date)
+	if err != nil {
+		return nil, err
+	}
+
+	err = q.QueryRow(`
+		SELECT COALESCE(SUM(t.total_amount), 0), COUNT(*) FILTER (WHERE t.status <> 'voided'),
+			COUNT(*) FILTER (WHERE t.status = 'voided'), COALESCE(SUM(t.discount_amount), 0)
+		FROM transactions t
+		WHERE t.created_at >= $1 AND t.created_at < $2
+	`, from, to).Scan(&report.GrossSales, &report.TransactionCount, &report.VoidCount, &report.TotalDiscount)
+	if err != nil {
+		return nil, err
+	}
+
+	err = q.QueryRow(`
+		SELECT COALESCE(SUM(rf.total_amount), 0), COUNT(*) FILTER (WHERE rf.type <> $3)
+		FROM refunds rf
+		WHERE rf.created_at >= $1 AND rf.created_at < $2
+	`, from, to, model.RefundTypeVoid).Scan(&report.TotalRefund, &report.RefundCount)
+	if err != nil {
+		return nil, err
+	}
+	report.NetSales = report.GrossSales - report.TotalRefund
+
+	// A sale voided the same day never took money that day, so neither it nor
+	// its refunds appear per method; sales voided later stay in and the void
+	// is handed back on the later day
+	rows, err := q.Query(`
+		WITH voided AS (
+			SELECT rf.transaction_id
+			FROM refunds rf
+			JOIN transactions t ON rf.transaction_id = t.id
+			WHERE rf.type = $3 AND rf.created_at >= $1 AND rf.created_at < $2
+				AND t.created_at >= $1 AND t.created_at < $2
+		), taken AS (
+			SELECT p.method, SUM(p.amount - p.change_amount) AS amount, COUNT(DISTINCT t.id) AS transaction_count
+			FROM transaction_payments p
+			JOIN transactions t ON p.transaction_id = t.id
+			WHERE t.created_at >= $1 AND t.created_at < $2
+				AND t.id NOT IN (SELECT transaction_id FROM voided)
+			GROUP BY p.method
+		), refunded AS (
+			SELECT rp.method, SUM(rp.amount) AS amount
+			FROM refund_payments rp
+			JOIN refunds rf ON rp.refund_id = rf.id
+			WHERE rf.created_at >= $1 AND rf.created_at < $2
+				AND rf.transaction_id NOT IN (SELECT transaction_id FROM voided)
+			GROUP BY rp.method
+		)
+		SELECT COALESCE(tk.method, rd.method), COALESCE(tk.amount, 0), COALESCE(rd.amount, 0), COALESCE(tk.transaction_count, 0)
+		FROM taken tk
+		FULL JOIN refunded rd ON rd.method = tk.method
+		ORDER BY COALESCE(tk.amount, 0) - COALESCE(rd.amount, 0) DESC, 1
+	`, from, to, model.RefundTypeVoid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p model.DayReportPayment
+		var refunded int
+		if err := rows.Scan(&p.Method, &p.Amount, &refunded, &p.TransactionCount); err != nil {
+			return nil, err
+		}
+		p.SetRefunded(refunded)
+		report.Payments = append(report.Payments, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Refunded lines come off the day of the refund, as in the sales report
+	taxRows, err := q.Query(`
+		SELECT s.tax_name, s.tax_rate_bp, s.tax_inclusive, SUM(s.base), SUM(s.tax)
+		FROM (
+			SELECT COALESCE(td.tax_name, '') AS tax_name, td.tax_rate_bp, td.tax_inclusive,
+				td.line_total - td.tax_amount AS base, td.tax_amount AS tax
+			FROM transaction_details td
+			JOIN transactions t ON td.transaction_id = t.id
+			WHERE t.created_at >= $1 AND t.created_at < $2
+			UNION ALL
+			SELECT COALESCE(td.tax_name, ''), td.tax_rate_bp, td.tax_inclusive,
+				-(ri.amount - ri.tax_amount), -ri.tax_amount
+			FROM refund_items ri
+			JOIN refunds rf ON ri.refund_id = rf.id
+			JOIN transaction_details td ON ri.transaction_detail_id = td.id
+			WHERE rf.created_at >= $1 AND rf.created_at < $2
+		) s
+		GROUP BY s.tax_name, s.tax_rate_bp, s.tax_inclusive
+		ORDER BY s.tax_rate_bp DESC, s.tax_name
+	`, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer taxRows.Close()
+	for taxRows.Next() {
+		var t model.DayReportTax
+		if err := taxRows.Scan(&t.Name, &t.RateBP, &t.Inclusive, &t.Base, &t.Tax); err != nil {
+			return nil, err
+		}
+		report.Taxes = append(report.Taxes, t)
+		report.TotalTax += t.Tax
+	}
+	if err := taxRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// ensureDayOpen fails with ErrDayClosed once the current business day has
+// been closed by a Z report. It holds a share lock on z_reports until tx
+// ends, so a Z report taken meanwhile waits for the sale or refund and
+// includes it.
+func ensureDayOpen(tx *sql.Tx, calendar *businessday.Calendar) error {
+	if _, err := tx.Exec("LOCK TABLE z_reports IN SHARE MODE"); err != nil {
+		return err
+	}
+	var closed bool
+	query := "SELECT EXISTS (SELECT 1 FROM z_reports WHERE business_date >= " + calendar.SQLDate("CURRENT_TIMESTAMP") + ")"
+	if err := tx.QueryRow(query).Scan(&closed); err != nil {
+		return err
+	}
+	if closed {
+		return fmt.Errorf("%w: today has already been closed with a Z report", ErrDayClosed)
+	}
+	return nil
+}
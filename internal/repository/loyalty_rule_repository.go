@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"database/sql"
+	"kasir-api/internal/model"
+)
+
+type LoyaltyRuleRepository interface {
+	Create(rule model.LoyaltyRule) (model.LoyaltyRule, error)
+	GetAll() ([]model.LoyaltyRule, error)
+	GetByID(id int) (model.LoyaltyRule, error)
+	Update(id int, rule model.LoyaltyRule) (model.LoyaltyRule, error)
+	Delete(id int) error
+}
+
+type postgresLoyaltyRuleRepository struct {
+	db *sql.DB
+}
+
+func NewLoyaltyRuleRepository(db *sql.DB) LoyaltyRuleRepository {
+	return &postgresLoyaltyRuleRepository{db: db}
+}
+
+const loyaltyRuleColumns = `id, name, category_id, spend_amount, points, active`
+
+func scanLoyaltyRule(row rowScanner) (model.LoyaltyRule, error) {
+	var rule model.LoyaltyRule
+	var categoryID sql.NullInt64
+	if err := row.Scan(&rule.ID, &rule.Name, &categoryID, &rule.SpendAmount, &rule.Points, &rule.Active); err != nil {
+		return model.LoyaltyRule{}, err
+	}
+	rule.CategoryID = nullIntPtr(categoryID)
+	return rule, nil
+}
+
+// queryLoyaltyRules runs a select over loyaltyRuleColumns.
+func queryLoyaltyRules(q queryer, query string, args ...interface{}) ([]model.LoyaltyRule, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []model.LoyaltyRule{}
+	for rows.Next() {
+		rule, err := scanLoyaltyRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// loadActiveLoyaltyRules returns the rules checkout earns points by.
+func loadActiveLoyaltyRules(tx *sql.Tx) ([]model.LoyaltyRule, error) {
+	return queryLoyaltyRules(tx, "SELECT "+loyaltyRuleColumns+" FROM loyalty_rules WHERE active ORDER BY id")
+}
+
+func (r *postgresLoyaltyRuleRepository) Create(rule model.LoyaltyRule) (model.LoyaltyRule, error) {
+	query := `INSERT INTO loyalty_rules (name, category_id, spend_amount, points, active) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	err := r.db.QueryRow(query, rule.Name, rule.CategoryID, rule.SpendAmount, rule.Points, rule.Active).Scan(&rule.ID)
+	if err != nil {
+		return model.LoyaltyRule{}, err
+	}
+	return rule, nil
+}
+
+func (r *postgresLoyaltyRuleRepository) GetAll() ([]model.LoyaltyRule, error) {
+	return queryLoyaltyRules(r.db, "SELECT "+loyaltyRuleColumns+" FROM loyalty_rules ORDER BY id")
+}
+
+func (r *postgresLoyaltyRuleRepository) GetByID(id int) (model.LoyaltyRule, error) {
+	return scanLoyaltyRule(r.db.QueryRow("SELECT "+loyaltyRuleColumns+" FROM loyalty_rules WHERE id = $1", id))
+}
+
+func (r *postgresLoyaltyRuleRepository) Update(id int, rule model.LoyaltyRule) (model.LoyaltyRule, error) {
+	query := `UPDATE loyalty_rules SET name = $1, category_id = $2, spend_amount = $3, points = $4, active = $5 WHERE id = $6
+		RETURNING ` + loyaltyRuleColumns
+	return scanLoyaltyRule(r.db.QueryRow(query, rule.Name, rule.CategoryID, rule.SpendAmount, rule.Points, rule.Active, id))
+}
+
+func (r *postgresLoyaltyRuleRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM loyalty_rules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
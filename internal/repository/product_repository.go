@@ -8,6 +8,7 @@ import (
 type ProductRepository interface {
 	Create(product model.Product) (model.Product, error)
 	GetAll(nameFilter string) ([]model.Product, error)
+	GetLowStock() ([]model.Product, error)
 	GetByID(id int) (model.Product, error)
 	Update(id int, product model.Product) (model.Product, error)
 	Delete(id int) error
@@ -22,42 +23,75 @@ func NewProductRepository(db *sql.DB) ProductRepository {
 }
 
 func (r *postgresProductRepository) Create(product model.Product) (model.Product, error) {
-	query := `INSERT INTO products (name, price, stock, category_id) VALUES ($1, $2, $3, $4) RETURNING id`
-	err := r.db.QueryRow(query, product.Name, product.Price, product.Stock, product.CategoryID).Scan(&product.ID)
+	tx, err := r.db.Begin()
 	if err != nil {
 		return model.Product{}, err
 	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO products (name, price, stock, cost_price, min_stock, reorder_quantity, category_id, tax_rate_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+	err = tx.QueryRow(query, product.Name, product.Price, product.Stock, product.CostPrice, product.MinStock, product.ReorderQuantity, product.CategoryID, product.TaxRateID).Scan(&product.ID)
+	if err != nil {
+		return model.Product{}, err
+	}
+
+	if product.Stock != 0 {
+		err = recordStockMovements(tx, []model.StockMovement{{
+			ProductID:  product.ID,
+			Type:       model.StockMovementInitial,
+			Quantity:   product.Stock,
+			StockAfter: product.Stock,
+			UserID:     product.UserID,
+		}})
+		if err != nil {
+			return model.Product{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Product{}, err
+	}
 	return product, nil
 }
 
 func (r *postgresProductRepository) GetAll(nameFilter string) ([]model.Product, error) {
+	if nameFilter != "" {
+		return r.queryProducts(" WHERE p.name ILIKE $1", "%"+nameFilter+"%")
+	}
+	return r.queryProducts("")
+}
+
+// GetLowStock returns the products whose stock has dropped below their
+// minimum, the furthest below first.
+func (r *postgresProductRepository) GetLowStock() ([]model.Product, error) {
+	return r.queryProducts(" WHERE p.min_stock > 0 AND p.stock < p.min_stock ORDER BY p.stock - p.min_stock, p.name")
+}
+
+// queryProducts loads the products matching where, with their category.
+func (r *postgresProductRepository) queryProducts(where string, args ...interface{}) ([]model.Product, error) {
 	query := `
 		SELECT 
-			p.id, p.name, p.price, p.stock, p.category_id,
-			c.id, c.name, c.description
+			p.id, p.name, p.price, p.stock, p.cost_price, p.min_stock, p.reorder_quantity, p.category_id, p.tax_rate_id,
+			c.id, c.name, c.description, c.tax_rate_id
 		FROM products p
 		LEFT JOIN categories c ON p.category_id = c.id
-	`
-	args := []interface{}{}
-	if nameFilter != "" {
-		query += " WHERE p.name ILIKE $1"
-		args = append(args, "%"+nameFilter+"%")
-	}
+	` + where
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var products []model.Product
+	products := []model.Product{}
 	for rows.Next() {
 		var p model.Product
-		var catID sql.NullInt64
+		var taxRateID, catID, catTaxRateID sql.NullInt64
 		var catName, catDesc sql.NullString
 
-		if err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryID, &catID, &catName, &catDesc); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CostPrice, &p.MinStock, &p.ReorderQuantity, &p.CategoryID, &taxRateID, &catID, &catName, &catDesc, &catTaxRateID); err != nil {
 			return nil, err
 		}
+		p.TaxRateID = nullIntPtr(taxRateID)
 
 		// Populate category if it exists
 		if catID.Valid {
@@ -65,6 +99,7 @@ func (r *postgresProductRepository) GetAll(nameFilter string) ([]model.Product,
 				ID:          int(catID.Int64),
 				Name:        catName.String,
 				Description: catDesc.String,
+				TaxRateID:   nullIntPtr(catTaxRateID),
 			}
 		}
 
@@ -76,20 +111,21 @@ func (r *postgresProductRepository) GetAll(nameFilter string) ([]model.Product,
 func (r *postgresProductRepository) GetByID(id int) (model.Product, error) {
 	query := `
 		SELECT 
-			p.id, p.name, p.price, p.stock, p.category_id,
-			c.id, c.name, c.description
+			p.id, p.name, p.price, p.stock, p.cost_price, p.min_stock, p.reorder_quantity, p.category_id, p.tax_rate_id,
+			c.id, c.name, c.description, c.tax_rate_id
 		FROM products p
 		LEFT JOIN categories c ON p.category_id = c.id
 		WHERE p.id = $1
 	`
 	var p model.Product
-	var catID sql.NullInt64
+	var taxRateID, catID, catTaxRateID sql.NullInt64
 	var catName, catDesc sql.NullString
 
-	err := r.db.QueryRow(query, id).Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryID, &catID, &catName, &catDesc)
+	err := r.db.QueryRow(query, id).Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CostPrice, &p.MinStock, &p.ReorderQuantity, &p.CategoryID, &taxRateID, &catID, &catName, &catDesc, &catTaxRateID)
 	if err != nil {
 		return model.Product{}, err
 	}
+	p.TaxRateID = nullIntPtr(taxRateID)
 
 	// Populate category if it exists
 	if catID.Valid {
@@ -97,19 +133,58 @@ func (r *postgresProductRepository) GetByID(id int) (model.Product, error) {
 			ID:          int(catID.Int64),
 			Name:        catName.String,
 			Description: catDesc.String,
+			TaxRateID:   nullIntPtr(catTaxRateID),
 		}
 	}
 
 	return p, nil
 }
 
+// Update saves a product. Stock is only overwritten when product.SetStock is
+// set, and the change is then recorded in the stock ledger as a correction;
+// otherwise whatever checkouts did to the stock in the meantime is kept.
 func (r *postgresProductRepository) Update(id int, product model.Product) (model.Product, error) {
-	query := `UPDATE products SET name = $1, price = $2, stock = $3, category_id = $4 WHERE id = $5 RETURNING id, name, price, stock, category_id`
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Product{}, err
+	}
+	defer tx.Rollback()
+
+	var stockBefore int
+	if err := tx.QueryRow("SELECT stock FROM products WHERE id = $1 FOR UPDATE", id).Scan(&stockBefore); err != nil {
+		return model.Product{}, err
+	}
+	if !product.SetStock {
+		product.Stock = stockBefore
+	}
+
+	query := `UPDATE products SET name = $1, price = $2, stock = $3, min_stock = $4, reorder_quantity = $5, category_id = $6, tax_rate_id = $7 WHERE id = $8 RETURNING id, name, price, stock, cost_price, min_stock, reorder_quantity, category_id, tax_rate_id`
 	var updated model.Product
-	err := r.db.QueryRow(query, product.Name, product.Price, product.Stock, product.CategoryID, id).Scan(&updated.ID, &updated.Name, &updated.Price, &updated.Stock, &updated.CategoryID)
+	var taxRateID sql.NullInt64
+	err = tx.QueryRow(query, product.Name, product.Price, product.Stock, product.MinStock, product.ReorderQuantity, product.CategoryID, product.TaxRateID, id).Scan(&updated.ID, &updated.Name, &updated.Price, &updated.Stock, &updated.CostPrice, &updated.MinStock, &updated.ReorderQuantity, &updated.CategoryID, &taxRateID)
 	if err != nil {
 		return model.Product{}, err
 	}
+	updated.TaxRateID = nullIntPtr(taxRateID)
+
+	if delta := updated.Stock - stockBefore; delta != 0 {
+		err = recordStockMovements(tx, []model.StockMovement{{
+			ProductID:  id,
+			Type:       model.StockMovementAdjustment,
+			Quantity:   delta,
+			StockAfter: updated.Stock,
+			Reason:     model.StockReasonCorrection,
+			Note:       "stock set on product update",
+			UserID:     product.UserID,
+		}})
+		if err != nil {
+			return model.Product{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Product{}, err
+	}
 	return updated, nil
 }
 
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"kasir-api/internal/model"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+type PromotionRepository interface {
+	Create(promo model.Promotion) (model.Promotion, error)
+	GetAll() ([]model.Promotion, error)
+	GetByID(id int) (model.Promotion, error)
+	Update(id int, promo model.Promotion) (model.Promotion, error)
+	Delete(id int) error
+}
+
+type postgresPromotionRepository struct {
+	db *sql.DB
+}
+
+func NewPromotionRepository(db *sql.DB) PromotionRepository {
+	return &postgresPromotionRepository{db: db}
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx.
+type queryer interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+const promotionColumns = `id, name, type, percent_bp, amount, buy_qty, get_qty, min_spend, priority, active, starts_at, ends_at`
+
+// activePromotionCondition selects promotions that apply right now.
+const activePromotionCondition = `active
+	AND (starts_at IS NULL OR starts_at <= CURRENT_TIMESTAMP)
+	AND (ends_at IS NULL OR ends_at > CURRENT_TIMESTAMP)`
+
+// queryPromotions loads the promotions matching where, together with their items.
+func queryPromotions(q queryer, where string, args ...interface{}) ([]model.Promotion, error) {
+	rows, err := q.Query("SELECT "+promotionColumns+" FROM promotions"+where+" ORDER BY priority, id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promos := []model.Promotion{}
+	ids := []int{}
+	for rows.Next() {
+		var p model.Promotion
+		var startsAt, endsAt sql.NullTime
+		if err := rows.Scan(&p.ID, &p.Name, &p.Type, &p.PercentBP, &p.Amount, &p.BuyQty, &p.GetQty,
+			&p.MinSpend, &p.Priority, &p.Active, &startsAt, &endsAt); err != nil {
+			return nil, err
+		}
+		if startsAt.Valid {
+			p.StartsAt = &startsAt.Time
+		}
+		if endsAt.Valid {
+			p.EndsAt = &endsAt.Time
+		}
+		p.Items = []model.PromotionItem{}
+		promos = append(promos, p)
+		ids = append(ids, p.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return promos, nil
+	}
+
+	itemRows, err := q.Query(`
+		SELECT promotion_id, product_id, category_id, quantity
+		FROM promotion_items
+		WHERE promotion_id = ANY($1::int[])
+		ORDER BY id
+	`, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer itemRows.Close()
+
+	indexByID := make(map[int]int, len(ids))
+	for i, id := range ids {
+		indexByID[id] = i
+	}
+	for itemRows.Next() {
+		var promotionID int
+		var productID, categoryID sql.NullInt64
+		var item model.PromotionItem
+		if err := itemRows.Scan(&promotionID, &productID, &categoryID, &item.Quantity); err != nil {
+			return nil, err
+		}
+		item.ProductID = int(productID.Int64)
+		item.CategoryID = int(categoryID.Int64)
+		i := indexByID[promotionID]
+		promos[i].Items = append(promos[i].Items, item)
+	}
+	if err := itemRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return promos, nil
+}
+
+// loadActivePromotions returns the promotions checkout has to evaluate.
+func loadActivePromotions(tx *sql.Tx) ([]model.Promotion, error) {
+	return queryPromotions(tx, " WHERE "+activePromotionCondition)
+}
+
+func (r *postgresPromotionRepository) Create(promo model.Promotion) (model.Promotion, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Promotion{}, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO promotions (name, type, percent_bp, amount, buy_qty, get_qty, min_spend, priority, active, starts_at, ends_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id
+	`, promo.Name, promo.Type, promo.PercentBP, promo.Amount, promo.BuyQty, promo.GetQty, promo.MinSpend,
+		promo.Priority, promo.Active, promo.StartsAt, promo.EndsAt).Scan(&promo.ID)
+	if err != nil {
+		return model.Promotion{}, err
+	}
+
+	if err := insertPromotionItems(tx, promo.ID, promo.Items); err != nil {
+		return model.Promotion{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.Promotion{}, err
+	}
+	return promo, nil
+}
+
+func (r *postgresPromotionRepository) GetAll() ([]model.Promotion, error) {
+	return queryPromotions(r.db, "")
+}
+
+func (r *postgresPromotionRepository) GetByID(id int) (model.Promotion, error) {
+	promos, err := queryPromotions(r.db, " WHERE id = $1", id)
+	if err != nil {
+		return model.Promotion{}, err
+	}
+	if len(promos) == 0 {
+		return model.Promotion{}, sql.ErrNoRows
+	}
+	return promos[0], nil
+}
+
+func (r *postgresPromotionRepository) Update(id int, promo model.Promotion) (model.Promotion, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Promotion{}, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE promotions
+		SET name = $1, type = $2, percent_bp = $3, amount = $4, buy_qty = $5, get_qty = $6, min_spend = $7,
+			priority = $8, active = $9, starts_at = $10, ends_at = $11
+		WHERE id = $12
+	`, promo.Name, promo.Type, promo.PercentBP, promo.Amount, promo.BuyQty, promo.GetQty, promo.MinSpend,
+		promo.Priority, promo.Active, promo.StartsAt, promo.EndsAt, id)
+	if err != nil {
+		return model.Promotion{}, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return model.Promotion{}, err
+	}
+	if rowsAffected == 0 {
+		return model.Promotion{}, sql.ErrNoRows
+	}
+
+	if _, err := tx.Exec("DELETE FROM promotion_items WHERE promotion_id = $1", id); err != nil {
+		return model.Promotion{}, err
+	}
+	if err := insertPromotionItems(tx, id, promo.Items); err != nil {
+		return model.Promotion{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.Promotion{}, err
+	}
+
+	promo.ID = id
+	return promo, nil
+}
+
+func (r *postgresPromotionRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM promotions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func insertPromotionItems(tx *sql.Tx, promotionID int, items []model.PromotionItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(items)*4)
+	var query strings.Builder
+	query.WriteString("INSERT INTO promotion_items (promotion_id, product_id, category_id, quantity) VALUES ")
+	argPos := 1
+	for i, item := range items {
+		if i > 0 {
+			query.WriteString(",")
+		}
+		query.WriteString(fmt.Sprintf("($%d::int, NULLIF($%d::int, 0), NULLIF($%d::int, 0), $%d::int)", argPos, argPos+1, argPos+2, argPos+3))
+		args = append(args, promotionID, item.ProductID, item.CategoryID, max(item.Quantity, 1))
+		argPos += 4
+	}
+
+	_, err := tx.Exec(query.String(), args...)
+	return err
+}
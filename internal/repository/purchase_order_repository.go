@@ -0,0 +1,422 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/internal/model"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+var ErrInvalidPurchaseOrder = errors.New("invalid purchase order")
+
+type PurchaseOrderRepository interface {
+	Create(req model.PurchaseOrderRequest) (*model.PurchaseOrder, error)
+	GetAll(filter model.PurchaseOrderFilter) ([]model.PurchaseOrder, error)
+	GetByID(id int) (*model.PurchaseOrder, error)
+	Receive(id int, req model.GoodsReceiptRequest) (*model.GoodsReceipt, error)
+	Close(id int, req model.ClosePurchaseOrderRequest) (*model.PurchaseOrder, error)
+}
+
+type postgresPurchaseOrderRepository struct {
+	db *sql.DB
+}
+
+func NewPurchaseOrderRepository(db *sql.DB) PurchaseOrderRepository {
+	return &postgresPurchaseOrderRepository{db: db}
+}
+
+// purchaseOrderColumns is the select list read by scanPurchaseOrder. It
+// expects purchase_orders aliased as po and suppliers joined as s.
+const purchaseOrderColumns = `po.id, po.supplier_id, s.name, po.status, COALESCE(po.note, ''),
+	COALESCE((SELECT SUM(l.quantity * l.unit_cost) FROM purchase_order_lines l WHERE l.purchase_order_id = po.id), 0),
+	COALESCE((SELECT SUM(gl.quantity * gl.unit_cost) FROM goods_receipt_lines gl
+		JOIN goods_receipts g ON gl.goods_receipt_id = g.id
+		WHERE g.purchase_order_id = po.id), 0),
+	po.created_by, po.created_at, po.closed_by, po.closed_at`
+
+func scanPurchaseOrder(row rowScanner) (model.PurchaseOrder, error) {
+	var po model.PurchaseOrder
+	var createdBy, closedBy sql.NullInt64
+	var closedAt sql.NullTime
+	err := row.Scan(&po.ID, &po.SupplierID, &po.SupplierName, &po.Status, &po.Note, &po.TotalCost, &po.ReceivedCost,
+		&createdBy, &po.CreatedAt, &closedBy, &closedAt)
+	if err != nil {
+		return model.PurchaseOrder{}, err
+	}
+	po.CreatedBy = int(createdBy.Int64)
+	po.ClosedBy = int(closedBy.Int64)
+	if closedAt.Valid {
+		po.ClosedAt = &closedAt.Time
+	}
+	return po, nil
+}
+
+// Create places a purchase order. The product names are copied onto the lines.
+func (r *postgresPurchaseOrderRepository) Create(req model.PurchaseOrderRequest) (*model.PurchaseOrder, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	createdBy := sql.NullInt64{Int64: int64(req.CreatedBy), Valid: req.CreatedBy != 0}
+	var id int
+	err = tx.QueryRow(
+		"INSERT INTO purchase_orders (supplier_id, status, note, created_by) VALUES ($1, $2, NULLIF($3, ''), $4) RETURNING id",
+		req.SupplierID, model.PurchaseOrderStatusOpen, req.Note, createdBy,
+	).Scan(&id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return nil, fmt.Errorf("%w: supplier %d not found", ErrInvalidPurchaseOrder, req.SupplierID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]interface{}, 0, len(req.Items)*4+1)
+	args = append(args, id)
+	var query strings.Builder
+	query.WriteString("INSERT INTO purchase_order_lines (purchase_order_id, product_id, product_name, quantity, unit_cost) SELECT $1::int, p.id, p.name, v.qty, v.cost FROM (VALUES ")
+	argPos := 2
+	for i, item := range req.Items {
+		if i > 0 {
+			query.WriteString(",")
+		}
+		query.WriteString(fmt.Sprintf("($%d::int, $%d::int, $%d::int, $%d::int)", argPos, argPos+1, argPos+2, argPos+3))
+		args = append(args, i, item.ProductID, item.Quantity, item.UnitCost)
+		argPos += 4
+	}
+	query.WriteString(") AS v(pos, product_id, qty, cost) JOIN products p ON p.id = v.product_id ORDER BY v.pos")
+
+	result, err := tx.Exec(query.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if int(inserted) != len(req.Items) {
+		return nil, fmt.Errorf("%w: one or more products not found", ErrInvalidPurchaseOrder)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// GetAll lists purchase orders, newest first, without their lines.
+func (r *postgresPurchaseOrderRepository) GetAll(filter model.PurchaseOrderFilter) ([]model.PurchaseOrder, error) {
+	conditions := []string{}
+	args := []interface{}{}
+	if filter.SupplierID != 0 {
+		args = append(args, filter.SupplierID)
+		conditions = append(conditions, fmt.Sprintf("po.supplier_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("po.status = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := r.db.Query("SELECT "+purchaseOrderColumns+" FROM purchase_orders po JOIN suppliers s ON po.supplier_id = s.id"+where+" ORDER BY po.created_at DESC, po.id DESC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []model.PurchaseOrder{}
+	for rows.Next() {
+		po, err := scanPurchaseOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, po)
+	}
+	return orders, rows.Err()
+}
+
+// GetByID returns a purchase order with its lines and goods receipts.
+func (r *postgresPurchaseOrderRepository) GetByID(id int) (*model.PurchaseOrder, error) {
+	po, err := scanPurchaseOrder(r.db.QueryRow("SELECT "+purchaseOrderColumns+" FROM purchase_orders po JOIN suppliers s ON po.supplier_id = s.id WHERE po.id = $1", id))
+	if err != nil {
+		return nil, err
+	}
+
+	po.Lines, err = getPurchaseOrderLines(r.db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	receiptRows, err := r.db.Query(`
+		SELECT id, COALESCE(note, ''), received_by, created_at
+		FROM goods_receipts
+		WHERE purchase_order_id = $1
+		ORDER BY id
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer receiptRows.Close()
+
+	indexByID := make(map[int]int)
+	for receiptRows.Next() {
+		g := model.GoodsReceipt{PurchaseOrderID: id, Lines: []model.GoodsReceiptLine{}}
+		var receivedBy sql.NullInt64
+		if err := receiptRows.Scan(&g.ID, &g.Note, &receivedBy, &g.CreatedAt); err != nil {
+			return nil, err
+		}
+		g.ReceivedBy = int(receivedBy.Int64)
+		indexByID[g.ID] = len(po.Receipts)
+		po.Receipts = append(po.Receipts, g)
+	}
+	if err := receiptRows.Err(); err != nil {
+		return nil, err
+	}
+	if len(po.Receipts) == 0 {
+		return &po, nil
+	}
+
+	lineRows, err := r.db.Query(`
+		SELECT gl.goods_receipt_id, gl.purchase_order_line_id, COALESCE(l.product_id, 0), l.product_name, gl.quantity, gl.unit_cost
+		FROM goods_receipt_lines gl
+		JOIN purchase_order_lines l ON gl.purchase_order_line_id = l.id
+		WHERE l.purchase_order_id = $1
+		ORDER BY gl.id
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer lineRows.Close()
+
+	for lineRows.Next() {
+		var receiptID int
+		var line model.GoodsReceiptLine
+		if err := lineRows.Scan(&receiptID, &line.PurchaseOrderLineID, &line.ProductID, &line.ProductName, &line.Quantity, &line.UnitCost); err != nil {
+			return nil, err
+		}
+		i := indexByID[receiptID]
+		po.Receipts[i].Lines = append(po.Receipts[i].Lines, line)
+	}
+	if err := lineRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &po, nil
+}
+
+// Receive books a delivery against an open purchase order: the delivered
+// units are added to stock and recorded in the stock ledger, and each
+// product's cost price becomes the weighted average of the stock already on
+// hand and the units received. The order is locked so
+// concurrent receipts cannot take a line past the quantity ordered.
+func (r *postgresPurchaseOrderRepository) Receive(id int, req model.GoodsReceiptRequest) (*model.GoodsReceipt, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockReceivablePurchaseOrder(tx, id); err != nil {
+		return nil, err
+	}
+
+	lines, err := getPurchaseOrderLines(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	lineByProduct := make(map[int]*model.PurchaseOrderLine, len(lines))
+	for i := range lines {
+		if lines[i].ProductID != 0 {
+			lineByProduct[lines[i].ProductID] = &lines[i]
+		}
+	}
+
+	receipt := model.GoodsReceipt{PurchaseOrderID: id, Note: req.Note, ReceivedBy: req.ReceivedBy}
+	for _, item := range req.Items {
+		line, ok := lineByProduct[item.ProductID]
+		if !ok {
+			return nil, fmt.Errorf("%w: product %d is not on purchase order %d", ErrInvalidPurchaseOrder, item.ProductID, id)
+		}
+		if outstanding := line.Quantity - line.ReceivedQuantity; item.Quantity > outstanding {
+			return nil, fmt.Errorf("%w: only %d of %s are still outstanding", ErrInvalidPurchaseOrder, outstanding, line.ProductName)
+		}
+		unitCost := item.UnitCost
+		if unitCost == 0 {
+			unitCost = line.UnitCost
+		}
+		line.ReceivedQuantity += item.Quantity
+		receipt.Lines = append(receipt.Lines, model.GoodsReceiptLine{
+			PurchaseOrderLineID: line.ID,
+			ProductID:           line.ProductID,
+			ProductName:         line.ProductName,
+			Quantity:            item.Quantity,
+			UnitCost:            unitCost,
+		})
+	}
+
+	receivedBy := sql.NullInt64{Int64: int64(req.ReceivedBy), Valid: req.ReceivedBy != 0}
+	err = tx.QueryRow(
+		"INSERT INTO goods_receipts (purchase_order_id, note, received_by) VALUES ($1, NULLIF($2, ''), $3) RETURNING id, created_at",
+		id, req.Note, receivedBy,
+	).Scan(&receipt.ID, &receipt.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	lineArgs := make([]interface{}, 0, len(receipt.Lines)*4)
+	var lineQuery strings.Builder
+	lineQuery.WriteString("INSERT INTO goods_receipt_lines (goods_receipt_id, purchase_order_line_id, quantity, unit_cost) VALUES ")
+	argPos := 1
+	for i, line := range receipt.Lines {
+		if i > 0 {
+			lineQuery.WriteString(",")
+		}
+		lineQuery.WriteString(fmt.Sprintf("($%d::int, $%d::int, $%d::int, $%d::int)", argPos, argPos+1, argPos+2, argPos+3))
+		lineArgs = append(lineArgs, receipt.ID, line.PurchaseOrderLineID, line.Quantity, line.UnitCost)
+		argPos += 4
+	}
+	if _, err := tx.Exec(lineQuery.String(), lineArgs...); err != nil {
+		return nil, err
+	}
+
+	orderArgs := make([]interface{}, 0, len(receipt.Lines)*2)
+	stockArgs := make([]interface{}, 0, len(receipt.Lines)*3)
+	var orderQuery, stockQuery strings.Builder
+	orderQuery.WriteString("UPDATE purchase_order_lines SET quantity_received = quantity_received + v.qty FROM (VALUES ")
+	// Stock at or below zero has no cost worth averaging in
+	stockQuery.WriteString("UPDATE products SET stock = stock + v.qty, cost_price = CASE WHEN products.stock > 0 " +
+		"THEN ROUND((products.stock::bigint * products.cost_price + v.qty::bigint * v.cost)::numeric / (products.stock + v.qty)) " +
+		"ELSE v.cost END FROM (VALUES ")
+	for i, line := range receipt.Lines {
+		if i > 0 {
+			orderQuery.WriteString(",")
+			stockQuery.WriteString(",")
+		}
+		orderQuery.WriteString(fmt.Sprintf("($%d::int, $%d::int)", 2*i+1, 2*i+2))
+		orderArgs = append(orderArgs, line.PurchaseOrderLineID, line.Quantity)
+		stockQuery.WriteString(fmt.Sprintf("($%d::int, $%d::int, $%d::int)", 3*i+1, 3*i+2, 3*i+3))
+		stockArgs = append(stockArgs, line.ProductID, line.Quantity, line.UnitCost)
+	}
+	orderQuery.WriteString(") AS v(id, qty) WHERE purchase_order_lines.id = v.id")
+	stockQuery.WriteString(") AS v(id, qty, cost) WHERE products.id = v.id RETURNING products.id, products.stock")
+
+	if _, err := tx.Exec(orderQuery.String(), orderArgs...); err != nil {
+		return nil, err
+	}
+	stockRows, err := tx.Query(stockQuery.String(), stockArgs...)
+	if err != nil {
+		return nil, err
+	}
+	stockAfter, err := scanStockLevels(stockRows)
+	if err != nil {
+		return nil, err
+	}
+
+	movements := make([]model.StockMovement, 0, len(receipt.Lines))
+	for _, line := range receipt.Lines {
+		movements = append(movements, model.StockMovement{
+			ProductID:     line.ProductID,
+			Type:          model.StockMovementReceiving,
+			Quantity:      line.Quantity,
+			StockAfter:    stockAfter[line.ProductID],
+			ReferenceType: model.StockReferenceGoodsReceipt,
+			ReferenceID:   receipt.ID,
+			UserID:        req.ReceivedBy,
+		})
+	}
+	if err := recordStockMovements(tx, movements); err != nil {
+		return nil, err
+	}
+
+	// Lines whose product has been deleted can no longer be received and do
+	// not hold the order open
+	status := model.PurchaseOrderStatusReceived
+	for _, line := range lines {
+		if line.ProductID != 0 && line.ReceivedQuantity < line.Quantity {
+			status = model.PurchaseOrderStatusPartiallyReceived
+			break
+		}
+	}
+	if _, err := tx.Exec("UPDATE purchase_orders SET status = $1 WHERE id = $2", status, id); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// Close stops an open or partially received order from taking further
+// receipts. What was received stays in stock.
+func (r *postgresPurchaseOrderRepository) Close(id int, req model.ClosePurchaseOrderRequest) (*model.PurchaseOrder, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockReceivablePurchaseOrder(tx, id); err != nil {
+		return nil, err
+	}
+
+	closedBy := sql.NullInt64{Int64: int64(req.ClosedBy), Valid: req.ClosedBy != 0}
+	_, err = tx.Exec(`
+		UPDATE purchase_orders
+		SET status = $1, note = COALESCE(NULLIF($2, ''), note), closed_by = $3, closed_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+	`, model.PurchaseOrderStatusClosed, req.Note, closedBy, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// lockReceivablePurchaseOrder locks a purchase order row for update and fails
+// unless it can still take goods receipts.
+func lockReceivablePurchaseOrder(tx *sql.Tx, id int) error {
+	var status string
+	if err := tx.QueryRow("SELECT status FROM purchase_orders WHERE id = $1 FOR UPDATE", id).Scan(&status); err != nil {
+		return err
+	}
+	if status != model.PurchaseOrderStatusOpen && status != model.PurchaseOrderStatusPartiallyReceived {
+		return fmt.Errorf("%w: purchase order %d is already %s", ErrInvalidPurchaseOrder, id, status)
+	}
+	return nil
+}
+
+func getPurchaseOrderLines(q queryer, purchaseOrderID int) ([]model.PurchaseOrderLine, error) {
+	rows, err := q.Query(`
+		SELECT id, COALESCE(product_id, 0), product_name, quantity, quantity_received, unit_cost
+		FROM purchase_order_lines
+		WHERE purchase_order_id = $1
+		ORDER BY id
+	`, purchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := []model.PurchaseOrderLine{}
+	for rows.Next() {
+		var l model.PurchaseOrderLine
+		if err := rows.Scan(&l.ID, &l.ProductID, &l.ProductName, &l.Quantity, &l.ReceivedQuantity, &l.UnitCost); err != nil {
+			return nil, err
+		}
+		lines = append(lines, l)
+	}
+	return lines, rows.Err()
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"database/sql"
+	"kasir-api/internal/model"
+)
+
+type ReceiptRepository interface {
+	RecordPrint(transactionID int, format string, printedBy int) (*model.ReceiptPrint, error)
+}
+
+type postgresReceiptRepository struct {
+	db *sql.DB
+}
+
+func NewReceiptRepository(db *sql.DB) ReceiptRepository {
+	return &postgresReceiptRepository{db: db}
+}
+
+// RecordPrint logs a receipt of the transaction and returns its print
+// number. The transaction row is locked while numbering, so two receipts
+// printed at once still get consecutive numbers. It fails with
+// sql.ErrNoRows when the transaction does not exist.
+func (r *postgresReceiptRepository) RecordPrint(transactionID int, format string, printedBy int) (*model.ReceiptPrint, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var id int
+	if err := tx.QueryRow("SELECT id FROM transactions WHERE id = $1 FOR UPDATE", transactionID).Scan(&id); err != nil {
+		return nil, err
+	}
+
+	p := model.ReceiptPrint{TransactionID: transactionID, Format: format, PrintedBy: printedBy}
+	err = tx.QueryRow(`
+		INSERT INTO receipt_prints (transaction_id, print_number, format, printed_by)
+		SELECT $1, COALESCE(MAX(print_number), 0) + 1, $2, $3
+		FROM receipt_prints
+		WHERE transaction_id = $1
+		RETURNING print_number, printed_at
+	`, transactionID, format, sql.NullInt64{Int64: int64(printedBy), Valid: printedBy != 0}).Scan(&p.PrintNumber, &p.PrintedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"database/sql"
+	"kasir-api/internal/model"
+
+	"github.com/lib/pq"
+)
+
+type RoleRepository interface {
+	Create(role model.Role) (model.Role, error)
+	GetAll() ([]model.Role, error)
+	GetByName(name string) (model.Role, error)
+	Update(name string, role model.Role) (model.Role, error)
+}
+
+type postgresRoleRepository struct {
+	db *sql.DB
+}
+
+func NewRoleRepository(db *sql.DB) RoleRepository {
+	return &postgresRoleRepository{db: db}
+}
+
+func (r *postgresRoleRepository) Create(role model.Role) (model.Role, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Role{}, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`INSERT INTO roles (name, description) VALUES ($1, $2)`, role.Name, role.Description); err != nil {
+		return model.Role{}, err
+	}
+	if err := replacePermissions(tx, role.Name, role.Permissions); err != nil {
+		return model.Role{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Role{}, err
+	}
+	return role, nil
+}
+
+func (r *postgresRoleRepository) GetAll() ([]model.Role, error) {
+	rows, err := r.db.Query(`
+		SELECT r.name, r.description, COALESCE(array_agg(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role = r.name
+		GROUP BY r.name, r.description
+		ORDER BY r.name
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []model.Role
+	for rows.Next() {
+		var role model.Role
+		if err := rows.Scan(&role.Name, &role.Description, pq.Array(&role.Permissions)); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+func (r *postgresRoleRepository) GetByName(name string) (model.Role, error) {
+	var role model.Role
+	err := r.db.QueryRow(`
+		SELECT r.name, r.description, COALESCE(array_agg(rp.permission ORDER BY rp.permission) FILTER (WHERE rp.permission IS NOT NULL), '{}')
+		FROM roles r
+		LEFT JOIN role_permissions rp ON rp.role = r.name
+		WHERE r.name = $1
+		GROUP BY r.name, r.description
+	`, name).Scan(&role.Name, &role.Description, pq.Array(&role.Permissions))
+	if err != nil {
+		return model.Role{}, err
+	}
+	return role, nil
+}
+
+func (r *postgresRoleRepository) Update(name string, role model.Role) (model.Role, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Role{}, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE roles SET description = $1 WHERE name = $2`, role.Description, name)
+	if err != nil {
+		return model.Role{}, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return model.Role{}, err
+	}
+	if rowsAffected == 0 {
+		return model.Role{}, sql.ErrNoRows
+	}
+
+	if err := replacePermissions(tx, name, role.Permissions); err != nil {
+		return model.Role{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Role{}, err
+	}
+	role.Name = name
+	return role, nil
+}
+
+func replacePermissions(tx *sql.Tx, role string, permissions []string) error {
+	if _, err := tx.Exec(`DELETE FROM role_permissions WHERE role = $1`, role); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`
+		INSERT INTO role_permissions (role, permission)
+		SELECT $1, p FROM unnest($2::text[]) AS p
+		ON CONFLICT DO NOTHING
+	`, role, pq.Array(permissions))
+	return err
+}
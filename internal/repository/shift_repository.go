@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/internal/model"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+var ErrInvalidShift = errors.New("invalid shift operation")
+
+type ShiftRepository interface {
+	Open(req model.OpenShiftRequest) (*model.Shift, error)
+	GetAll(filter model.ShiftFilter) ([]model.Shift, error)
+	GetByID(id int) (*model.Shift, error)
+	AddCashMovement(shiftID int, req model.CashMovementRequest) (*model.CashMovement, error)
+	Close(id int, req model.CloseShiftRequest) (*model.Shift, error)
+}
+
+type postgresShiftRepository struct {
+	db *sql.DB
+}
+
+func NewShiftRepository(db *sql.DB) ShiftRepository {
+	return &postgresShiftRepository{db: db}
+}
+
+// shiftColumns is the select list read by scanShift. The cash totals are
+// computed from the sales, refunds and cash movements attributed to the shift;
+// it expects shifts aliased as s and users (the cashier) left-joined as u.
+const shiftColumns = `s.id, s.terminal_id, s.cashier_id, COALESCE(NULLIF(u.name, ''), u.username, ''), s.status, s.opening_float,
+	COALESCE((SELECT SUM(p.amount - p.change_amount) FROM transaction_payments p
+		JOIN transactions t ON p.transaction_id = t.id
+		WHERE t.shift_id = s.id AND p.method = 'cash'), 0),
+	COALESCE((SELECT SUM(rp.amount) FROM refund_payments rp
+		JOIN refunds rf ON rp.refund_id = rf.id
+		WHERE rf.shift_id = s.id AND rp.method = 'cash'), 0),
+	COALESCE((SELECT SUM(m.amount) FROM cash_movements m WHERE m.shift_id = s.id AND m.type = 'pay_in'), 0),
+	COALESCE((SELECT SUM(m.amount) FROM cash_movements m WHERE m.shift_id = s.id AND m.type = 'pay_out'), 0),
+	s.expected_cash, s.counted_cash, s.discrepancy, COALESCE(s.note, ''), s.opened_at, s.closed_at, s.closed_by`
+
+func scanShift(row rowScanner) (model.Shift, error) {
+	var s model.Shift
+	var cashierID, expected, counted, discrepancy, closedBy sql.NullInt64
+	var closedAt sql.NullTime
+	err := row.Scan(&s.ID, &s.TerminalID, &cashierID, &s.CashierName, &s.Status, &s.OpeningFloat,
+		&s.CashSales, &s.CashRefunds, &s.PayIns, &s.PayOuts,
+		&expected, &counted, &discrepancy, &s.Note, &s.OpenedAt, &closedAt, &closedBy)
+	if err != nil {
+		return model.Shift{}, err
+	}
+
+	s.CashierID = int(cashierID.Int64)
+	s.ClosedBy = int(closedBy.Int64)
+	if expected.Valid {
+		s.ExpectedCash = int(expected.Int64)
+	} else {
+		s.ExpectedCash = s.OpeningFloat + s.CashSales - s.CashRefunds + s.PayIns - s.PayOuts
+	}
+	if counted.Valid {
+		v := int(counted.Int64)
+		s.CountedCash = &v
+	}
+	if discrepancy.Valid {
+		v := int(discrepancy.Int64)
+		s.Discrepancy = &v
+	}
+	if closedAt.Valid {
+		s.ClosedAt = &closedAt.Time
+	}
+	return s, nil
+}
+
+func (r *postgresShiftRepository) Open(req model.OpenShiftRequest) (*model.Shift, error) {
+	cashierID := sql.NullInt64{Int64: int64(req.CashierID), Valid: req.CashierID != 0}
+
+	var id int
+	err := r.db.QueryRow(
+		"INSERT INTO shifts (terminal_id, cashier_id, opening_float) VALUES ($1, $2, $3) RETURNING id",
+		req.TerminalID, cashierID, req.OpeningFloat,
+	).Scan(&id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return nil, fmt.Errorf("%w: terminal %s already has an open shift", ErrInvalidShift, req.TerminalID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(id)
+}
+
+func (r *postgresShiftRepository) GetAll(filter model.ShiftFilter) ([]model.Shift, error) {
+	conditions := []string{}
+	args := []interface{}{}
+	if filter.TerminalID != "" {
+		args = append(args, filter.TerminalID)
+		conditions = append(conditions, fmt.Sprintf("s.terminal_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("s.status = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	rows, err := r.db.Query("SELECT "+shiftColumns+" FROM shifts s LEFT JOIN users u ON s.cashier_id = u.id"+where+" ORDER BY s.opened_at DESC, s.id DESC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shifts := []model.Shift{}
+	for rows.Next() {
+		s, err := scanShift(rows)
+		if err != nil {
+			return nil, err
+		}
+		shifts = append(shifts, s)
+	}
+	return shifts, rows.Err()
+}
+
+func (r *postgresShiftRepository) GetByID(id int) (*model.Shift, error) {
+	row := r.db.QueryRow("SELECT "+shiftColumns+" FROM shifts s LEFT JOIN users u ON s.cashier_id = u.id WHERE s.id = $1", id)
+	s, err := scanShift(row)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT id, shift_id, type, amount, reason, created_by, created_at
+		FROM cash_movements
+		WHERE shift_id = $1
+		ORDER BY id
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m model.CashMovement
+		var createdBy sql.NullInt64
+		if err := rows.Scan(&m.ID, &m.ShiftID, &m.Type, &m.Amount, &m.Reason, &createdBy, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		m.CreatedBy = int(createdBy.Int64)
+		s.CashMovements = append(s.CashMovements, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+func (r *postgresShiftRepository) AddCashMovement(shiftID int, req model.CashMovementRequest) (*model.CashMovement, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenShift(tx, shiftID); err != nil {
+		return nil, err
+	}
+
+	m := model.CashMovement{ShiftID: shiftID, Type: req.Type, Amount: req.Amount, Reason: req.Reason, CreatedBy: req.CreatedBy}
+	createdBy := sql.NullInt64{Int64: int64(req.CreatedBy), Valid: req.CreatedBy != 0}
+	err = tx.QueryRow(
+		"INSERT INTO cash_movements (shift_id, type, amount, reason, created_by) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at",
+		shiftID, m.Type, m.Amount, m.Reason, createdBy,
+	).Scan(&m.ID, &m.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Close fixes the expected cash of an open shift and records the counted
+// amount. The shift row is locked first, so checkouts attaching themselves to
+// the shift either finish before the totals are taken or see it closed.
+func (r *postgresShiftRepository) Close(id int, req model.CloseShiftRequest) (*model.Shift, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenShift(tx, id); err != nil {
+		return nil, err
+	}
+
+	s, err := scanShift(tx.QueryRow("SELECT "+shiftColumns+" FROM shifts s LEFT JOIN users u ON s.cashier_id = u.id WHERE s.id = $1", id))
+	if err != nil {
+		return nil, err
+	}
+
+	discrepancy := req.CountedCash - s.ExpectedCash
+	closedBy := sql.NullInt64{Int64: int64(req.ClosedBy), Valid: req.ClosedBy != 0}
+	_, err = tx.Exec(`
+		UPDATE shifts
+		SET status = $1, expected_cash = $2, counted_cash = $3, discrepancy = $4, note = NULLIF($5, ''),
+			closed_at = CURRENT_TIMESTAMP, closed_by = $6
+		WHERE id = $7
+	`, model.ShiftStatusClosed, s.ExpectedCash, req.CountedCash, discrepancy, req.Note, closedBy, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// lockOpenShift locks a shift row for update and fails unless it is still open.
+func lockOpenShift(tx *sql.Tx, id int) error {
+	var status string
+	if err := tx.QueryRow("SELECT status FROM shifts WHERE id = $1 FOR UPDATE", id).Scan(&status); err != nil {
+		return err
+	}
+	if status != model.ShiftStatusOpen {
+		return fmt.Errorf("%w: shift %d is already closed", ErrInvalidShift, id)
+	}
+	return nil
+}
+
+// openShiftID returns the open shift at terminalID, if any, holding a share
+// lock on it until tx ends so the shift cannot be closed underneath a sale.
+func openShiftID(tx *sql.Tx, terminalID string) (sql.NullInt64, error) {
+	var shiftID sql.NullInt64
+	if terminalID == "" {
+		return shiftID, nil
+	}
+	err := tx.QueryRow("SELECT id FROM shifts WHERE terminal_id = $1 AND status = $2 FOR SHARE", terminalID, model.ShiftStatusOpen).Scan(&shiftID)
+	if err == sql.ErrNoRows {
+		return sql.NullInt64{}, nil
+	}
+	return shiftID, err
+}
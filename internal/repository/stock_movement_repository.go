@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/internal/businessday"
+	"kasir-api/internal/model"
+	"strings"
+)
+
+var ErrInvalidStockAdjustment = errors.New("invalid stock adjustment")
+
+type StockMovementRepository interface {
+	GetByProduct(productID int, filter model.StockMovementFilter) ([]model.StockMovement, error)
+	Adjust(productID int, req model.StockAdjustmentRequest) (*model.StockMovement, error)
+	GetLowStockCrossings(transactionID int) ([]model.LowStockAlert, error)
+}
+
+type postgresStockMovementRepository struct {
+	db       *sql.DB
+	calendar *businessday.Calendar
+}
+
+func NewStockMovementRepository(db *sql.DB, calendar *businessday.Calendar) StockMovementRepository {
+	return &postgresStockMovementRepository{db: db, calendar: calendar}
+}
+
+// GetByProduct returns the stock ledger of a product, newest first.
+func (r *postgresStockMovementRepository) GetByProduct(productID int, filter model.StockMovementFilter) ([]model.StockMovement, error) {
+	conditions := []string{"sm.product_id = $1"}
+	args := []interface{}{productID}
+	addCondition := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(cond, len(args)))
+	}
+	if filter.Type != "" {
+		addCondition("sm.type = $%d", filter.Type)
+	}
+	if filter.StartDate != "" {
+		from, err := r.calendar.Start(filter.StartDate)
+		if err != nil {
+			return nil, err
+		}
+		addCondition("sm.created_at >= $%d", from)
+	}
+	if filter.EndDate != "" {
+		to, err := r.calendar.End(filter.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		addCondition("sm.created_at < $%d", to)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT sm.id, sm.product_id, sm.type, sm.quantity, sm.stock_after, COALESCE(sm.reason, ''), COALESCE(sm.note, ''),
+			COALESCE(sm.reference_type, ''), sm.reference_id, sm.user_id, COALESCE(NULLIF(u.name, ''), u.username, ''), sm.created_at
+		FROM stock_movements sm
+		LEFT JOIN users u ON sm.user_id = u.id
+		WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY sm.id DESC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := []model.StockMovement{}
+	for rows.Next() {
+		var m model.StockMovement
+		var referenceID, userID sql.NullInt64
+		if err := rows.Scan(&m.ID, &m.ProductID, &m.Type, &m.Quantity, &m.StockAfter, &m.Reason, &m.Note,
+			&m.ReferenceType, &referenceID, &userID, &m.UserName, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		m.ReferenceID = int(referenceID.Int64)
+		m.UserID = int(userID.Int64)
+		movements = append(movements, m)
+	}
+	return movements, rows.Err()
+}
+
+// Adjust applies a signed change to a product's stock and records it. The
+// product row is locked so the check against negative stock holds against
+// concurrent checkouts.
+func (r *postgresStockMovementRepository) Adjust(productID int, req model.StockAdjustmentRequest) (*model.StockMovement, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var name string
+	var stock int
+	if err := tx.QueryRow("SELECT name, stock FROM products WHERE id = $1 FOR UPDATE", productID).Scan(&name, &stock); err != nil {
+		return nil, err
+	}
+	if stock+req.Quantity < 0 {
+		return nil, fmt.Errorf("%w: %s has only %d in stock", ErrInvalidStockAdjustment, name, stock)
+	}
+
+	m := model.StockMovement{
+		ProductID: productID,
+		Type:      model.StockMovementAdjustment,
+		Quantity:  req.Quantity,
+		Reason:    req.ReasonCode,
+		Note:      req.Note,
+		UserID:    req.UserID,
+	}
+	if err := tx.QueryRow("UPDATE products SET stock = stock + $1 WHERE id = $2 RETURNING stock", req.Quantity, productID).Scan(&m.StockAfter); err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO stock_movements (product_id, type, quantity, stock_after, reason, note, user_id)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, 0))
+		RETURNING id, created_at
+	`, m.ProductID, m.Type, m.Quantity, m.StockAfter, m.Reason, m.Note, m.UserID).Scan(&m.ID, &m.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// GetLowStockCrossings returns the products a sale took from at or above
+// their minimum stock to below it. Products that were already low before the
+// sale are left out so every shortage is reported once.
+func (r *postgresStockMovementRepository) GetLowStockCrossings(transactionID int) ([]model.LowStockAlert, error) {
+	rows, err := r.db.Query(`
+		SELECT p.id, p.name, sm.stock_after, p.min_stock, p.reorder_quantity
+		FROM stock_movements sm
+		JOIN products p ON sm.product_id = p.id
+		WHERE sm.reference_type = $1 AND sm.reference_id = $2 AND sm.type = $3
+			AND p.min_stock > 0
+			AND sm.stock_after < p.min_stock
+			AND sm.stock_after - sm.quantity >= p.min_stock
+		ORDER BY p.name
+	`, model.StockReferenceTransaction, transactionID, model.StockMovementSale)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts := []model.LowStockAlert{}
+	for rows.Next() {
+		a := model.LowStockAlert{TransactionID: transactionID}
+		if err := rows.Scan(&a.ProductID, &a.ProductName, &a.Stock, &a.MinStock, &a.ReorderQuantity); err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, rows.Err()
+}
+
+// recordStockMovements appends movements to the stock ledger. Callers fill in
+// StockAfter from the products row they changed, inside the same tx.
+func recordStockMovements(tx *sql.Tx, movements []model.StockMovement) error {
+	if len(movements) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(movements)*9)
+	var query strings.Builder
+	query.WriteString("INSERT INTO stock_movements (product_id, type, quantity, stock_after, reason, note, reference_type, reference_id, user_id) VALUES ")
+	argPos := 1
+	for i, m := range movements {
+		if i > 0 {
+			query.WriteString(",")
+		}
+		query.WriteString(fmt.Sprintf("($%d::int, $%d::text, $%d::int, $%d::int, NULLIF($%d::text, ''), NULLIF($%d::text, ''), NULLIF($%d::text, ''), NULLIF($%d::int, 0), NULLIF($%d::int, 0))",
+			argPos, argPos+1, argPos+2, argPos+3, argPos+4, argPos+5, argPos+6, argPos+7, argPos+8))
+		args = append(args, m.ProductID, m.Type, m.Quantity, m.StockAfter, m.Reason, m.Note, m.ReferenceType, m.ReferenceID, m.UserID)
+		argPos += 9
+	}
+
+	_, err := tx.Exec(query.String(), args...)
+	return err
+}
+
+// scanStockLevels reads the (id, stock) rows returned by a bulk stock update.
+func scanStockLevels(rows *sql.Rows) (map[int]int, error) {
+	defer rows.Close()
+	levels := make(map[int]int)
+	for rows.Next() {
+		var id, stock int
+		if err := rows.Scan(&id, &stock); err != nil {
+			return nil, err
+		}
+		levels[id] = stock
+	}
+	return levels, rows.Err()
+}
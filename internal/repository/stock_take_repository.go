@@ -0,0 +1,437 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/internal/model"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+var ErrInvalidStockTake = errors.New("invalid stock take")
+
+type StockTakeRepository interface {
+	Open(req model.OpenStockTakeRequest) (*model.StockTake, error)
+	GetAll(filter model.StockTakeFilter) ([]model.StockTake, error)
+	GetByID(id int) (*model.StockTake, error)
+	SubmitCounts(id int, req model.StockCountRequest) (*model.StockTake, error)
+	Post(id int, userID int) (*model.StockTake, error)
+	Cancel(id int, userID int) (*model.StockTake, error)
+}
+
+type postgresStockTakeRepository struct {
+	db *sql.DB
+}
+
+func NewStockTakeRepository(db *sql.DB) StockTakeRepository {
+	return &postgresStockTakeRepository{db: db}
+}
+
+// stockTakeColumns is the select list read by scanStockTake. It expects
+// stock_takes aliased as st and categories left-joined as c.
+const stockTakeColumns = `st.id, COALESCE(st.category_id, 0), COALESCE(c.name, ''), st.status, COALESCE(st.note, ''),
+	(SELECT COUNT(*) FROM stock_take_lines l WHERE l.stock_take_id = st.id),
+	(SELECT COUNT(DISTINCT l.id) FROM stock_take_lines l
+		JOIN stock_take_counts sc ON sc.stock_take_line_id = l.id
+		WHERE l.stock_take_id = st.id),
+	st.opened_by, st.opened_at, st.closed_by, st.closed_at`
+
+const stockTakeFrom = ` FROM stock_takes st LEFT JOIN categories c ON st.category_id = c.id`
+
+func scanStockTake(row rowScanner) (model.StockTake, error) {
+	var st model.StockTake
+	var openedBy, closedBy sql.NullInt64
+	var closedAt sql.NullTime
+	err := row.Scan(&st.ID, &st.CategoryID, &st.CategoryName, &st.Status, &st.Note, &st.ProductCount, &st.CountedCount,
+		&openedBy, &st.OpenedAt, &closedBy, &closedAt)
+	if err != nil {
+		return model.StockTake{}, err
+	}
+	st.OpenedBy = int(openedBy.Int64)
+	st.ClosedBy = int(closedBy.Int64)
+	if closedAt.Valid {
+		st.ClosedAt = &closedAt.Time
+	}
+	return st, nil
+}
+
+// Open starts a stock take and snapshots the stock of the products in scope.
+// Sessions whose scopes overlap cannot be open at the same time, or posting
+// both would apply the same variance twice; the table lock keeps two
+// sessions from being opened side by side.
+func (r *postgresStockTakeRepository) Open(req model.OpenStockTakeRequest) (*model.StockTake, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("LOCK TABLE stock_takes IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return nil, err
+	}
+
+	var openID int
+	err = tx.QueryRow(`
+		SELECT id FROM stock_takes
+		WHERE status = $1 AND ($2 = 0 OR category_id IS NULL OR category_id = $2)
+		LIMIT 1
+	`, model.StockTakeStatusOpen, req.CategoryID).Scan(&openID)
+	if err == nil {
+		return nil, fmt.Errorf("%w: stock take %d is still open for these products", ErrInvalidStockTake, openID)
+	}
+	if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	if req.CategoryID != 0 {
+		var exists bool
+		if err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM categories WHERE id = $1)", req.CategoryID).Scan(&exists); err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("%w: category %d not found", ErrInvalidStockTake, req.CategoryID)
+		}
+	}
+
+	openedBy := sql.NullInt64{Int64: int64(req.OpenedBy), Valid: req.OpenedBy != 0}
+	var id int
+	err = tx.QueryRow(
+		"INSERT INTO stock_takes (category_id, status, note, opened_by) VALUES (NULLIF($1, 0), $2, NULLIF($3, ''), $4) RETURNING id",
+		req.CategoryID, model.StockTakeStatusOpen, req.Note, openedBy,
+	).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO stock_take_lines (stock_take_id, product_id, product_name, snapshot_stock)
+		SELECT $1::int, id, name, stock FROM products
+		WHERE $2::int = 0 OR category_id = $2::int
+		ORDER BY name, id
+	`, id, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	products, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if products == 0 {
+		return nil, fmt.Errorf("%w: there are no products to count", ErrInvalidStockTake)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// GetAll lists stock takes, newest first, without their lines.
+func (r *postgresStockTakeRepository) GetAll(filter model.StockTakeFilter) ([]model.StockTake, error) {
+	query := "SELECT " + stockTakeColumns + stockTakeFrom
+	args := []interface{}{}
+	if filter.Status != "" {
+		query += " WHERE st.status = $1"
+		args = append(args, filter.Status)
+	}
+	query += " ORDER BY st.opened_at DESC, st.id DESC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	takes := []model.StockTake{}
+	for rows.Next() {
+		st, err := scanStockTake(rows)
+		if err != nil {
+			return nil, err
+		}
+		takes = append(takes, st)
+	}
+	return takes, rows.Err()
+}
+
+// GetByID returns a stock take with its lines, their counts and variances.
+func (r *postgresStockTakeRepository) GetByID(id int) (*model.StockTake, error) {
+	st, err := scanStockTake(r.db.QueryRow("SELECT "+stockTakeColumns+stockTakeFrom+" WHERE st.id = $1", id))
+	if err != nil {
+		return nil, err
+	}
+	st.Lines, err = getStockTakeLines(r.db, id)
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// SubmitCounts records what the caller's device counted. The session is
+// share-locked so it cannot be posted while counts are coming in, and each
+// count keeps the product's stock at that moment to measure the variance
+// against.
+func (r *postgresStockTakeRepository) SubmitCounts(id int, req model.StockCountRequest) (*model.StockTake, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenStockTake(tx, id, "FOR SHARE"); err != nil {
+		return nil, err
+	}
+
+	productIDs := make([]int, len(req.Items))
+	for i, item := range req.Items {
+		productIDs[i] = item.ProductID
+	}
+	rows, err := tx.Query(`
+		SELECT l.product_id, l.id, p.stock
+		FROM stock_take_lines l
+		JOIN products p ON l.product_id = p.id
+		WHERE l.stock_take_id = $1 AND l.product_id = ANY($2::int[])
+	`, id, pq.Array(productIDs))
+	if err != nil {
+		return nil, err
+	}
+	type lineStock struct{ lineID, stock int }
+	lines := make(map[int]lineStock, len(req.Items))
+	for rows.Next() {
+		var productID int
+		var ls lineStock
+		if err := rows.Scan(&productID, &ls.lineID, &ls.stock); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		lines[productID] = ls
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	countedBy := sql.NullInt64{Int64: int64(req.CountedBy), Valid: req.CountedBy != 0}
+	args := make([]interface{}, 0, len(req.Items)*5)
+	var query strings.Builder
+	query.WriteString("INSERT INTO stock_take_counts (stock_take_line_id, device, quantity, stock_at_count, counted_by) VALUES ")
+	argPos := 1
+	for i, item := range req.Items {
+		ls, ok := lines[item.ProductID]
+		if !ok {
+			return nil, fmt.Errorf("%w: product %d is not part of stock take %d", ErrInvalidStockTake, item.ProductID, id)
+		}
+		if i > 0 {
+			query.WriteString(",")
+		}
+		query.WriteString(fmt.Sprintf("($%d::int, $%d::text, $%d::int, $%d::int, $%d::int)", argPos, argPos+1, argPos+2, argPos+3, argPos+4))
+		args = append(args, ls.lineID, req.Device, item.Quantity, ls.stock, countedBy)
+		argPos += 5
+	}
+	query.WriteString(` ON CONFLICT (stock_take_line_id, device) DO UPDATE
+		SET quantity = EXCLUDED.quantity, stock_at_count = EXCLUDED.stock_at_count,
+			counted_by = EXCLUDED.counted_by, counted_at = CURRENT_TIMESTAMP`)
+	if _, err := tx.Exec(query.String(), args...); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// Post applies the variance of every counted product to its current stock and
+// records it in the stock ledger. Applying the variance rather than the
+// counted quantity keeps the sales and receipts made since the count.
+func (r *postgresStockTakeRepository) Post(id int, userID int) (*model.StockTake, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenStockTake(tx, id, "FOR UPDATE"); err != nil {
+		return nil, err
+	}
+
+	lines, err := getStockTakeLines(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustments := []model.StockTakeLine{}
+	for _, line := range lines {
+		if line.ProductID != 0 && line.Variance != nil && *line.Variance != 0 {
+			adjustments = append(adjustments, line)
+		}
+	}
+
+	if len(adjustments) > 0 {
+		updateArgs := make([]interface{}, 0, len(adjustments)*2)
+		var updateQuery strings.Builder
+		updateQuery.WriteString("UPDATE products SET stock = stock + v.qty FROM (VALUES ")
+		for i, line := range adjustments {
+			if i > 0 {
+				updateQuery.WriteString(",")
+			}
+			updateQuery.WriteString(fmt.Sprintf("($%d::int, $%d::int)", 2*i+1, 2*i+2))
+			updateArgs = append(updateArgs, line.ProductID, *line.Variance)
+		}
+		updateQuery.WriteString(") AS v(id, qty) WHERE products.id = v.id RETURNING products.id, products.stock")
+
+		stockRows, err := tx.Query(updateQuery.String(), updateArgs...)
+		if err != nil {
+			return nil, err
+		}
+		stockAfter, err := scanStockLevels(stockRows)
+		if err != nil {
+			return nil, err
+		}
+
+		movements := make([]model.StockMovement, 0, len(adjustments))
+		for _, line := range adjustments {
+			movements = append(movements, model.StockMovement{
+				ProductID:     line.ProductID,
+				Type:          model.StockMovementStockTake,
+				Quantity:      *line.Variance,
+				StockAfter:    stockAfter[line.ProductID],
+				ReferenceType: model.StockReferenceStockTake,
+				ReferenceID:   id,
+				UserID:        userID,
+			})
+		}
+		if err := recordStockMovements(tx, movements); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := closeStockTake(tx, id, model.StockTakeStatusPosted, userID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// Cancel closes a stock take without touching stock.
+func (r *postgresStockTakeRepository) Cancel(id int, userID int) (*model.StockTake, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenStockTake(tx, id, "FOR UPDATE"); err != nil {
+		return nil, err
+	}
+	if err := closeStockTake(tx, id, model.StockTakeStatusCancelled, userID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(id)
+}
+
+// lockOpenStockTake locks a stock take row with the given locking clause and
+// fails unless it is still open.
+func lockOpenStockTake(tx *sql.Tx, id int, lock string) error {
+	var status string
+	if err := tx.QueryRow("SELECT status FROM stock_takes WHERE id = $1 "+lock, id).Scan(&status); err != nil {
+		return err
+	}
+	if status != model.StockTakeStatusOpen {
+		return fmt.Errorf("%w: stock take %d is already %s", ErrInvalidStockTake, id, status)
+	}
+	return nil
+}
+
+func closeStockTake(tx *sql.Tx, id int, status string, userID int) error {
+	closedBy := sql.NullInt64{Int64: int64(userID), Valid: userID != 0}
+	_, err := tx.Exec(
+		"UPDATE stock_takes SET status = $1, closed_by = $2, closed_at = CURRENT_TIMESTAMP WHERE id = $3",
+		status, closedBy, id,
+	)
+	return err
+}
+
+// getStockTakeLines loads the lines of a stock take with their counts and
+// works out the variance of each counted line.
+func getStockTakeLines(q queryer, stockTakeID int) ([]model.StockTakeLine, error) {
+	rows, err := q.Query(`
+		SELECT l.id, COALESCE(l.product_id, 0), l.product_name, l.snapshot_stock, COALESCE(p.cost_price, 0)
+		FROM stock_take_lines l
+		LEFT JOIN products p ON l.product_id = p.id
+		WHERE l.stock_take_id = $1
+		ORDER BY l.id
+	`, stockTakeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := []model.StockTakeLine{}
+	costPrices := []int{}
+	indexByID := make(map[int]int)
+	for rows.Next() {
+		var lineID, costPrice int
+		var line model.StockTakeLine
+		if err := rows.Scan(&lineID, &line.ProductID, &line.ProductName, &line.SnapshotStock, &costPrice); err != nil {
+			return nil, err
+		}
+		indexByID[lineID] = len(lines)
+		lines = append(lines, line)
+		costPrices = append(costPrices, costPrice)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	countRows, err := q.Query(`
+		SELECT c.stock_take_line_id, c.device, c.quantity, c.stock_at_count, c.counted_by, c.counted_at
+		FROM stock_take_counts c
+		JOIN stock_take_lines l ON c.stock_take_line_id = l.id
+		WHERE l.stock_take_id = $1
+		ORDER BY c.counted_at, c.id
+	`, stockTakeID)
+	if err != nil {
+		return nil, err
+	}
+	defer countRows.Close()
+
+	for countRows.Next() {
+		var lineID int
+		var c model.StockCount
+		var countedBy sql.NullInt64
+		if err := countRows.Scan(&lineID, &c.Device, &c.Quantity, &c.StockAtCount, &countedBy, &c.CountedAt); err != nil {
+			return nil, err
+		}
+		c.CountedBy = int(countedBy.Int64)
+		i := indexByID[lineID]
+		lines[i].Counts = append(lines[i].Counts, c)
+	}
+	if err := countRows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range lines {
+		counts := lines[i].Counts
+		if len(counts) == 0 {
+			continue
+		}
+		counted := 0
+		for _, c := range counts {
+			counted += c.Quantity
+		}
+		expected := counts[len(counts)-1].StockAtCount
+		variance := counted - expected
+		lines[i].CountedQuantity = &counted
+		lines[i].ExpectedStock = &expected
+		lines[i].Variance = &variance
+		lines[i].VarianceAmount = variance * costPrices[i]
+	}
+	return lines, nil
+}
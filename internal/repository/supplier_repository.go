@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/internal/model"
+
+	"github.com/lib/pq"
+)
+
+var ErrInvalidSupplier = errors.New("invalid supplier")
+
+type SupplierRepository interface {
+	Create(s model.Supplier) (*model.Supplier, error)
+	GetAll(search string) ([]model.Supplier, error)
+	GetByID(id int) (*model.Supplier, error)
+	Update(id int, s model.Supplier) (*model.Supplier, error)
+	Delete(id int) error
+}
+
+type postgresSupplierRepository struct {
+	db *sql.DB
+}
+
+func NewSupplierRepository(db *sql.DB) SupplierRepository {
+	return &postgresSupplierRepository{db: db}
+}
+
+const supplierColumns = `id, name, COALESCE(phone, ''), COALESCE(email, ''), COALESCE(address, ''), created_at`
+
+func scanSupplier(row rowScanner) (*model.Supplier, error) {
+	var s model.Supplier
+	if err := row.Scan(&s.ID, &s.Name, &s.Phone, &s.Email, &s.Address, &s.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *postgresSupplierRepository) Create(s model.Supplier) (*model.Supplier, error) {
+	return scanSupplier(r.db.QueryRow(`
+		INSERT INTO suppliers (name, phone, email, address)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), NULLIF($4, ''))
+		RETURNING `+supplierColumns, s.Name, s.Phone, s.Email, s.Address))
+}
+
+// GetAll lists suppliers by name, optionally only those whose name contains
+// search.
+func (r *postgresSupplierRepository) GetAll(search string) ([]model.Supplier, error) {
+	query := "SELECT " + supplierColumns + " FROM suppliers"
+	args := []interface{}{}
+	if search != "" {
+		query += " WHERE name ILIKE $1"
+		args = append(args, "%"+search+"%")
+	}
+	query += " ORDER BY name, id"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suppliers := []model.Supplier{}
+	for rows.Next() {
+		s, err := scanSupplier(rows)
+		if err != nil {
+			return nil, err
+		}
+		suppliers = append(suppliers, *s)
+	}
+	return suppliers, rows.Err()
+}
+
+func (r *postgresSupplierRepository) GetByID(id int) (*model.Supplier, error) {
+	return scanSupplier(r.db.QueryRow("SELECT "+supplierColumns+" FROM suppliers WHERE id = $1", id))
+}
+
+func (r *postgresSupplierRepository) Update(id int, s model.Supplier) (*model.Supplier, error) {
+	return scanSupplier(r.db.QueryRow(`
+		UPDATE suppliers
+		SET name = $1, phone = NULLIF($2, ''), email = NULLIF($3, ''), address = NULLIF($4, '')
+		WHERE id = $5
+		RETURNING `+supplierColumns, s.Name, s.Phone, s.Email, s.Address, id))
+}
+
+// Delete removes a supplier. Suppliers with purchase orders are kept so the
+// orders stay readable.
+func (r *postgresSupplierRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM suppliers WHERE id = $1`, id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return fmt.Errorf("%w: supplier %d has purchase orders", ErrInvalidSupplier, id)
+	}
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"database/sql"
+	"kasir-api/internal/model"
+)
+
+type TaxRateRepository interface {
+	Create(rate model.TaxRate) (model.TaxRate, error)
+	GetAll() ([]model.TaxRate, error)
+	GetByID(id int) (model.TaxRate, error)
+	Update(id int, rate model.TaxRate) (model.TaxRate, error)
+	Delete(id int) error
+}
+
+type postgresTaxRateRepository struct {
+	db *sql.DB
+}
+
+func NewTaxRateRepository(db *sql.DB) TaxRateRepository {
+	return &postgresTaxRateRepository{db: db}
+}
+
+func (r *postgresTaxRateRepository) Create(rate model.TaxRate) (model.TaxRate, error) {
+	query := `INSERT INTO tax_rates (name, rate_bp, inclusive) VALUES ($1, $2, $3) RETURNING id`
+	err := r.db.QueryRow(query, rate.Name, rate.Rate, rate.Inclusive).Scan(&rate.ID)
+	if err != nil {
+		return model.TaxRate{}, err
+	}
+	return rate, nil
+}
+
+func (r *postgresTaxRateRepository) GetAll() ([]model.TaxRate, error) {
+	rows, err := r.db.Query(`SELECT id, name, rate_bp, inclusive FROM tax_rates ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := []model.TaxRate{}
+	for rows.Next() {
+		var t model.TaxRate
+		if err := rows.Scan(&t.ID, &t.Name, &t.Rate, &t.Inclusive); err != nil {
+			return nil, err
+		}
+		rates = append(rates, t)
+	}
+	return rates, rows.Err()
+}
+
+func (r *postgresTaxRateRepository) GetByID(id int) (model.TaxRate, error) {
+	var t model.TaxRate
+	err := r.db.QueryRow(`SELECT id, name, rate_bp, inclusive FROM tax_rates WHERE id = $1`, id).Scan(&t.ID, &t.Name, &t.Rate, &t.Inclusive)
+	if err != nil {
+		return model.TaxRate{}, err
+	}
+	return t, nil
+}
+
+func (r *postgresTaxRateRepository) Update(id int, rate model.TaxRate) (model.TaxRate, error) {
+	query := `UPDATE tax_rates SET name = $1, rate_bp = $2, inclusive = $3 WHERE id = $4 RETURNING id, name, rate_bp, inclusive`
+	var updated model.TaxRate
+	err := r.db.QueryRow(query, rate.Name, rate.Rate, rate.Inclusive, id).Scan(&updated.ID, &updated.Name, &updated.Rate, &updated.Inclusive)
+	if err != nil {
+		return model.TaxRate{}, err
+	}
+	return updated, nil
+}
+
+func (r *postgresTaxRateRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM tax_rates WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
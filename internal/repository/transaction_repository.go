@@ -2,17 +2,113 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"kasir-api/internal/businessday"
+	"kasir-api/internal/invoice"
 	"kasir-api/internal/model"
+	"kasir-api/internal/payment"
+	"kasir-api/internal/pricing"
+	"math"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/lib/pq"
 )
 
+// ErrInvalidRefund is returned when a refund conflicts with what is still
+// refundable on the original transaction.
+var ErrInvalidRefund = errors.New("invalid refund")
+
+// ErrInvalidCheckout is returned when a checkout is malformed, such as its items
+// or a customer_ref that contradicts the attached customer.
+var ErrInvalidCheckout = errors.New("invalid checkout")
+
 type SalesReport struct {
-	TotalRevenue   int            `json:"total_revenue"`
-	TotalTransaksi int            `json:"total_transaksi"`
-	ProdukTerlaris ProdukTerlaris `json:"produk_terlaris"`
+	// TanggalMulai and TanggalAkhir are the business days the report covers.
+	TanggalMulai   string           `json:"tanggal_mulai"`
+	TanggalAkhir   string           `json:"tanggal_akhir"`
+	TotalRevenue   int              `json:"total_revenue"`
+	TotalRefund    int              `json:"total_refund"`
+	TotalTransaksi int              `json:"total_transaksi"`
+	ProdukTerlaris ProdukTerlaris   `json:"produk_terlaris"`
+	PerKasir       []PenjualanKasir `json:"per_kasir,omitempty"`
+	// TerlarisQty and TerlarisRevenue are the top products by quantity and by
+	// revenue, net of refunds; ProdukTerlaris is the first of TerlarisQty.
+	TerlarisQty     []ProdukTerjual `json:"terlaris_qty"`
+	TerlarisRevenue []ProdukTerjual `json:"terlaris_revenue"`
+	// PerKategoriRevenue is revenue per category, net of refunds.
+	PerKategoriRevenue []PenjualanKategori `json:"per_kategori_revenue"`
+	// RataRataBelanja (average basket) and ItemPerTransaksi describe sales
+	// as rung up; voided sales are left out and later refunds do not count.
+	RataRataBelanja  int     `json:"rata_rata_belanja"`
+	ItemPerTransaksi float64 `json:"item_per_transaksi"`
+	// PerWaktu is net revenue per hour or per day of the period, as set by
+	// Interval, with empty buckets included.
+	Interval string             `json:"interval"`
+	PerWaktu []PenjualanPeriode `json:"per_waktu"`
+	// PerMetode is the money kept per payment method: taken for sales in the
+	// period, net of change, less what refunds in the period handed back in
+	// that method, so it adds up to TotalRevenue. Sales voided within the
+	// period are left out together with their refunds.
+	PerMetode []PenjualanMetode `json:"per_metode"`
+	// TotalPajak and Pajak summarize the tax collected per rate, net of refunds,
+	// for PPN filing. DPP is the taxable base (dasar pengenaan pajak).
+	TotalPajak int              `json:"total_pajak"`
+	Pajak      []RingkasanPajak `json:"pajak"`
+	// TotalDiskon and Promosi show the promotion discounts given on sales in
+	// the period.
+	TotalDiskon int                `json:"total_diskon"`
+	Promosi     []RingkasanPromosi `json:"promosi"`
+	// PenjualanBersih is net sales before tax, after discounts and refunds.
+	// TotalHPP is the cost of those goods (harga pokok penjualan) at the cost
+	// snapshotted when they were sold, and LabaKotor the gross profit left.
+	// MarginBP is the gross margin in basis points of PenjualanBersih.
+	PenjualanBersih int `json:"penjualan_bersih"`
+	TotalHPP        int `json:"total_hpp"`
+	LabaKotor       int `json:"laba_kotor"`
+	MarginBP        int `json:"margin_bp"`
+	// PerProduk, PerKategori and PerHari break gross profit down when the
+	// report is grouped by product, category or day.
+	PerProduk   []RingkasanLaba `json:"per_produk,omitempty"`
+	PerKategori []RingkasanLaba `json:"per_kategori,omitempty"`
+	PerHari     []LabaHarian    `json:"per_hari,omitempty"`
+}
+
+// RingkasanLaba is the gross profit of one product or category. ID is zero
+// for products deleted since and for uncategorized sales.
+type RingkasanLaba struct {
+	ID              int    `json:"id,omitempty"`
+	Nama            string `json:"nama"`
+	QtyTerjual      int    `json:"qty_terjual"`
+	PenjualanBersih int    `json:"penjualan_bersih"`
+	TotalHPP        int    `json:"total_hpp"`
+	LabaKotor       int    `json:"laba_kotor"`
+	MarginBP        int    `json:"margin_bp"`
+}
+
+type LabaHarian struct {
+	Tanggal         string `json:"tanggal"`
+	PenjualanBersih int    `json:"penjualan_bersih"`
+	TotalHPP        int    `json:"total_hpp"`
+	LabaKotor       int    `json:"laba_kotor"`
+	MarginBP        int    `json:"margin_bp"`
+}
+
+type RingkasanPromosi struct {
+	PromosiID      int    `json:"promosi_id,omitempty"`
+	Nama           string `json:"nama"`
+	TotalTransaksi int    `json:"total_transaksi"`
+	TotalDiskon    int    `json:"total_diskon"`
+}
+
+type RingkasanPajak struct {
+	Nama       string `json:"nama"`
+	TarifBP    int    `json:"tarif_bp"`
+	Inklusif   bool   `json:"inklusif"`
+	DPP        int    `json:"dpp"`
+	TotalPajak int    `json:"total_pajak"`
 }
 
 type ProdukTerlaris struct {
@@ -20,206 +116,1850 @@ type ProdukTerlaris struct {
 	QtyTerjual int    `json:"qty_terjual"`
 }
 
+// ProdukTerjual is one product of a top list. ProdukID is zero for products
+// deleted since.
+type ProdukTerjual struct {
+	ProdukID     int    `json:"produk_id,omitempty"`
+	Nama         string `json:"nama"`
+	QtyTerjual   int    `json:"qty_terjual"`
+	TotalRevenue int    `json:"total_revenue"`
+}
+
+type PenjualanKategori struct {
+	KategoriID   int    `json:"kategori_id,omitempty"`
+	Nama         string `json:"nama"`
+	QtyTerjual   int    `json:"qty_terjual"`
+	TotalRevenue int    `json:"total_revenue"`
+}
+
+// PenjualanPeriode is one bucket of the time series; Waktu is the start of
+// the hour (YYYY-MM-DD HH:MM) or the business day (YYYY-MM-DD), in the
+// store's timezone.
+type PenjualanPeriode struct {
+	Waktu          string `json:"waktu"`
+	TotalRevenue   int    `json:"total_revenue"`
+	TotalTransaksi int    `json:"total_transaksi"`
+}
+
+type PenjualanMetode struct {
+	Metode         string `json:"metode"`
+	TotalRevenue   int    `json:"total_revenue"`
+	TotalRefund    int    `json:"total_refund"`
+	TotalTransaksi int    `json:"total_transaksi"`
+}
+
+type PenjualanKasir struct {
+	KasirID        int    `json:"kasir_id"`
+	Nama           string `json:"nama"`
+	TotalRevenue   int    `json:"total_revenue"`
+	TotalTransaksi int    `json:"total_transaksi"`
+}
+
 type TransactionRepository interface {
-	CreateTransaction(items []model.CheckoutItem) (*model.Transaction, error)
-	GetSalesReport(startDate, endDate string) (*SalesReport, error)
+	CreateTransaction(req model.CheckoutRequest) (*model.Transaction, error)
+	GetSalesReport(filter model.ReportFilter) (*SalesReport, error)
+	GetTransactions(filter model.TransactionFilter) ([]model.Transaction, int, error)
+	EachTransaction(filter model.TransactionFilter, fn func(model.Transaction) error) error
+	EachTransactionDetail(filter model.TransactionFilter, fn func(model.TransactionDetail) error) error
+	EachPayment(filter model.TransactionFilter, fn func(model.Payment) error) error
+	GetTransactionByID(id int) (*model.Transaction, error)
+	CreateRefund(transactionID int, refundType string, req model.RefundRequest) (*model.Refund, error)
 }
 
 type postgresTransactionRepository struct {
-	db *sql.DB
+	db       *sql.DB
+	calendar *businessday.Calendar
+	invoices *invoice.Format
+}
+
+// NewTransactionRepository numbers new sales with invoices, dated by their
+// business day.
+func NewTransactionRepository(db *sql.DB, calendar *businessday.Calendar, invoices *invoice.Format) TransactionRepository {
+	return &postgresTransactionRepository{db: db, calendar: calendar, invoices: invoices}
 }
 
-func NewTransactionRepository(db *sql.DB) TransactionRepository {
-	return &postgresTransactionRepository{db: db}
+// reportConditions restricts sales (alias t) and refunds (alias rf, joined to
+// their transaction as t) to the report filter. Both share the same arguments;
+// $1 and $2 are the instants the first business day starts and the last ends.
+func (r *postgresTransactionRepository) reportConditions(filter model.ReportFilter) (saleCond, refundCond string, args []interface{}, err error) {
+	from, to, err := r.calendar.Bounds(filter.StartDate, filter.EndDate)
+	if err != nil {
+		return "", "", nil, err
+	}
+	args = []interface{}{from, to}
+	saleCond = "t.created_at >= $1 AND t.created_at < $2"
+	refundCond = "rf.created_at >= $1 AND rf.created_at < $2"
+
+	if filter.CashierID != 0 {
+		args = append(args, filter.CashierID)
+		saleCond += fmt.Sprintf(" AND t.cashier_id = $%d", len(args))
+		refundCond += fmt.Sprintf(" AND t.cashier_id = $%d", len(args))
+	}
+	if filter.TerminalID != "" {
+		args = append(args, filter.TerminalID)
+		saleCond += fmt.Sprintf(" AND t.terminal_id = $%d", len(args))
+		refundCond += fmt.Sprintf(" AND t.terminal_id = $%d", len(args))
+	}
+
+	return saleCond, refundCond, args, nil
 }
 
-func (r *postgresTransactionRepository) GetSalesReport(startDate, endDate string) (*SalesReport, error) {
-	// Get total revenue and total transactions
-	var totalRevenue, totalTransaksi int
+func (r *postgresTransactionRepository) GetSalesReport(filter model.ReportFilter) (*SalesReport, error) {
+	saleCond, refundCond, args, err := r.reportConditions(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get gross revenue and total transactions, voided sales are not counted
+	var grossRevenue, totalTransaksi int
 	query := `
-		SELECT COALESCE(SUM(total_amount), 0), COUNT(*)
-		FROM transactions
-		WHERE DATE(created_at) BETWEEN $1 AND $2
-	`
-	err := r.db.QueryRow(query, startDate, endDate).Scan(&totalRevenue, &totalTransaksi)
+		SELECT COALESCE(SUM(t.total_amount), 0), COUNT(*) FILTER (WHERE t.status <> 'voided')
+		FROM transactions t
+		WHERE ` + saleCond
+	err = r.db.QueryRow(query, args...).Scan(&grossRevenue, &totalTransaksi)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get best-selling product
-	var produkNama string
-	var qtyTerjual int
+	// Refunds are netted out on the day they were issued
+	var totalRefund int
 	query = `
-		SELECT p.name, SUM(td.quantity) as total_qty
-		FROM transaction_details td
-		JOIN products p ON td.product_id = p.id
-		JOIN transactions t ON td.transaction_id = t.id
-		WHERE DATE(t.created_at) BETWEEN $1 AND $2
-		GROUP BY p.id, p.name
-		ORDER BY total_qty DESC
-		LIMIT 1
-	`
-	err = r.db.QueryRow(query, startDate, endDate).Scan(&produkNama, &qtyTerjual)
-	if err == sql.ErrNoRows {
-		produkNama = ""
-		qtyTerjual = 0
-	} else if err != nil {
+		SELECT COALESCE(SUM(rf.total_amount), 0)
+		FROM refunds rf
+		JOIN transactions t ON rf.transaction_id = t.id
+		WHERE ` + refundCond
+	err = r.db.QueryRow(query, args...).Scan(&totalRefund)
+	if err != nil {
 		return nil, err
 	}
 
-	return &SalesReport{
-		TotalRevenue:   totalRevenue,
+	report := &SalesReport{
+		TanggalMulai:   filter.StartDate,
+		TanggalAkhir:   filter.EndDate,
+		TotalRevenue:   grossRevenue - totalRefund,
+		TotalRefund:    totalRefund,
 		TotalTransaksi: totalTransaksi,
-		ProdukTerlaris: ProdukTerlaris{
-			Nama:       produkNama,
-			QtyTerjual: qtyTerjual,
-		},
-	}, nil
-}
+		Interval:       filter.Interval,
+	}
 
-func (r *postgresTransactionRepository) CreateTransaction(items []model.CheckoutItem) (*model.Transaction, error) {
-	tx, err := r.db.Begin()
+	// Best-selling products from the sale-time snapshot, net of refunds
+	report.TerlarisQty, err = r.getTopProducts(saleCond, refundCond, args, "SUM(s.qty)", filter.Top)
 	if err != nil {
 		return nil, err
 	}
-	defer tx.Rollback()
+	report.TerlarisRevenue, err = r.getTopProducts(saleCond, refundCond, args, "SUM(s.revenue)", filter.Top)
+	if err != nil {
+		return nil, err
+	}
+	if len(report.TerlarisQty) > 0 {
+		report.ProdukTerlaris = ProdukTerlaris{Nama: report.TerlarisQty[0].Nama, QtyTerjual: report.TerlarisQty[0].QtyTerjual}
+	}
 
-	totalAmount := 0
-	details := make([]model.TransactionDetail, 0, len(items))
+	report.PerKategoriRevenue, err = r.getRevenuePerCategory(saleCond, refundCond, args)
+	if err != nil {
+		return nil, err
+	}
 
-	if len(items) == 0 {
-		var transactionID int
-		err = tx.QueryRow("INSERT INTO transactions (total_amount) VALUES ($1) RETURNING id", totalAmount).Scan(&transactionID)
-		if err != nil {
+	var basketRevenue, basketItems int
+	query = `
+		SELECT COALESCE(SUM(t.total_amount), 0),
+			COALESCE(SUM((SELECT SUM(td.quantity) FROM transaction_details td WHERE td.transaction_id = t.id)), 0)
+		FROM transactions t
+		WHERE t.status <> 'voided' AND ` + saleCond
+	if err := r.db.QueryRow(query, args...).Scan(&basketRevenue, &basketItems); err != nil {
+		return nil, err
+	}
+	if totalTransaksi > 0 {
+		report.RataRataBelanja = basketRevenue / totalTransaksi
+		report.ItemPerTransaksi = math.Round(float64(basketItems)/float64(totalTransaksi)*100) / 100
+	}
+
+	report.PerWaktu, err = r.getSalesSeries(filter.Interval, saleCond, refundCond, args)
+	if err != nil {
+		return nil, err
+	}
+
+	report.PerMetode, err = r.getSalesPerPaymentMethod(saleCond, refundCond, args)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Pajak, err = r.getTaxSummary(saleCond, refundCond, args)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range report.Pajak {
+		report.TotalPajak += p.TotalPajak
+	}
+
+	report.Promosi, err = r.getPromotionSummary(saleCond, args)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range report.Promosi {
+		report.TotalDiskon += p.TotalDiskon
+	}
+
+	lines := r.grossProfitLines(saleCond, refundCond)
+	err = r.db.QueryRow(`SELECT COALESCE(SUM(s.sales), 0), COALESCE(SUM(s.cost), 0) FROM (`+lines+`) s`, args...).
+		Scan(&report.PenjualanBersih, &report.TotalHPP)
+	if err != nil {
+		return nil, err
+	}
+	report.LabaKotor = report.PenjualanBersih - report.TotalHPP
+	report.MarginBP = marginBP(report.LabaKotor, report.PenjualanBersih)
+
+	switch filter.GroupBy {
+	case model.ReportGroupByCashier:
+		report.PerKasir, err = r.getSalesPerCashier(saleCond, refundCond, args)
+	case model.ReportGroupByProduct:
+		report.PerProduk, err = r.getGrossProfitBy("s.product_id", "s.product_name", lines, args)
+	case model.ReportGroupByCategory:
+		report.PerKategori, err = r.getGrossProfitBy("s.category_id", "s.category_name", lines, args)
+	case model.ReportGroupByDay:
+		report.PerHari, err = r.getGrossProfitPerDay(lines, args)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// grossProfitLines is a query of every sold and refunded line in the report
+// with its net sales before tax and its cost. Refunds take both off on the
+// day of the refund, like revenue; voided sales are netted out by their void.
+func (r *postgresTransactionRepository) grossProfitLines(saleCond, refundCond string) string {
+	return `
+		SELECT td.product_id, td.product_name, td.category_id, td.category_name, ` + r.calendar.SQLDate("t.created_at") + ` AS day,
+			td.quantity AS qty, td.line_total - td.tax_amount AS sales, td.quantity * td.unit_cost AS cost
+		FROM transaction_details td
+		JOIN transactions t ON td.transaction_id = t.id
+		WHERE ` + saleCond + `
+		UNION ALL
+		SELECT td.product_id, td.product_name, td.category_id, td.category_name, ` + r.calendar.SQLDate("rf.created_at") + `,
+			-ri.quantity, -(ri.amount - ri.tax_amount), -ri.quantity * td.unit_cost
+		FROM refund_items ri
+		JOIN refunds rf ON ri.refund_id = rf.id
+		JOIN transactions t ON rf.transaction_id = t.id
+		JOIN transaction_details td ON ri.transaction_detail_id = td.id
+		WHERE ` + refundCond
+}
+
+// getGrossProfitBy groups the gross profit lines by a product or category
+// column pair, most profitable first.
+func (r *postgresTransactionRepository) getGrossProfitBy(idCol, nameCol, lines string, args []interface{}) ([]RingkasanLaba, error) {
+	query := `
+		SELECT COALESCE(` + idCol + `, 0), COALESCE(` + nameCol + `, ''), SUM(s.qty), SUM(s.sales), SUM(s.cost)
+		FROM (` + lines + `) s
+		GROUP BY ` + idCol + `, ` + nameCol + `
+		ORDER BY SUM(s.sales) - SUM(s.cost) DESC, ` + nameCol + `
+	`
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summary := []RingkasanLaba{}
+	for rows.Next() {
+		var l RingkasanLaba
+		if err := rows.Scan(&l.ID, &l.Nama, &l.QtyTerjual, &l.PenjualanBersih, &l.TotalHPP); err != nil {
 			return nil, err
 		}
+		l.LabaKotor = l.PenjualanBersih - l.TotalHPP
+		l.MarginBP = marginBP(l.LabaKotor, l.PenjualanBersih)
+		summary = append(summary, l)
+	}
+	return summary, rows.Err()
+}
+
+// getGrossProfitPerDay groups the gross profit lines by day, oldest first.
+func (r *postgresTransactionRepository) getGrossProfitPerDay(lines string, args []interface{}) ([]LabaHarian, error) {
+	query := `
+		SELECT TO_CHAR(s.day, 'YYYY-MM-DD'), SUM(s.sales), SUM(s.cost)
+		FROM (` + lines + `) s
+		GROUP BY s.day
+		ORDER BY s.day
+	`
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-		if err := tx.Commit(); err != nil {
+	perDay := []LabaHarian{}
+	for rows.Next() {
+		var l LabaHarian
+		if err := rows.Scan(&l.Tanggal, &l.PenjualanBersih, &l.TotalHPP); err != nil {
 			return nil, err
 		}
+		l.LabaKotor = l.PenjualanBersih - l.TotalHPP
+		l.MarginBP = marginBP(l.LabaKotor, l.PenjualanBersih)
+		perDay = append(perDay, l)
+	}
+	return perDay, rows.Err()
+}
 
-		return &model.Transaction{
-			ID:          transactionID,
-			TotalAmount: totalAmount,
-			Details:     details,
-		}, nil
+// marginBP is profit as a share of sales in basis points, zero without sales.
+func marginBP(profit, sales int) int {
+	if sales == 0 {
+		return 0
 	}
+	return profit * 10000 / sales
+}
 
-	type productRow struct {
-		ID    int
-		Name  string
-		Price int
-		Stock int
+// soldLines is a query of every sold and refunded line in the report with
+// its quantity and revenue, refunds counted negative on the day of the refund.
+func soldLines(saleCond, refundCond string) string {
+	return `
+		SELECT td.product_id, td.product_name, td.category_id, td.category_name, td.quantity AS qty, td.line_total AS revenue
+		FROM transaction_details td
+		JOIN transactions t ON td.transaction_id = t.id
+		WHERE ` + saleCond + `
+		UNION ALL
+		SELECT td.product_id, td.product_name, td.category_id, td.category_name, -ri.quantity, -ri.amount
+		FROM refund_items ri
+		JOIN refunds rf ON ri.refund_id = rf.id
+		JOIN transactions t ON rf.transaction_id = t.id
+		JOIN transaction_details td ON ri.transaction_detail_id = td.id
+		WHERE ` + refundCond
+}
+
+// getTopProducts returns the n products with the highest rank, which is
+// either SUM(s.qty) or SUM(s.revenue). Products refunded in full are left out.
+func (r *postgresTransactionRepository) getTopProducts(saleCond, refundCond string, args []interface{}, rank string, n int) ([]ProdukTerjual, error) {
+	query := `
+		SELECT COALESCE(s.product_id, 0), COALESCE(s.product_name, ''), SUM(s.qty), SUM(s.revenue)
+		FROM (` + soldLines(saleCond, refundCond) + `) s
+		GROUP BY s.product_id, s.product_name
+		HAVING SUM(s.qty) > 0
+		ORDER BY ` + rank + ` DESC, s.product_name
+		LIMIT ` + strconv.Itoa(n)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	qtyByID := make(map[int]int, len(items))
-	uniqueIDs := make([]int, 0, len(items))
-	for _, item := range items {
-		if _, ok := qtyByID[item.ProductID]; !ok {
-			uniqueIDs = append(uniqueIDs, item.ProductID)
+	products := []ProdukTerjual{}
+	for rows.Next() {
+		var p ProdukTerjual
+		if err := rows.Scan(&p.ProdukID, &p.Nama, &p.QtyTerjual, &p.TotalRevenue); err != nil {
+			return nil, err
 		}
-		qtyByID[item.ProductID] += item.Quantity
+		products = append(products, p)
 	}
+	return products, rows.Err()
+}
 
-	rows, err := tx.Query("SELECT id, name, price, stock FROM products WHERE id = ANY($1::int[]) FOR UPDATE", pq.Array(uniqueIDs))
+// getRevenuePerCategory groups sold lines by their category snapshot.
+func (r *postgresTransactionRepository) getRevenuePerCategory(saleCond, refundCond string, args []interface{}) ([]PenjualanKategori, error) {
+	query := `
+		SELECT COALESCE(s.category_id, 0), COALESCE(s.category_name, ''), SUM(s.qty), SUM(s.revenue)
+		FROM (` + soldLines(saleCond, refundCond) + `) s
+		GROUP BY s.category_id, s.category_name
+		ORDER BY SUM(s.revenue) DESC, s.category_name
+	`
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	products := make(map[int]productRow, len(uniqueIDs))
+	perCategory := []PenjualanKategori{}
 	for rows.Next() {
-		var p productRow
-		if err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Stock); err != nil {
+		var c PenjualanKategori
+		if err := rows.Scan(&c.KategoriID, &c.Nama, &c.QtyTerjual, &c.TotalRevenue); err != nil {
 			return nil, err
 		}
-		products[p.ID] = p
+		perCategory = append(perCategory, c)
 	}
-	if err := rows.Err(); err != nil {
+	return perCategory, rows.Err()
+}
+
+// getSalesSeries buckets net revenue and sales by hour or business day over
+// the whole report period, which runs from $1 to $2. Sales count in the
+// bucket they were rung up in, refunds in the bucket they were issued in.
+// Sales and refunds are totalled per bucket number first, so the empty
+// buckets are joined on by number rather than by time range.
+func (r *postgresTransactionRepository) getSalesSeries(interval, saleCond, refundCond string, args []interface{}) ([]PenjualanPeriode, error) {
+	seconds, format := "86400", "YYYY-MM-DD"
+	if interval == model.ReportIntervalHour {
+		seconds, format = "3600", "YYYY-MM-DD HH24:MI"
+	}
+	bucket := func(at string) string {
+		return "FLOOR(EXTRACT(EPOCH FROM " + at + " - $1::timestamptz) / " + seconds + ")::int"
+	}
+
+	query := `
+		SELECT TO_CHAR(` + r.calendar.SQLLocalTime("($1::timestamptz + b.i * INTERVAL '"+seconds+" seconds')") + `, '` + format + `'),
+			COALESCE(s.revenue, 0), COALESCE(s.trx, 0)
+		FROM generate_series(0, ` + bucket("$2::timestamptz") + ` - 1) AS b(i)
+		LEFT JOIN (
+			SELECT e.i, SUM(e.revenue) AS revenue, SUM(e.trx) AS trx
+			FROM (
+				SELECT ` + bucket("t.created_at") + ` AS i, t.total_amount AS revenue, CASE WHEN t.status <> 'voided' THEN 1 ELSE 0 END AS trx
+				FROM transactions t
+				WHERE ` + saleCond + `
+				UNION ALL
+				SELECT ` + bucket("rf.created_at") + `, -rf.total_amount, 0
+				FROM refunds rf
+				JOIN transactions t ON rf.transaction_id = t.id
+				WHERE ` + refundCond + `
+			) e
+			GROUP BY e.i
+		) s ON s.i = b.i
+		ORDER BY b.i
+	`
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	for _, item := range items {
-		if _, ok := products[item.ProductID]; !ok {
-			return nil, fmt.Errorf("product id %d not found", item.ProductID)
+	series := []PenjualanPeriode{}
+	for rows.Next() {
+		var p PenjualanPeriode
+		if err := rows.Scan(&p.Waktu, &p.TotalRevenue, &p.TotalTransaksi); err != nil {
+			return nil, err
 		}
+		series = append(series, p)
 	}
+	return series, rows.Err()
+}
 
-	for productID, qty := range qtyByID {
-		p := products[productID]
-		if p.Stock < qty {
-			return nil, fmt.Errorf("insufficient stock for product %s (available: %d, requested: %d)", p.Name, p.Stock, qty)
+// getTaxSummary groups sold lines by their tax snapshot. Refunded lines are
+// taken off on the day of the refund, like revenue.
+func (r *postgresTransactionRepository) getTaxSummary(saleCond, refundCond string, args []interface{}) ([]RingkasanPajak, error) {
+	query := `
+		SELECT s.tax_name, s.tax_rate_bp, s.tax_inclusive, SUM(s.base), SUM(s.tax)
+		FROM (
+			SELECT COALESCE(td.tax_name, '') AS tax_name, td.tax_rate_bp, td.tax_inclusive,
+				td.line_total - td.tax_amount AS base, td.tax_amount AS tax
+			FROM transaction_details td
+			JOIN transactions t ON td.transaction_id = t.id
+			WHERE ` + saleCond + `
+			UNION ALL
+			SELECT COALESCE(td.tax_name, ''), td.tax_rate_bp, td.tax_inclusive,
+				-(ri.amount - ri.tax_amount), -ri.tax_amount
+			FROM refund_items ri
+			JOIN refunds rf ON ri.refund_id = rf.id
+			JOIN transactions t ON rf.transaction_id = t.id
+			JOIN transaction_details td ON ri.transaction_detail_id = td.id
+			WHERE ` + refundCond + `
+		) s
+		GROUP BY s.tax_name, s.tax_rate_bp, s.tax_inclusive
+		ORDER BY s.tax_rate_bp DESC, s.tax_name
+	`
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summary := []RingkasanPajak{}
+	for rows.Next() {
+		var p RingkasanPajak
+		if err := rows.Scan(&p.Nama, &p.TarifBP, &p.Inklusif, &p.DPP, &p.TotalPajak); err != nil {
+			return nil, err
 		}
+		summary = append(summary, p)
 	}
+	return summary, rows.Err()
+}
 
-	updateArgs := make([]interface{}, 0, len(uniqueIDs)*2)
-	var updateQuery strings.Builder
-	updateQuery.WriteString("UPDATE products SET stock = stock - v.qty FROM (VALUES ")
-	argPos := 1
-	for i, productID := range uniqueIDs {
-		if i > 0 {
-			updateQuery.WriteString(",")
+// getPromotionSummary totals the discount each promotion gave.
+func (r *postgresTransactionRepository) getPromotionSummary(saleCond string, args []interface{}) ([]RingkasanPromosi, error) {
+	query := `
+		SELECT COALESCE(tp.promotion_id, 0), tp.promotion_name, COUNT(DISTINCT t.id), SUM(tp.amount)
+		FROM transaction_promotions tp
+		JOIN transactions t ON tp.transaction_id = t.id
+		WHERE ` + saleCond + `
+		GROUP BY tp.promotion_id, tp.promotion_name
+		ORDER BY SUM(tp.amount) DESC
+	`
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summary := []RingkasanPromosi{}
+	for rows.Next() {
+		var p RingkasanPromosi
+		if err := rows.Scan(&p.PromosiID, &p.Nama, &p.TotalTransaksi, &p.TotalDiskon); err != nil {
+			return nil, err
 		}
-		updateQuery.WriteString(fmt.Sprintf("($%d::int, $%d::int)", argPos, argPos+1))
-		updateArgs = append(updateArgs, productID, qtyByID[productID])
-		argPos += 2
+		summary = append(summary, p)
 	}
-	updateQuery.WriteString(") AS v(id, qty) WHERE products.id = v.id")
+	return summary, rows.Err()
+}
 
-	_, err = tx.Exec(updateQuery.String(), updateArgs...)
+// getSalesPerPaymentMethod nets what was collected with each payment method
+// against what refunds handed back in it. A sale voided within the period
+// never kept any money, so neither it nor its refunds show; one voided later
+// stays in, as its void counts in the later period.
+func (r *postgresTransactionRepository) getSalesPerPaymentMethod(saleCond, refundCond string, args []interface{}) ([]PenjualanMetode, error) {
+	query := `
+		WITH voided AS (
+			SELECT rf.transaction_id
+			FROM refunds rf
+			JOIN transactions t ON rf.transaction_id = t.id
+			WHERE rf.type = 'void' AND ` + saleCond + ` AND ` + refundCond + `
+		), taken AS (
+			SELECT p.method, SUM(p.amount - p.change_amount) AS amount, COUNT(DISTINCT t.id) AS trx
+			FROM transaction_payments p
+			JOIN transactions t ON p.transaction_id = t.id
+			WHERE ` + saleCond + ` AND t.id NOT IN (SELECT transaction_id FROM voided)
+			GROUP BY p.method
+		), refunded AS (
+			SELECT rp.method, SUM(rp.amount) AS amount
+			FROM refund_payments rp
+			JOIN refunds rf ON rp.refund_id = rf.id
+			JOIN transactions t ON rf.transaction_id = t.id
+			WHERE ` + refundCond + ` AND rf.transaction_id NOT IN (SELECT transaction_id FROM voided)
+			GROUP BY rp.method
+		)
+		SELECT COALESCE(tk.method, rd.method), COALESCE(tk.amount, 0) - COALESCE(rd.amount, 0),
+			COALESCE(rd.amount, 0), COALESCE(tk.trx, 0)
+		FROM taken tk
+		FULL JOIN refunded rd ON rd.method = tk.method
+		ORDER BY 2 DESC, 1
+	`
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	for _, item := range items {
-		p := products[item.ProductID]
-		subtotal := p.Price * item.Quantity
-		totalAmount += subtotal
-		details = append(details, model.TransactionDetail{
-			ProductID:   item.ProductID,
-			ProductName: p.Name,
-			Quantity:    item.Quantity,
-			Subtotal:    subtotal,
-		})
+	perMethod := []PenjualanMetode{}
+	for rows.Next() {
+		var m PenjualanMetode
+		if err := rows.Scan(&m.Metode, &m.TotalRevenue, &m.TotalRefund, &m.TotalTransaksi); err != nil {
+			return nil, err
+		}
+		perMethod = append(perMethod, m)
 	}
+	return perMethod, rows.Err()
+}
 
-	var transactionID int
-	err = tx.QueryRow("INSERT INTO transactions (total_amount) VALUES ($1) RETURNING id", totalAmount).Scan(&transactionID)
+// getSalesPerCashier breaks net revenue down by the cashier who rang up the
+// sale; refunds count against the original cashier.
+func (r *postgresTransactionRepository) getSalesPerCashier(saleCond, refundCond string, args []interface{}) ([]PenjualanKasir, error) {
+	query := `
+		SELECT COALESCE(s.cashier_id, 0), COALESCE(NULLIF(u.name, ''), u.username, ''),
+			SUM(s.revenue), SUM(s.trx)
+		FROM (
+			SELECT t.cashier_id, t.total_amount AS revenue, CASE WHEN t.status <> 'voided' THEN 1 ELSE 0 END AS trx
+			FROM transactions t
+			WHERE ` + saleCond + `
+			UNION ALL
+			SELECT t.cashier_id, -rf.total_amount AS revenue, 0 AS trx
+			FROM refunds rf
+			JOIN transactions t ON rf.transaction_id = t.id
+			WHERE ` + refundCond + `
+		) s
+		LEFT JOIN users u ON s.cashier_id = u.id
+		GROUP BY s.cashier_id, u.name, u.username
+		ORDER BY SUM(s.revenue) DESC
+	`
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	if len(details) > 0 {
-		insertArgs := make([]interface{}, 0, len(details)*4)
-		var insertQuery strings.Builder
-		insertQuery.WriteString("INSERT INTO transaction_details (transaction_id, product_id, quantity, subtotal) VALUES ")
-		argPos = 1
-		for i := range details {
-			if i > 0 {
-				insertQuery.WriteString(",")
-			}
-			insertQuery.WriteString(fmt.Sprintf("($%d::int, $%d::int, $%d::int, $%d::int)", argPos, argPos+1, argPos+2, argPos+3))
-			insertArgs = append(insertArgs, transactionID, details[i].ProductID, details[i].Quantity, details[i].Subtotal)
-			argPos += 4
+	perCashier := []PenjualanKasir{}
+	for rows.Next() {
+		var p PenjualanKasir
+		if err := rows.Scan(&p.KasirID, &p.Nama, &p.TotalRevenue, &p.TotalTransaksi); err != nil {
+			return nil, err
 		}
+		perCashier = append(perCashier, p)
+	}
+	return perCashier, rows.Err()
+}
+
+func (r *postgresTransactionRepository) GetTransactions(filter model.TransactionFilter) ([]model.Transaction, int, error) {
+	where, args, err := r.transactionConditions(filter)
+	if err != nil {
+		return nil, 0, err
+	}
 
-		_, err = tx.Exec(insertQuery.String(), insertArgs...)
+	var total int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM transactions t"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT " + transactionColumns + " FROM transactions t LEFT JOIN users u ON t.cashier_id = u.id" + where +
+		fmt.Sprintf(" ORDER BY t.created_at DESC, t.id DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	transactions := make([]model.Transaction, 0, filter.Limit)
+	ids := make([]int, 0, filter.Limit)
+	for rows.Next() {
+		t, err := scanTransaction(rows)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
+		t.Details = []model.TransactionDetail{}
+		t.Payments = []model.Payment{}
+		transactions = append(transactions, t)
+		ids = append(ids, t.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
 	}
 
-	for i := range details {
-		details[i].TransactionID = transactionID
+	if len(ids) == 0 {
+		return transactions, total, nil
 	}
 
-	if err := tx.Commit(); err != nil {
-		return nil, err
+	details, err := r.getDetails(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	payments, err := r.getPayments(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	promotions, err := r.getPromotions(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range transactions {
+		transactions[i].Details = append(transactions[i].Details, details[transactions[i].ID]...)
+		transactions[i].Payments = append(transactions[i].Payments, payments[transactions[i].ID]...)
+		transactions[i].Promotions = promotions[transactions[i].ID]
 	}
 
-	return &model.Transaction{
-		ID:          transactionID,
-		TotalAmount: totalAmount,
-		Details:     details,
-	}, nil
+	return transactions, total, nil
+}
+
+// EachTransaction calls fn for every transaction matching filter, newest
+// first, as it is read from the database; Page and Limit are ignored. The
+// transactions come without details or payments, CreatedAt in the store's
+// timezone.
+func (r *postgresTransactionRepository) EachTransaction(filter model.TransactionFilter, fn func(model.Transaction) error) error {
+	where, args, err := r.transactionConditions(filter)
+	if err != nil {
+		return err
+	}
+	rows, err := r.db.Query("SELECT "+transactionColumns+" FROM transactions t LEFT JOIN users u ON t.cashier_id = u.id"+where+
+		" ORDER BY t.created_at DESC, t.id DESC", args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			return err
+		}
+		t.CreatedAt = t.CreatedAt.In(r.calendar.Location())
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// EachTransactionDetail calls fn for every detail row of the transactions
+// matching filter, in the order of EachTransaction.
+func (r *postgresTransactionRepository) EachTransactionDetail(filter model.TransactionFilter, fn func(model.TransactionDetail) error) error {
+	where, args, err := r.transactionConditions(filter)
+	if err != nil {
+		return err
+	}
+	rows, err := r.db.Query("SELECT "+detailColumns+" FROM transaction_details td JOIN transactions t ON td.transaction_id = t.id"+where+
+		" ORDER BY t.created_at DESC, t.id DESC, td.id", args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		d, err := scanDetail(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(d); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// EachPayment calls fn for every payment of the transactions matching
+// filter, in the order of EachTransaction.
+func (r *postgresTransactionRepository) EachPayment(filter model.TransactionFilter, fn func(model.Payment) error) error {
+	where, args, err := r.transactionConditions(filter)
+	if err != nil {
+		return err
+	}
+	rows, err := r.db.Query("SELECT "+paymentColumns+" FROM transaction_payments tp JOIN transactions t ON tp.transaction_id = t.id"+where+
+		" ORDER BY t.created_at DESC, t.id DESC, tp.id", args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p model.Payment
+		if err := rows.Scan(&p.ID, &p.TransactionID, &p.Method, &p.Amount, &p.ChangeAmount, &p.Reference); err != nil {
+			return err
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// transactionConditions turns filter into a WHERE clause over transactions
+// aliased as t, ignoring Page and Limit.
+func (r *postgresTransactionRepository) transactionConditions(filter model.TransactionFilter) (string, []interface{}, error) {
+	conditions := []string{}
+	args := []interface{}{}
+	addCondition := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.StartDate != "" {
+		from, err := r.calendar.Start(filter.StartDate)
+		if err != nil {
+			return "", nil, err
+		}
+		addCondition("t.created_at >= $%d", from)
+	}
+	if filter.EndDate != "" {
+		to, err := r.calendar.End(filter.EndDate)
+		if err != nil {
+			return "", nil, err
+		}
+		addCondition("t.created_at < $%d", to)
+	}
+	if filter.MinAmount != nil {
+		addCondition("t.total_amount >= $%d", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		addCondition("t.total_amount <= $%d", *filter.MaxAmount)
+	}
+	if filter.ProductID != 0 {
+		addCondition("EXISTS (SELECT 1 FROM transaction_details td WHERE td.transaction_id = t.id AND td.product_id = $%d)", filter.ProductID)
+	}
+	if filter.CashierID != 0 {
+		addCondition("t.cashier_id = $%d", filter.CashierID)
+	}
+	if filter.TerminalID != "" {
+		addCondition("t.terminal_id = $%d", filter.TerminalID)
+	}
+	if filter.ShiftID != 0 {
+		addCondition("t.shift_id = $%d", filter.ShiftID)
+	}
+	if filter.CustomerID != 0 {
+		addCondition("t.customer_id = $%d", filter.CustomerID)
+	}
+	if filter.Invoice != "" {
+		addCondition("t.invoice_number ILIKE $%d", "%"+filter.Invoice+"%")
+	}
+	if filter.StoreCode != "" {
+		addCondition("t.store_code = $%d", filter.StoreCode)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return where, args, nil
+}
+
+func (r *postgresTransactionRepository) GetTransactionByID(id int) (*model.Transaction, error) {
+	row := r.db.QueryRow("SELECT "+transactionColumns+" FROM transactions t LEFT JOIN users u ON t.cashier_id = u.id WHERE t.id = $1", id)
+	t, err := scanTransaction(row)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := r.getDetails([]int{id})
+	if err != nil {
+		return nil, err
+	}
+	t.Details = details[id]
+	if t.Details == nil {
+		t.Details = []model.TransactionDetail{}
+	}
+
+	payments, err := r.getPayments([]int{id})
+	if err != nil {
+		return nil, err
+	}
+	t.Payments = payments[id]
+	if t.Payments == nil {
+		t.Payments = []model.Payment{}
+	}
+
+	promotions, err := r.getPromotions([]int{id})
+	if err != nil {
+		return nil, err
+	}
+	t.Promotions = promotions[id]
+
+	t.Refunds, err = r.getRefunds(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// transactionColumns is the select list read by scanTransaction; it expects
+// transactions aliased as t and users (the cashier) left-joined as u.
+const transactionColumns = `t.id, COALESCE(t.invoice_number, ''), COALESCE(t.store_code, ''), t.subtotal_amount, t.discount_amount, t.tax_amount, t.total_amount, t.paid_amount, t.change_amount, t.status, t.cashier_id,
+	COALESCE(NULLIF(u.name, ''), u.username, ''), COALESCE(t.terminal_id, ''), t.shift_id, COALESCE(t.voucher_code, ''), t.voucher_discount,
+	t.customer_id, t.points_earned, t.points_redeemed, t.created_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTransaction(row rowScanner) (model.Transaction, error) {
+	var t model.Transaction
+	var cashierID, shiftID, customerID sql.NullInt64
+	err := row.Scan(&t.ID, &t.InvoiceNumber, &t.StoreCode, &t.SubtotalAmount, &t.DiscountAmount, &t.TaxAmount, &t.TotalAmount, &t.PaidAmount, &t.ChangeAmount, &t.Status, &cashierID, &t.CashierName, &t.TerminalID, &shiftID, &t.VoucherCode, &t.VoucherDiscount,
+		&customerID, &t.PointsEarned, &t.PointsRedeemed, &t.CreatedAt)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	t.CashierID = int(cashierID.Int64)
+	t.ShiftID = int(shiftID.Int64)
+	t.CustomerID = int(customerID.Int64)
+	return t, nil
+}
+
+// detailColumns is the select list read by scanDetail; it expects
+// transaction_details aliased as td.
+const detailColumns = `td.id, td.transaction_id, td.product_id, COALESCE(td.product_name, ''), COALESCE(td.unit_price, 0), td.unit_cost,
+	td.category_id, td.category_name, td.quantity, td.subtotal, td.discount_amount,
+	COALESCE(td.tax_name, ''), td.tax_rate_bp, td.tax_inclusive, td.tax_amount, td.line_total,
+	COALESCE((SELECT SUM(ri.quantity) FROM refund_items ri WHERE ri.transaction_detail_id = td.id), 0)`
+
+func scanDetail(row rowScanner) (model.TransactionDetail, error) {
+	var d model.TransactionDetail
+	var productID, categoryID sql.NullInt64
+	var categoryName sql.NullString
+	if err := row.Scan(&d.ID, &d.TransactionID, &productID, &d.ProductName, &d.UnitPrice, &d.UnitCost,
+		&categoryID, &categoryName, &d.Quantity, &d.Subtotal, &d.DiscountAmount,
+		&d.TaxName, &d.TaxRate, &d.TaxInclusive, &d.TaxAmount, &d.LineTotal, &d.RefundedQuantity); err != nil {
+		return model.TransactionDetail{}, err
+	}
+	d.ProductID = int(productID.Int64)
+	d.CategoryID = int(categoryID.Int64)
+	d.CategoryName = categoryName.String
+	return d, nil
+}
+
+// getDetails loads the detail rows of the given transactions, keyed by transaction ID.
+func (r *postgresTransactionRepository) getDetails(transactionIDs []int) (map[int][]model.TransactionDetail, error) {
+	rows, err := r.db.Query("SELECT "+detailColumns+" FROM transaction_details td WHERE td.transaction_id = ANY($1::int[]) ORDER BY td.transaction_id, td.id",
+		pq.Array(transactionIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	details := make(map[int][]model.TransactionDetail, len(transactionIDs))
+	for rows.Next() {
+		d, err := scanDetail(rows)
+		if err != nil {
+			return nil, err
+		}
+		details[d.TransactionID] = append(details[d.TransactionID], d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return details, nil
+}
+
+// paymentColumns is the select list of a payment; it expects
+// transaction_payments aliased as tp.
+const paymentColumns = `tp.id, tp.transaction_id, tp.method, tp.amount, tp.change_amount, COALESCE(tp.reference, '')`
+
+// getPayments loads the payments of the given transactions, keyed by transaction ID.
+func (r *postgresTransactionRepository) getPayments(transactionIDs []int) (map[int][]model.Payment, error) {
+	rows, err := r.db.Query(`
+		SELECT `+paymentColumns+`
+		FROM transaction_payments tp
+		WHERE tp.transaction_id = ANY($1::int[])
+		ORDER BY tp.transaction_id, tp.id
+	`, pq.Array(transactionIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := make(map[int][]model.Payment, len(transactionIDs))
+	for rows.Next() {
+		var p model.Payment
+		if err := rows.Scan(&p.ID, &p.TransactionID, &p.Method, &p.Amount, &p.ChangeAmount, &p.Reference); err != nil {
+			return nil, err
+		}
+		payments[p.TransactionID] = append(payments[p.TransactionID], p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+func (r *postgresTransactionRepository) CreateTransaction(req model.CheckoutRequest) (*model.Transaction, error) {
+	items := req.Items
+	cashierID := sql.NullInt64{Int64: int64(req.CashierID), Valid: req.CashierID != 0}
+	terminalID := sql.NullString{String: req.TerminalID, Valid: req.TerminalID != ""}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := ensureDayOpen(tx, r.calendar); err != nil {
+		return nil, err
+	}
+
+	totalAmount := 0
+	details := make([]model.TransactionDetail, 0, len(items))
+
+	type productRow struct {
+		ID           int
+		Name         string
+		Price        int
+		CostPrice    int
+		Stock        int
+		CategoryID   sql.NullInt64
+		CategoryName sql.NullString
+		TaxName      sql.NullString
+		TaxRate      sql.NullInt64
+		TaxInclusive sql.NullBool
+	}
+
+	qtyByID := make(map[int]int, len(items))
+	uniqueIDs := make([]int, 0, len(items))
+	for _, item := range items {
+		if _, ok := qtyByID[item.ProductID]; !ok {
+			uniqueIDs = append(uniqueIDs, item.ProductID)
+		}
+		qtyByID[item.ProductID] += item.Quantity
+	}
+
+	// The product's own tax rate wins over its category's
+	rows, err := tx.Query(`
+		SELECT p.id, p.name, p.price, p.cost_price, p.stock, p.category_id, c.name, tr.name, tr.rate_bp, tr.inclusive
+		FROM products p
+		LEFT JOIN categories c ON p.category_id = c.id
+		LEFT JOIN tax_rates tr ON tr.id = COALESCE(p.tax_rate_id, c.tax_rate_id)
+		WHERE p.id = ANY($1::int[])
+		FOR UPDATE OF p
+	`, pq.Array(uniqueIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := make(map[int]productRow, len(uniqueIDs))
+	for rows.Next() {
+		var p productRow
+		if err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.CostPrice, &p.Stock, &p.CategoryID, &p.CategoryName, &p.TaxName, &p.TaxRate, &p.TaxInclusive); err != nil {
+			return nil, err
+		}
+		products[p.ID] = p
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		if _, ok := products[item.ProductID]; !ok {
+			return nil, fmt.Errorf("product id %d not found", item.ProductID)
+		}
+	}
+
+	for productID, qty := range qtyByID {
+		p := products[productID]
+		if p.Stock < qty {
+			return nil, fmt.Errorf("insufficient stock for product %s (available: %d, requested: %d)", p.Name, p.Stock, qty)
+		}
+	}
+
+	updateArgs := make([]interface{}, 0, len(uniqueIDs)*2)
+	var updateQuery strings.Builder
+	updateQuery.WriteString("UPDATE products SET stock = stock - v.qty FROM (VALUES ")
+	argPos := 1
+	for i, productID := range uniqueIDs {
+		if i > 0 {
+			updateQuery.WriteString(",")
+		}
+		updateQuery.WriteString(fmt.Sprintf("($%d::int, $%d::int)", argPos, argPos+1))
+		updateArgs = append(updateArgs, productID, qtyByID[productID])
+		argPos += 2
+	}
+	updateQuery.WriteString(") AS v(id, qty) WHERE products.id = v.id RETURNING products.id, products.stock")
+
+	stockRows, err := tx.Query(updateQuery.String(), updateArgs...)
+	if err != nil {
+		return nil, err
+	}
+	stockAfter, err := scanStockLevels(stockRows)
+	if err != nil {
+		return nil, err
+	}
+
+	// The customer row is locked for the points it may spend; their member
+	// number is always the reference for per-customer voucher limits, so a
+	// made-up reference cannot reset a known customer's count
+	var customer *model.Customer
+	if req.CustomerID != 0 {
+		customer, err = lockCustomer(tx, req.CustomerID)
+		if err != nil {
+			return nil, err
+		}
+		if req.CustomerRef != "" && req.CustomerRef != customer.MemberNumber {
+			return nil, fmt.Errorf("%w: customer_ref %q is not the member number of customer %d", ErrInvalidCheckout, req.CustomerRef, req.CustomerID)
+		}
+		req.CustomerRef = customer.MemberNumber
+	}
+
+	// Promotions are worked out on shelf prices; tax is then charged on what is
+	// left to pay for each line
+	promos, err := loadActivePromotions(tx)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]pricing.Line, len(items))
+	for i, item := range items {
+		p := products[item.ProductID]
+		lines[i] = pricing.Line{ProductID: p.ID, CategoryID: int(p.CategoryID.Int64), UnitPrice: p.Price, Quantity: item.Quantity}
+	}
+	discounts := pricing.ApplyPromotions(lines, promos)
+	discountByLine := make([]int, len(items))
+	for _, d := range discounts {
+		discountByLine[d.Line] += d.Amount
+	}
+
+	// The voucher row stays locked until commit, so its usage limits hold
+	// however many checkouts try the same code at once
+	var voucher model.Voucher
+	voucherDiscount := 0
+	if req.VoucherCode != "" {
+		voucher, err = lockVoucher(tx, req.VoucherCode, req.CustomerRef)
+		if err != nil {
+			return nil, err
+		}
+		amounts := pricing.ApplyVoucher(lines, discountByLine, voucher)
+		if amounts == nil {
+			return nil, fmt.Errorf("%w: code %s needs a minimum spend of %d", ErrInvalidVoucher, voucher.Code, voucher.MinSpend)
+		}
+		for i, amount := range amounts {
+			discountByLine[i] += amount
+			voucherDiscount += amount
+		}
+	}
+
+	subtotalAmount, discountAmount, taxAmount := 0, 0, 0
+	for i, item := range items {
+		p := products[item.ProductID]
+		subtotal := p.Price * item.Quantity
+		tax, lineTotal := pricing.ApplyTax(subtotal-discountByLine[i], pricing.Tax{Rate: int(p.TaxRate.Int64), Inclusive: p.TaxInclusive.Bool})
+		subtotalAmount += subtotal
+		discountAmount += discountByLine[i]
+		taxAmount += tax
+		totalAmount += lineTotal
+		details = append(details, model.TransactionDetail{
+			ProductID:      item.ProductID,
+			ProductName:    p.Name,
+			UnitPrice:      p.Price,
+			UnitCost:       p.CostPrice,
+			CategoryID:     int(p.CategoryID.Int64),
+			CategoryName:   p.CategoryName.String,
+			Quantity:       item.Quantity,
+			Subtotal:       subtotal,
+			DiscountAmount: discountByLine[i],
+			TaxName:        p.TaxName.String,
+			TaxRate:        int(p.TaxRate.Int64),
+			TaxInclusive:   p.TaxInclusive.Bool,
+			TaxAmount:      tax,
+			LineTotal:      lineTotal,
+		})
+	}
+
+	payments, change, err := payment.Settle(totalAmount, req.Payments)
+	if err != nil {
+		return nil, err
+	}
+	paidAmount := totalAmount + change
+
+	pointsRedeemed := 0
+	for _, p := range payments {
+		if p.Method == model.PaymentMethodPoints {
+			pointsRedeemed += p.Amount
+		}
+	}
+	pointsEarned := 0
+	customerID := sql.NullInt64{}
+	if customer != nil {
+		if pointsRedeemed > customer.Points {
+			return nil, fmt.Errorf("%w: customer has %d points, %d requested", payment.ErrInvalidPayment, customer.Points, pointsRedeemed)
+		}
+		rules, err := loadActiveLoyaltyRules(tx)
+		if err != nil {
+			return nil, err
+		}
+		lineTotals := make([]int, len(details))
+		for i := range details {
+			lineTotals[i] = details[i].LineTotal
+		}
+		pointsEarned = pricing.EarnedPoints(lines, lineTotals, pointsRedeemed, rules)
+		customerID = sql.NullInt64{Int64: int64(customer.ID), Valid: true}
+	} else if pointsRedeemed > 0 {
+		return nil, fmt.Errorf("%w: paying with points needs a customer", payment.ErrInvalidPayment)
+	}
+
+	shiftID, err := openShiftID(tx, req.TerminalID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Taken last, since the sequence row stays locked until commit
+	invoiceNumber, err := r.nextInvoiceNumber(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactionID int
+	var createdAt time.Time
+	err = tx.QueryRow(`
+		INSERT INTO transactions (subtotal_amount, discount_amount, tax_amount, total_amount, paid_amount, change_amount, cashier_id, terminal_id, shift_id,
+			voucher_code, voucher_discount, customer_id, points_earned, points_redeemed, store_code, invoice_number)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), $11, $12, $13, $14, $15, $16)
+		RETURNING id, created_at
+	`, subtotalAmount, discountAmount, taxAmount, totalAmount, paidAmount, change, cashierID, terminalID, shiftID,
+		voucher.Code, voucherDiscount, customerID, pointsEarned, pointsRedeemed, r.invoices.Store(), invoiceNumber).Scan(&transactionID, &createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(details) > 0 {
+		insertArgs := make([]interface{}, 0, len(details)*15)
+		var insertQuery strings.Builder
+		insertQuery.WriteString("INSERT INTO transaction_details (transaction_id, product_id, product_name, unit_price, unit_cost, category_id, category_name, quantity, subtotal, " +
+			"discount_amount, tax_name, tax_rate_bp, tax_inclusive, tax_amount, line_total) VALUES ")
+		argPos = 1
+		for i := range details {
+			if i > 0 {
+				insertQuery.WriteString(",")
+			}
+			insertQuery.WriteString(fmt.Sprintf("($%d::int, $%d::int, $%d::text, $%d::int, $%d::int, $%d::int, $%d::text, $%d::int, $%d::int, $%d::int, $%d::text, $%d::int, $%d::boolean, $%d::int, $%d::int)",
+				argPos, argPos+1, argPos+2, argPos+3, argPos+4, argPos+5, argPos+6, argPos+7, argPos+8, argPos+9, argPos+10, argPos+11, argPos+12, argPos+13, argPos+14))
+			p := products[details[i].ProductID]
+			insertArgs = append(insertArgs, transactionID, details[i].ProductID, details[i].ProductName, details[i].UnitPrice, details[i].UnitCost,
+				p.CategoryID, p.CategoryName, details[i].Quantity, details[i].Subtotal, details[i].DiscountAmount,
+				p.TaxName, details[i].TaxRate, details[i].TaxInclusive, details[i].TaxAmount, details[i].LineTotal)
+			argPos += 15
+		}
+		insertQuery.WriteString(" RETURNING id")
+
+		detailRows, err := tx.Query(insertQuery.String(), insertArgs...)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; detailRows.Next(); i++ {
+			if err := detailRows.Scan(&details[i].ID); err != nil {
+				detailRows.Close()
+				return nil, err
+			}
+		}
+		detailRows.Close()
+		if err := detailRows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range details {
+		details[i].TransactionID = transactionID
+	}
+
+	if err := insertPayments(tx, transactionID, payments); err != nil {
+		return nil, err
+	}
+
+	movements := make([]model.StockMovement, 0, len(uniqueIDs))
+	for _, productID := range uniqueIDs {
+		movements = append(movements, model.StockMovement{
+			ProductID:     productID,
+			Type:          model.StockMovementSale,
+			Quantity:      -qtyByID[productID],
+			StockAfter:    stockAfter[productID],
+			ReferenceType: model.StockReferenceTransaction,
+			ReferenceID:   transactionID,
+			UserID:        req.CashierID,
+		})
+	}
+	if err := recordStockMovements(tx, movements); err != nil {
+		return nil, err
+	}
+
+	applied := make([]model.AppliedPromotion, 0, len(discounts))
+	for _, d := range discounts {
+		applied = append(applied, model.AppliedPromotion{
+			PromotionID:         promos[d.Promotion].ID,
+			Name:                promos[d.Promotion].Name,
+			TransactionDetailID: details[d.Line].ID,
+			Amount:              d.Amount,
+		})
+	}
+	if err := insertAppliedPromotions(tx, transactionID, applied); err != nil {
+		return nil, err
+	}
+
+	if voucher.ID != 0 {
+		if err := insertVoucherRedemption(tx, voucher.ID, transactionID, req.CustomerRef, voucherDiscount); err != nil {
+			return nil, err
+		}
+	}
+
+	if customer != nil {
+		if err := addPoints(tx, customer.ID, transactionID, model.PointEntryRedeem, -pointsRedeemed); err != nil {
+			return nil, err
+		}
+		if err := addPoints(tx, customer.ID, transactionID, model.PointEntryEarn, pointsEarned); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &model.Transaction{
+		ID:              transactionID,
+		InvoiceNumber:   invoiceNumber,
+		StoreCode:       r.invoices.Store(),
+		SubtotalAmount:  subtotalAmount,
+		DiscountAmount:  discountAmount,
+		TaxAmount:       taxAmount,
+		TotalAmount:     totalAmount,
+		PaidAmount:      paidAmount,
+		ChangeAmount:    change,
+		Status:          model.TransactionStatusCompleted,
+		CashierID:       req.CashierID,
+		TerminalID:      req.TerminalID,
+		ShiftID:         int(shiftID.Int64),
+		CreatedAt:       createdAt,
+		Details:         details,
+		Payments:        payments,
+		Promotions:      applied,
+		VoucherCode:     voucher.Code,
+		VoucherDiscount: voucherDiscount,
+		CustomerID:      int(customerID.Int64),
+		PointsEarned:    pointsEarned,
+		PointsRedeemed:  pointsRedeemed,
+	}, nil
+}
+
+// nextInvoiceNumber takes the next number of the store's sequence for the
+// current business day. The sequence row stays locked until tx ends, so
+// concurrent sales queue for their numbers and a rolled back sale leaves no
+// gap.
+func (r *postgresTransactionRepository) nextInvoiceNumber(tx *sql.Tx) (string, error) {
+	today, _, err := resolveBusinessDate(tx, r.calendar, "")
+	if err != nil {
+		return "", err
+	}
+	date, err := time.Parse("2006-01-02", today)
+	if err != nil {
+		return "", err
+	}
+
+	var seq int
+	err = tx.QueryRow(`
+		INSERT INTO invoice_sequences (store_code, period, last_seq)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (store_code, period) DO UPDATE SET last_seq = invoice_sequences.last_seq + 1
+		RETURNING last_seq
+	`, r.invoices.Store(), r.invoices.Period(date)).Scan(&seq)
+	if err != nil {
+		return "", err
+	}
+	return r.invoices.Number(date, seq), nil
+}
+
+// insertPayments stores the settled payments of a transaction and fills in
+// their IDs.
+func insertPayments(tx *sql.Tx, transactionID int, payments []model.Payment) error {
+	args := make([]interface{}, 0, len(payments)*5)
+	var query strings.Builder
+	query.WriteString("INSERT INTO transaction_payments (transaction_id, method, amount, change_amount, reference) VALUES ")
+	argPos := 1
+	for i, p := range payments {
+		if i > 0 {
+			query.WriteString(",")
+		}
+		query.WriteString(fmt.Sprintf("($%d::int, $%d::text, $%d::int, $%d::int, NULLIF($%d::text, ''))",
+			argPos, argPos+1, argPos+2, argPos+3, argPos+4))
+		args = append(args, transactionID, p.Method, p.Amount, p.ChangeAmount, p.Reference)
+		argPos += 5
+	}
+	query.WriteString(" RETURNING id")
+
+	rows, err := tx.Query(query.String(), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for i := 0; rows.Next(); i++ {
+		if err := rows.Scan(&payments[i].ID); err != nil {
+			return err
+		}
+		payments[i].TransactionID = transactionID
+	}
+	return rows.Err()
+}
+
+// insertAppliedPromotions records which promotions discounted which lines.
+func insertAppliedPromotions(tx *sql.Tx, transactionID int, applied []model.AppliedPromotion) error {
+	if len(applied) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(applied)*5)
+	var query strings.Builder
+	query.WriteString("INSERT INTO transaction_promotions (transaction_id, transaction_detail_id, promotion_id, promotion_name, amount) VALUES ")
+	argPos := 1
+	for i, a := range applied {
+		if i > 0 {
+			query.WriteString(",")
+		}
+		query.WriteString(fmt.Sprintf("($%d::int, $%d::int, $%d::int, $%d::text, $%d::int)", argPos, argPos+1, argPos+2, argPos+3, argPos+4))
+		args = append(args, transactionID, a.TransactionDetailID, a.PromotionID, a.Name, a.Amount)
+		argPos += 5
+	}
+
+	_, err := tx.Exec(query.String(), args...)
+	return err
+}
+
+// getPromotions loads the promotions applied to the given transactions, keyed by transaction ID.
+func (r *postgresTransactionRepository) getPromotions(transactionIDs []int) (map[int][]model.AppliedPromotion, error) {
+	rows, err := r.db.Query(`
+		SELECT transaction_id, promotion_id, promotion_name, transaction_detail_id, amount
+		FROM transaction_promotions
+		WHERE transaction_id = ANY($1::int[])
+		ORDER BY transaction_id, id
+	`, pq.Array(transactionIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int][]model.AppliedPromotion, len(transactionIDs))
+	for rows.Next() {
+		var transactionID int
+		var promotionID sql.NullInt64
+		var a model.AppliedPromotion
+		if err := rows.Scan(&transactionID, &promotionID, &a.Name, &a.TransactionDetailID, &a.Amount); err != nil {
+			return nil, err
+		}
+		a.PromotionID = int(promotionID.Int64)
+		applied[transactionID] = append(applied[transactionID], a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return applied, nil
+}
+
+// getRefunds loads the refunds issued against a transaction, oldest first.
+func (r *postgresTransactionRepository) getRefunds(transactionID int) ([]model.Refund, error) {
+	rows, err := r.db.Query(`
+		SELECT id, transaction_id, type, COALESCE(reason, ''), total_amount, shift_id, created_at
+		FROM refunds
+		WHERE transaction_id = $1
+		ORDER BY id
+	`, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var refunds []model.Refund
+	indexByID := make(map[int]int)
+	for rows.Next() {
+		var rf model.Refund
+		var shiftID sql.NullInt64
+		if err := rows.Scan(&rf.ID, &rf.TransactionID, &rf.Type, &rf.Reason, &rf.TotalAmount, &shiftID, &rf.CreatedAt); err != nil {
+			return nil, err
+		}
+		rf.ShiftID = int(shiftID.Int64)
+		rf.Items = []model.RefundItem{}
+		rf.Payments = []model.RefundPayment{}
+		indexByID[rf.ID] = len(refunds)
+		refunds = append(refunds, rf)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(refunds) == 0 {
+		return nil, nil
+	}
+
+	itemRows, err := r.db.Query(`
+		SELECT ri.id, ri.refund_id, ri.transaction_detail_id, ri.product_id, COALESCE(td.product_name, ''), ri.quantity, ri.amount, ri.tax_amount
+		FROM refund_items ri
+		JOIN refunds rf ON ri.refund_id = rf.id
+		JOIN transaction_details td ON ri.transaction_detail_id = td.id
+		WHERE rf.transaction_id = $1
+		ORDER BY ri.id
+	`, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var item model.RefundItem
+		var productID sql.NullInt64
+		if err := itemRows.Scan(&item.ID, &item.RefundID, &item.TransactionDetailID, &productID, &item.ProductName, &item.Quantity, &item.Amount, &item.TaxAmount); err != nil {
+			return nil, err
+		}
+		item.ProductID = int(productID.Int64)
+		i := indexByID[item.RefundID]
+		refunds[i].Items = append(refunds[i].Items, item)
+	}
+	if err := itemRows.Err(); err != nil {
+		return nil, err
+	}
+
+	paymentRows, err := r.db.Query(`
+		SELECT rp.id, rp.refund_id, rp.method, rp.amount
+		FROM refund_payments rp
+		JOIN refunds rf ON rp.refund_id = rf.id
+		WHERE rf.transaction_id = $1
+		ORDER BY rp.id
+	`, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer paymentRows.Close()
+
+	for paymentRows.Next() {
+		var p model.RefundPayment
+		if err := paymentRows.Scan(&p.ID, &p.RefundID, &p.Method, &p.Amount); err != nil {
+			return nil, err
+		}
+		i := indexByID[p.RefundID]
+		refunds[i].Payments = append(refunds[i].Payments, p)
+	}
+	if err := paymentRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return refunds, nil
+}
+
+// heldPayments lists, per payment method of a sale, the money kept from it
+// less what earlier refunds handed back in that method.
+func heldPayments(tx *sql.Tx, transactionID int) ([]model.RefundPayment, error) {
+	rows, err := tx.Query(`
+		SELECT p.method, SUM(p.amount - p.change_amount) - COALESCE((
+			SELECT SUM(rp.amount)
+			FROM refund_payments rp
+			JOIN refunds rf ON rp.refund_id = rf.id
+			WHERE rf.transaction_id = $1 AND rp.method = p.method
+		), 0)
+		FROM transaction_payments p
+		WHERE p.transaction_id = $1
+		GROUP BY p.method
+		ORDER BY MIN(p.id)
+	`, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var held []model.RefundPayment
+	for rows.Next() {
+		var h model.RefundPayment
+		if err := rows.Scan(&h.Method, &h.Amount); err != nil {
+			return nil, err
+		}
+		held = append(held, h)
+	}
+	return held, rows.Err()
+}
+
+// insertRefundPayments stores how a refund was handed back and fills in the
+// IDs.
+func insertRefundPayments(tx *sql.Tx, refundID int, payments []model.RefundPayment) error {
+	if len(payments) == 0 {
+		return nil
+	}
+	args := make([]interface{}, 0, len(payments)*3)
+	var query strings.Builder
+	query.WriteString("INSERT INTO refund_payments (refund_id, method, amount) VALUES ")
+	for i, p := range payments {
+		if i > 0 {
+			query.WriteString(",")
+		}
+		query.WriteString(fmt.Sprintf("($%d::int, $%d::text, $%d::int)", i*3+1, i*3+2, i*3+3))
+		args = append(args, refundID, p.Method, p.Amount)
+	}
+	query.WriteString(" RETURNING id")
+
+	rows, err := tx.Query(query.String(), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for i := 0; rows.Next(); i++ {
+		if err := rows.Scan(&payments[i].ID); err != nil {
+			return err
+		}
+		payments[i].RefundID = refundID
+	}
+	return rows.Err()
+}
+
+// CreateRefund records a refund document against a transaction and puts the
+// refunded quantities back into stock, all inside one database transaction.
+// The transaction row is locked so concurrent refunds cannot exceed what was
+// originally sold.
+func (r *postgresTransactionRepository) CreateRefund(transactionID int, refundType string, req model.RefundRequest) (*model.Refund, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := ensureDayOpen(tx, r.calendar); err != nil {
+		return nil, err
+	}
+
+	var status string
+	var totalAmount, pointsEarned, pointsRedeemed int
+	var customerID sql.NullInt64
+	err = tx.QueryRow(
+		"SELECT status, total_amount, customer_id, points_earned, points_redeemed FROM transactions WHERE id = $1 FOR UPDATE", transactionID,
+	).Scan(&status, &totalAmount, &customerID, &pointsEarned, &pointsRedeemed)
+	if err != nil {
+		return nil, err
+	}
+	if status == model.TransactionStatusRefunded || status == model.TransactionStatusVoided {
+		return nil, fmt.Errorf("%w: transaction %d has already been fully refunded", ErrInvalidRefund, transactionID)
+	}
+
+	type detailRow struct {
+		ID             int
+		ProductID      sql.NullInt64
+		ProductName    string
+		Quantity       int
+		LineTotal      int
+		TaxAmount      int
+		RefundedQty    int
+		RefundedAmount int
+		RefundedTax    int
+	}
+
+	rows, err := tx.Query(`
+		SELECT td.id, td.product_id, COALESCE(td.product_name, ''), td.quantity, td.line_total, td.tax_amount,
+			COALESCE(SUM(ri.quantity), 0), COALESCE(SUM(ri.amount), 0), COALESCE(SUM(ri.tax_amount), 0)
+		FROM transaction_details td
+		LEFT JOIN refund_items ri ON ri.transaction_detail_id = td.id
+		WHERE td.transaction_id = $1
+		GROUP BY td.id
+		ORDER BY td.id
+	`, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	detailOrder := []int{}
+	detailsByID := make(map[int]detailRow)
+	for rows.Next() {
+		var d detailRow
+		if err := rows.Scan(&d.ID, &d.ProductID, &d.ProductName, &d.Quantity, &d.LineTotal, &d.TaxAmount,
+			&d.RefundedQty, &d.RefundedAmount, &d.RefundedTax); err != nil {
+			return nil, err
+		}
+		detailOrder = append(detailOrder, d.ID)
+		detailsByID[d.ID] = d
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Resolve the quantity to refund per detail line
+	qtyByDetail := make(map[int]int)
+	lineOrder := []int{}
+	if len(req.Items) == 0 {
+		for _, id := range detailOrder {
+			d := detailsByID[id]
+			if remaining := d.Quantity - d.RefundedQty; remaining > 0 {
+				qtyByDetail[id] = remaining
+				lineOrder = append(lineOrder, id)
+			}
+		}
+	} else {
+		for _, item := range req.Items {
+			if _, ok := detailsByID[item.TransactionDetailID]; !ok {
+				return nil, fmt.Errorf("%w: detail %d does not belong to transaction %d", ErrInvalidRefund, item.TransactionDetailID, transactionID)
+			}
+			if _, ok := qtyByDetail[item.TransactionDetailID]; !ok {
+				lineOrder = append(lineOrder, item.TransactionDetailID)
+			}
+			qtyByDetail[item.TransactionDetailID] += item.Quantity
+		}
+		for _, id := range lineOrder {
+			d := detailsByID[id]
+			if remaining := d.Quantity - d.RefundedQty; qtyByDetail[id] > remaining {
+				return nil, fmt.Errorf("%w: detail %d has only %d refundable item(s), requested %d", ErrInvalidRefund, id, remaining, qtyByDetail[id])
+			}
+		}
+	}
+	if len(lineOrder) == 0 {
+		return nil, fmt.Errorf("%w: nothing left to refund on transaction %d", ErrInvalidRefund, transactionID)
+	}
+
+	refund := &model.Refund{
+		TransactionID: transactionID,
+		Type:          refundType,
+		Reason:        req.Reason,
+		Items:         make([]model.RefundItem, 0, len(lineOrder)),
+	}
+	restockByProduct := make(map[int]int)
+	restockOrder := []int{}
+	for _, id := range lineOrder {
+		d := detailsByID[id]
+		qty := qtyByDetail[id]
+
+		// The customer gets back what they paid for the line, tax included
+		amount := pricing.Prorate(d.LineTotal, d.Quantity, d.RefundedQty, d.RefundedAmount, qty)
+		tax := pricing.Prorate(d.TaxAmount, d.Quantity, d.RefundedQty, d.RefundedTax, qty)
+
+		refund.TotalAmount += amount
+		refund.Items = append(refund.Items, model.RefundItem{
+			TransactionDetailID: id,
+			ProductID:           int(d.ProductID.Int64),
+			ProductName:         d.ProductName,
+			Quantity:            qty,
+			Amount:              amount,
+			TaxAmount:           tax,
+		})
+
+		// Products deleted since the sale have nothing left to restock
+		if !d.ProductID.Valid {
+			continue
+		}
+		productID := int(d.ProductID.Int64)
+		if _, ok := restockByProduct[productID]; !ok {
+			restockOrder = append(restockOrder, productID)
+		}
+		restockByProduct[productID] += qty
+	}
+
+	// The refund is paid out of the drawer of the terminal issuing it
+	shiftID, err := openShiftID(tx, req.TerminalID)
+	if err != nil {
+		return nil, err
+	}
+	refund.ShiftID = int(shiftID.Int64)
+
+	// Points spent on the sale go back to the customer in proportion to the
+	// refund; only the rest is paid out as money
+	pointsReturned := 0
+	if customerID.Valid && pointsRedeemed > 0 {
+		var refundedBefore, returnedBefore int
+		err := tx.QueryRow(`
+			SELECT COALESCE((SELECT SUM(total_amount) FROM refunds WHERE transaction_id = $1), 0),
+				COALESCE((SELECT SUM(points) FROM loyalty_point_entries WHERE transaction_id = $1 AND type = $2), 0)
+		`, transactionID, model.PointEntryReturn).Scan(&refundedBefore, &returnedBefore)
+		if err != nil {
+			return nil, err
+		}
+		pointsReturned = pricing.Prorate(pointsRedeemed, totalAmount, refundedBefore, returnedBefore, refund.TotalAmount)
+	}
+
+	held, err := heldPayments(tx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	heldMoney := make([]model.RefundPayment, 0, len(held))
+	for _, h := range held {
+		if h.Method != model.PaymentMethodPoints {
+			heldMoney = append(heldMoney, h)
+		}
+	}
+	refund.Payments = payment.SplitRefund(refund.TotalAmount-pointsReturned, heldMoney)
+	if pointsReturned > 0 {
+		refund.Payments = append(refund.Payments, model.RefundPayment{Method: model.PaymentMethodPoints, Amount: pointsReturned})
+	}
+
+	err = tx.QueryRow(
+		"INSERT INTO refunds (transaction_id, type, reason, total_amount, shift_id) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at",
+		transactionID, refund.Type, refund.Reason, refund.TotalAmount, shiftID,
+	).Scan(&refund.ID, &refund.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := insertRefundPayments(tx, refund.ID, refund.Payments); err != nil {
+		return nil, err
+	}
+
+	insertArgs := make([]interface{}, 0, len(refund.Items)*6)
+	var insertQuery strings.Builder
+	insertQuery.WriteString("INSERT INTO refund_items (refund_id, transaction_detail_id, product_id, quantity, amount, tax_amount) VALUES ")
+	argPos := 1
+	for i, item := range refund.Items {
+		if i > 0 {
+			insertQuery.WriteString(",")
+		}
+		insertQuery.WriteString(fmt.Sprintf("($%d::int, $%d::int, $%d::int, $%d::int, $%d::int, $%d::int)", argPos, argPos+1, argPos+2, argPos+3, argPos+4, argPos+5))
+		insertArgs = append(insertArgs, refund.ID, item.TransactionDetailID, detailsByID[item.TransactionDetailID].ProductID, item.Quantity, item.Amount, item.TaxAmount)
+		argPos += 6
+	}
+	insertQuery.WriteString(" RETURNING id")
+
+	itemRows, err := tx.Query(insertQuery.String(), insertArgs...)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; itemRows.Next(); i++ {
+		if err := itemRows.Scan(&refund.Items[i].ID); err != nil {
+			itemRows.Close()
+			return nil, err
+		}
+		refund.Items[i].RefundID = refund.ID
+	}
+	itemRows.Close()
+	if err := itemRows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(restockOrder) > 0 {
+		updateArgs := make([]interface{}, 0, len(restockOrder)*2)
+		var updateQuery strings.Builder
+		updateQuery.WriteString("UPDATE products SET stock = stock + v.qty FROM (VALUES ")
+		argPos = 1
+		for i, productID := range restockOrder {
+			if i > 0 {
+				updateQuery.WriteString(",")
+			}
+			updateQuery.WriteString(fmt.Sprintf("($%d::int, $%d::int)", argPos, argPos+1))
+			updateArgs = append(updateArgs, productID, restockByProduct[productID])
+			argPos += 2
+		}
+		updateQuery.WriteString(") AS v(id, qty) WHERE products.id = v.id RETURNING products.id, products.stock")
+
+		stockRows, err := tx.Query(updateQuery.String(), updateArgs...)
+		if err != nil {
+			return nil, err
+		}
+		stockAfter, err := scanStockLevels(stockRows)
+		if err != nil {
+			return nil, err
+		}
+
+		movementType := model.StockMovementRefund
+		if refundType == model.RefundTypeVoid {
+			movementType = model.StockMovementVoid
+		}
+		movements := make([]model.StockMovement, 0, len(restockOrder))
+		for _, productID := range restockOrder {
+			// Products deleted while the refund ran have no stock to record
+			after, ok := stockAfter[productID]
+			if !ok {
+				continue
+			}
+			movements = append(movements, model.StockMovement{
+				ProductID:     productID,
+				Type:          movementType,
+				Quantity:      restockByProduct[productID],
+				StockAfter:    after,
+				Reason:        req.Reason,
+				ReferenceType: model.StockReferenceRefund,
+				ReferenceID:   refund.ID,
+				UserID:        req.UserID,
+			})
+		}
+		if err := recordStockMovements(tx, movements); err != nil {
+			return nil, err
+		}
+	}
+
+	fullyRefunded := true
+	for _, id := range detailOrder {
+		d := detailsByID[id]
+		if d.RefundedQty+qtyByDetail[id] < d.Quantity {
+			fullyRefunded = false
+			break
+		}
+	}
+	newStatus := model.TransactionStatusPartiallyRefunded
+	if fullyRefunded {
+		newStatus = model.TransactionStatusRefunded
+		if refundType == model.RefundTypeVoid {
+			newStatus = model.TransactionStatusVoided
+		}
+	}
+	if _, err := tx.Exec("UPDATE transactions SET status = $1 WHERE id = $2", newStatus, transactionID); err != nil {
+		return nil, err
+	}
+	if newStatus == model.TransactionStatusVoided {
+		if err := releaseVoucherRedemption(tx, transactionID); err != nil {
+			return nil, err
+		}
+	}
+
+	if pointsReturned > 0 {
+		if err := addPoints(tx, int(customerID.Int64), transactionID, model.PointEntryReturn, pointsReturned); err != nil {
+			return nil, err
+		}
+	}
+
+	// Points earned on the sale are taken back in proportion to the money refunded
+	if customerID.Valid && pointsEarned > 0 {
+		var refundedBefore, reversedBefore int
+		err := tx.QueryRow(`
+			SELECT COALESCE((SELECT SUM(total_amount) FROM refunds WHERE transaction_id = $1 AND id <> $2), 0),
+				COALESCE((SELECT -SUM(points) FROM loyalty_point_entries WHERE transaction_id = $1 AND type = $3), 0)
+		`, transactionID, refund.ID, model.PointEntryReverse).Scan(&refundedBefore, &reversedBefore)
+		if err != nil {
+			return nil, err
+		}
+		reverse := pricing.Prorate(pointsEarned, totalAmount, refundedBefore, reversedBefore, refund.TotalAmount)
+		if err := addPoints(tx, int(customerID.Int64), transactionID, model.PointEntryReverse, -reverse); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return refund, nil
 }
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"database/sql"
+	"kasir-api/internal/model"
+)
+
+type UserRepository interface {
+	Create(user model.User) (model.User, error)
+	GetAll() ([]model.User, error)
+	GetByID(id int) (model.User, error)
+	GetByUsername(username string) (model.User, error)
+	Update(id int, user model.User) (model.User, error)
+	Count() (int, error)
+}
+
+type postgresUserRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) UserRepository {
+	return &postgresUserRepository{db: db}
+}
+
+func (r *postgresUserRepository) Create(user model.User) (model.User, error) {
+	query := `INSERT INTO users (username, password_hash, name, role, active) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+	err := r.db.QueryRow(query, user.Username, user.PasswordHash, user.Name, user.Role, user.Active).Scan(&user.ID, &user.CreatedAt)
+	if err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+func (r *postgresUserRepository) GetAll() ([]model.User, error) {
+	rows, err := r.db.Query(`SELECT id, username, password_hash, name, role, active, created_at FROM users ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []model.User
+	for rows.Next() {
+		var u model.User
+		if err := rows.Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Name, &u.Role, &u.Active, &u.CreatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+func (r *postgresUserRepository) GetByID(id int) (model.User, error) {
+	var u model.User
+	err := r.db.QueryRow(`SELECT id, username, password_hash, name, role, active, created_at FROM users WHERE id = $1`, id).
+		Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Name, &u.Role, &u.Active, &u.CreatedAt)
+	if err != nil {
+		return model.User{}, err
+	}
+	return u, nil
+}
+
+func (r *postgresUserRepository) GetByUsername(username string) (model.User, error) {
+	var u model.User
+	err := r.db.QueryRow(`SELECT id, username, password_hash, name, role, active, created_at FROM users WHERE username = $1`, username).
+		Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Name, &u.Role, &u.Active, &u.CreatedAt)
+	if err != nil {
+		return model.User{}, err
+	}
+	return u, nil
+}
+
+func (r *postgresUserRepository) Update(id int, user model.User) (model.User, error) {
+	query := `
+		UPDATE users SET username = $1, password_hash = $2, name = $3, role = $4, active = $5
+		WHERE id = $6
+		RETURNING id, username, password_hash, name, role, active, created_at
+	`
+	var u model.User
+	err := r.db.QueryRow(query, user.Username, user.PasswordHash, user.Name, user.Role, user.Active, id).
+		Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Name, &u.Role, &u.Active, &u.CreatedAt)
+	if err != nil {
+		return model.User{}, err
+	}
+	return u, nil
+}
+
+func (r *postgresUserRepository) Count() (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kasir-api/internal/model"
+
+	"github.com/lib/pq"
+)
+
+var ErrInvalidVoucher = errors.New("invalid voucher")
+
+type VoucherRepository interface {
+	Create(v model.Voucher) (model.Voucher, error)
+	GetAll() ([]model.Voucher, error)
+	GetByID(id int) (model.Voucher, error)
+	Update(id int, v model.Voucher) (model.Voucher, error)
+	Delete(id int) error
+}
+
+type postgresVoucherRepository struct {
+	db *sql.DB
+}
+
+func NewVoucherRepository(db *sql.DB) VoucherRepository {
+	return &postgresVoucherRepository{db: db}
+}
+
+const voucherColumns = `v.id, v.code, v.type, v.percent_bp, v.amount, v.max_discount, v.min_spend, v.usage_limit, v.per_customer_limit,
+	v.active, v.expires_at, (SELECT COUNT(*) FROM voucher_redemptions vr WHERE vr.voucher_id = v.id AND vr.released_at IS NULL)`
+
+func scanVoucher(row rowScanner) (model.Voucher, error) {
+	var v model.Voucher
+	var expiresAt sql.NullTime
+	err := row.Scan(&v.ID, &v.Code, &v.Type, &v.PercentBP, &v.Amount, &v.MaxDiscount, &v.MinSpend, &v.UsageLimit, &v.PerCustomerLimit,
+		&v.Active, &expiresAt, &v.Redeemed)
+	if err != nil {
+		return model.Voucher{}, err
+	}
+	if expiresAt.Valid {
+		v.ExpiresAt = &expiresAt.Time
+	}
+	return v, nil
+}
+
+// duplicateCode turns a unique violation on the code into ErrInvalidVoucher.
+func duplicateCode(err error, code string) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return fmt.Errorf("%w: code %s already exists", ErrInvalidVoucher, code)
+	}
+	return err
+}
+
+func (r *postgresVoucherRepository) Create(v model.Voucher) (model.Voucher, error) {
+	var id int
+	err := r.db.QueryRow(`
+		INSERT INTO vouchers (code, type, percent_bp, amount, max_discount, min_spend, usage_limit, per_customer_limit, active, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`, v.Code, v.Type, v.PercentBP, v.Amount, v.MaxDiscount, v.MinSpend, v.UsageLimit, v.PerCustomerLimit, v.Active, v.ExpiresAt).Scan(&id)
+	if err != nil {
+		return model.Voucher{}, duplicateCode(err, v.Code)
+	}
+	return r.GetByID(id)
+}
+
+func (r *postgresVoucherRepository) GetAll() ([]model.Voucher, error) {
+	rows, err := r.db.Query("SELECT " + voucherColumns + " FROM vouchers v ORDER BY v.id DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	vouchers := []model.Voucher{}
+	for rows.Next() {
+		v, err := scanVoucher(rows)
+		if err != nil {
+			return nil, err
+		}
+		vouchers = append(vouchers, v)
+	}
+	return vouchers, rows.Err()
+}
+
+func (r *postgresVoucherRepository) GetByID(id int) (model.Voucher, error) {
+	return scanVoucher(r.db.QueryRow("SELECT "+voucherColumns+" FROM vouchers v WHERE v.id = $1", id))
+}
+
+func (r *postgresVoucherRepository) Update(id int, v model.Voucher) (model.Voucher, error) {
+	result, err := r.db.Exec(`
+		UPDATE vouchers
+		SET code = $1, type = $2, percent_bp = $3, amount = $4, max_discount = $5, min_spend = $6,
+			usage_limit = $7, per_customer_limit = $8, active = $9, expires_at = $10
+		WHERE id = $11
+	`, v.Code, v.Type, v.PercentBP, v.Amount, v.MaxDiscount, v.MinSpend, v.UsageLimit, v.PerCustomerLimit, v.Active, v.ExpiresAt, id)
+	if err != nil {
+		return model.Voucher{}, duplicateCode(err, v.Code)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return model.Voucher{}, err
+	}
+	if rowsAffected == 0 {
+		return model.Voucher{}, sql.ErrNoRows
+	}
+	return r.GetByID(id)
+}
+
+// Delete removes a voucher. Vouchers that have been redeemed are kept so
+// their redemption history stays intact; they can be deactivated instead.
+func (r *postgresVoucherRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM vouchers WHERE id = $1`, id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return fmt.Errorf("%w: voucher %d has been redeemed, deactivate it instead", ErrInvalidVoucher, id)
+	}
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// lockVoucher loads the voucher with the given code for redemption, locking
+// its row until tx ends. Concurrent checkouts using the same code queue up
+// here, so the usage counts read below cannot change before the redemption
+// is recorded.
+func lockVoucher(tx *sql.Tx, code, customerRef string) (model.Voucher, error) {
+	var v model.Voucher
+	var expired bool
+	err := tx.QueryRow(`
+		SELECT id, code, type, percent_bp, amount, max_discount, min_spend, usage_limit, per_customer_limit, active,
+			COALESCE(expires_at <= CURRENT_TIMESTAMP, FALSE)
+		FROM vouchers
+		WHERE code = $1
+		FOR UPDATE
+	`, code).Scan(&v.ID, &v.Code, &v.Type, &v.PercentBP, &v.Amount, &v.MaxDiscount, &v.MinSpend, &v.UsageLimit, &v.PerCustomerLimit,
+		&v.Active, &expired)
+	if err == sql.ErrNoRows {
+		return model.Voucher{}, fmt.Errorf("%w: code %s not found", ErrInvalidVoucher, code)
+	}
+	if err != nil {
+		return model.Voucher{}, err
+	}
+
+	if !v.Active {
+		return model.Voucher{}, fmt.Errorf("%w: code %s is not active", ErrInvalidVoucher, code)
+	}
+	if expired {
+		return model.Voucher{}, fmt.Errorf("%w: code %s has expired", ErrInvalidVoucher, code)
+	}
+	if v.PerCustomerLimit > 0 && customerRef == "" {
+		return model.Voucher{}, fmt.Errorf("%w: code %s needs a customer", ErrInvalidVoucher, code)
+	}
+
+	var redeemed, redeemedByCustomer int
+	err = tx.QueryRow(`
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE customer_ref = $2)
+		FROM voucher_redemptions
+		WHERE voucher_id = $1 AND released_at IS NULL
+	`, v.ID, customerRef).Scan(&redeemed, &redeemedByCustomer)
+	if err != nil {
+		return model.Voucher{}, err
+	}
+	if v.UsageLimit > 0 && redeemed >= v.UsageLimit {
+		return model.Voucher{}, fmt.Errorf("%w: code %s has been fully redeemed", ErrInvalidVoucher, code)
+	}
+	if v.PerCustomerLimit > 0 && redeemedByCustomer >= v.PerCustomerLimit {
+		return model.Voucher{}, fmt.Errorf("%w: code %s has already been used by this customer", ErrInvalidVoucher, code)
+	}
+	v.Redeemed = redeemed
+
+	return v, nil
+}
+
+func insertVoucherRedemption(tx *sql.Tx, voucherID, transactionID int, customerRef string, amount int) error {
+	_, err := tx.Exec(
+		"INSERT INTO voucher_redemptions (voucher_id, transaction_id, customer_ref, amount) VALUES ($1, $2, NULLIF($3, ''), $4)",
+		voucherID, transactionID, customerRef, amount,
+	)
+	return err
+}
+
+// releaseVoucherRedemption hands the voucher use of a voided sale back, so it
+// no longer counts against the voucher's limits.
+func releaseVoucherRedemption(tx *sql.Tx, transactionID int) error {
+	_, err := tx.Exec(
+		"UPDATE voucher_redemptions SET released_at = CURRENT_TIMESTAMP WHERE transaction_id = $1 AND released_at IS NULL", transactionID,
+	)
+	return err
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"errors"
+	"kasir-api/internal/auth"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+)
+
+type APIClientService interface {
+	Create(req model.APIClientRequest) (*model.APIClientCredentials, error)
+	GetAll() ([]model.APIClient, error)
+	Revoke(id int) error
+}
+
+type apiClientService struct {
+	repo     repository.APIClientRepository
+	roleRepo repository.RoleRepository
+}
+
+func NewAPIClientService(repo repository.APIClientRepository, roleRepo repository.RoleRepository) APIClientService {
+	return &apiClientService{repo: repo, roleRepo: roleRepo}
+}
+
+func (s *apiClientService) Create(req model.APIClientRequest) (*model.APIClientCredentials, error) {
+	if req.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if _, err := s.roleRepo.GetByName(req.Role); err != nil {
+		return nil, errors.New("role not found")
+	}
+
+	key, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := s.repo.Create(model.APIClient{
+		Name:      req.Name,
+		KeyPrefix: prefix,
+		KeyHash:   hash,
+		Role:      req.Role,
+		Active:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.APIClientCredentials{APIClient: client, Key: key}, nil
+}
+
+func (s *apiClientService) GetAll() ([]model.APIClient, error) {
+	return s.repo.GetAll()
+}
+
+func (s *apiClientService) Revoke(id int) error {
+	return s.repo.Deactivate(id)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"errors"
+	"kasir-api/internal/auth"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrInvalidCredentials = errors.New("invalid username or password")
+
+type AuthService interface {
+	Login(req model.LoginRequest) (*model.LoginResponse, error)
+	Authenticate(token string) (*auth.Principal, error)
+	AuthenticateAPIKey(key string) (*auth.Principal, error)
+}
+
+type authService struct {
+	users      repository.UserRepository
+	roles      repository.RoleRepository
+	apiClients repository.APIClientRepository
+	tokens     *auth.TokenManager
+}
+
+func NewAuthService(users repository.UserRepository, roles repository.RoleRepository, apiClients repository.APIClientRepository, tokens *auth.TokenManager) AuthService {
+	return &authService{users: users, roles: roles, apiClients: apiClients, tokens: tokens}
+}
+
+func (s *authService) Login(req model.LoginRequest) (*model.LoginResponse, error) {
+	user, err := s.users.GetByUsername(req.Username)
+	if err != nil || !user.Active {
+		return nil, ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	token, expiresAt, err := s.tokens.Issue(user.ID, user.Username, user.Role, strings.TrimSpace(req.TerminalID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.LoginResponse{Token: token, ExpiresAt: expiresAt, User: user}, nil
+}
+
+// Authenticate verifies a bearer token and re-checks the account, so
+// deactivating a user or changing their role takes effect immediately.
+func (s *authService) Authenticate(token string) (*auth.Principal, error) {
+	p, err := s.tokens.Parse(token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.GetByID(p.UserID)
+	if err != nil || !user.Active {
+		return nil, auth.ErrInvalidToken
+	}
+
+	role, err := s.roles.GetByName(user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.Principal{
+		Type:        auth.PrincipalUser,
+		UserID:      user.ID,
+		Username:    user.Username,
+		Role:        role.Name,
+		Permissions: role.Permissions,
+		TerminalID:  p.TerminalID,
+	}, nil
+}
+
+// AuthenticateAPIKey resolves the API client owning key, if it is still active.
+func (s *authService) AuthenticateAPIKey(key string) (*auth.Principal, error) {
+	client, err := s.apiClients.GetByKeyHash(auth.HashAPIKey(key))
+	if err != nil || !client.Active {
+		return nil, auth.ErrInvalidAPIKey
+	}
+
+	role, err := s.roles.GetByName(client.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auth.Principal{
+		Type:        auth.PrincipalAPIClient,
+		APIClientID: client.ID,
+		Username:    client.Name,
+		Role:        role.Name,
+		Permissions: role.Permissions,
+	}, nil
+}
@@ -15,17 +15,21 @@ type CategoryService interface {
 }
 
 type categoryService struct {
-	repo repository.CategoryRepository
+	repo    repository.CategoryRepository
+	taxRepo repository.TaxRateRepository
 }
 
-func NewCategoryService(repo repository.CategoryRepository) CategoryService {
-	return &categoryService{repo: repo}
+func NewCategoryService(repo repository.CategoryRepository, taxRepo repository.TaxRateRepository) CategoryService {
+	return &categoryService{repo: repo, taxRepo: taxRepo}
 }
 
 func (s *categoryService) Create(category model.Category) (model.Category, error) {
 	if category.Name == "" {
 		return model.Category{}, errors.New("name is required")
 	}
+	if err := validateTaxRateID(s.taxRepo, category.TaxRateID); err != nil {
+		return model.Category{}, err
+	}
 	return s.repo.Create(category)
 }
 
@@ -41,6 +45,9 @@ func (s *categoryService) Update(id int, category model.Category) (model.Categor
 	if category.Name == "" {
 		return model.Category{}, errors.New("name is required")
 	}
+	if err := validateTaxRateID(s.taxRepo, category.TaxRateID); err != nil {
+		return model.Category{}, err
+	}
 	return s.repo.Update(id, category)
 }
 
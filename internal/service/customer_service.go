@@ -0,0 +1,69 @@
+package service
+
+import (
+	"fmt"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"strings"
+)
+
+type CustomerService interface {
+	Create(c model.Customer) (*model.Customer, error)
+	GetAll(search string) ([]model.Customer, error)
+	GetByID(id int) (*model.Customer, error)
+	Update(id int, c model.Customer) (*model.Customer, error)
+	Delete(id int) error
+	GetPointEntries(customerID int) ([]model.PointEntry, error)
+}
+
+type customerService struct {
+	repo repository.CustomerRepository
+}
+
+func NewCustomerService(repo repository.CustomerRepository) CustomerService {
+	return &customerService{repo: repo}
+}
+
+func (s *customerService) Create(c model.Customer) (*model.Customer, error) {
+	if err := validateCustomer(&c); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(c)
+}
+
+func (s *customerService) GetAll(search string) ([]model.Customer, error) {
+	return s.repo.GetAll(strings.TrimSpace(search))
+}
+
+func (s *customerService) GetByID(id int) (*model.Customer, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *customerService) Update(id int, c model.Customer) (*model.Customer, error) {
+	if err := validateCustomer(&c); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(id, c)
+}
+
+func (s *customerService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+func (s *customerService) GetPointEntries(customerID int) ([]model.PointEntry, error) {
+	if _, err := s.repo.GetByID(customerID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetPointEntries(customerID)
+}
+
+// validateCustomer trims the customer's details in place and checks them.
+func validateCustomer(c *model.Customer) error {
+	c.Name = strings.TrimSpace(c.Name)
+	c.Phone = strings.TrimSpace(c.Phone)
+	c.MemberNumber = strings.ToUpper(strings.TrimSpace(c.MemberNumber))
+	if c.Name == "" {
+		return fmt.Errorf("%w: name is required", repository.ErrInvalidCustomer)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"fmt"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"strings"
+	"time"
+)
+
+type DayReportService interface {
+	GetXReport(businessDate string) (*model.DayReport, error)
+	CloseDay(req model.CloseDayRequest) (*model.DayReport, error)
+	GetZReports() ([]model.DayReport, error)
+	GetZReport(zNumber int) (*model.DayReport, error)
+}
+
+type dayReportService struct {
+	repo repository.DayReportRepository
+}
+
+func NewDayReportService(repo repository.DayReportRepository) DayReportService {
+	return &dayReportService{repo: repo}
+}
+
+func (s *dayReportService) GetXReport(businessDate string) (*model.DayReport, error) {
+	if err := validateBusinessDate(businessDate); err != nil {
+		return nil, err
+	}
+	return s.repo.GetXReport(businessDate)
+}
+
+func (s *dayReportService) CloseDay(req model.CloseDayRequest) (*model.DayReport, error) {
+	if err := validateBusinessDate(req.BusinessDate); err != nil {
+		return nil, err
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	return s.repo.CloseDay(req)
+}
+
+func (s *dayReportService) GetZReports() ([]model.DayReport, error) {
+	return s.repo.GetZReports()
+}
+
+func (s *dayReportService) GetZReport(zNumber int) (*model.DayReport, error) {
+	return s.repo.GetZReport(zNumber)
+}
+
+// validateBusinessDate accepts an empty date, meaning today, or YYYY-MM-DD.
+func validateBusinessDate(businessDate string) error {
+	if businessDate == "" {
+		return nil
+	}
+	if _, err := time.Parse("2006-01-02", businessDate); err != nil {
+		return fmt.Errorf("%w: business_date must be in YYYY-MM-DD format", repository.ErrInvalidDayClose)
+	}
+	return nil
+}
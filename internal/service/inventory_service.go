@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"time"
+)
+
+type InventoryService interface {
+	GetStockMovements(productID int, filter model.StockMovementFilter) ([]model.StockMovement, error)
+	AdjustStock(productID int, req model.StockAdjustmentRequest) (*model.StockMovement, error)
+}
+
+type inventoryService struct {
+	movementRepo repository.StockMovementRepository
+	productRepo  repository.ProductRepository
+}
+
+func NewInventoryService(movementRepo repository.StockMovementRepository, productRepo repository.ProductRepository) InventoryService {
+	return &inventoryService{movementRepo: movementRepo, productRepo: productRepo}
+}
+
+var stockMovementTypes = []string{
+	model.StockMovementInitial,
+	model.StockMovementSale,
+	model.StockMovementRefund,
+	model.StockMovementVoid,
+	model.StockMovementAdjustment,
+	model.StockMovementReceiving,
+	model.StockMovementStockTake,
+}
+
+func (s *inventoryService) GetStockMovements(productID int, filter model.StockMovementFilter) ([]model.StockMovement, error) {
+	if filter.Type != "" {
+		known := false
+		for _, t := range stockMovementTypes {
+			known = known || t == filter.Type
+		}
+		if !known {
+			return nil, errors.New("unknown movement type")
+		}
+	}
+	if filter.StartDate != "" {
+		if _, err := time.Parse("2006-01-02", filter.StartDate); err != nil {
+			return nil, errors.New("start_date must be in YYYY-MM-DD format")
+		}
+	}
+	if filter.EndDate != "" {
+		if _, err := time.Parse("2006-01-02", filter.EndDate); err != nil {
+			return nil, errors.New("end_date must be in YYYY-MM-DD format")
+		}
+	}
+
+	if _, err := s.productRepo.GetByID(productID); err != nil {
+		return nil, err
+	}
+	return s.movementRepo.GetByProduct(productID, filter)
+}
+
+func (s *inventoryService) AdjustStock(productID int, req model.StockAdjustmentRequest) (*model.StockMovement, error) {
+	if req.Quantity == 0 {
+		return nil, fmt.Errorf("%w: quantity cannot be zero", repository.ErrInvalidStockAdjustment)
+	}
+	known := false
+	for _, code := range model.StockReasonCodes {
+		known = known || code == req.ReasonCode
+	}
+	if !known {
+		return nil, fmt.Errorf("%w: unknown reason_code %q", repository.ErrInvalidStockAdjustment, req.ReasonCode)
+	}
+	return s.movementRepo.Adjust(productID, req)
+}
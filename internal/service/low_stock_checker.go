@@ -0,0 +1,55 @@
+package service
+
+import (
+	"kasir-api/internal/notify"
+	"kasir-api/internal/repository"
+	"log"
+)
+
+const lowStockQueueSize = 256
+
+// LowStockChecker looks for products that dropped below their minimum stock
+// after a checkout and hands them to a notifier. Checks run on a background
+// goroutine so a slow webhook never holds up the till.
+type LowStockChecker struct {
+	repo     repository.StockMovementRepository
+	notifier notify.Notifier
+	queue    chan int
+}
+
+func NewLowStockChecker(repo repository.StockMovementRepository, notifier notify.Notifier) *LowStockChecker {
+	return &LowStockChecker{repo: repo, notifier: notifier, queue: make(chan int, lowStockQueueSize)}
+}
+
+// Start runs the checker until the process exits.
+func (c *LowStockChecker) Start() {
+	go func() {
+		for transactionID := range c.queue {
+			c.check(transactionID)
+		}
+	}()
+}
+
+// TransactionCompleted queues a completed checkout for checking. It never
+// blocks; when the queue is full the check is dropped and logged.
+func (c *LowStockChecker) TransactionCompleted(transactionID int) {
+	select {
+	case c.queue <- transactionID:
+	default:
+		log.Printf("low stock check queue full, skipped transaction %d", transactionID)
+	}
+}
+
+func (c *LowStockChecker) check(transactionID int) {
+	alerts, err := c.repo.GetLowStockCrossings(transactionID)
+	if err != nil {
+		log.Printf("low stock check for transaction %d failed: %v", transactionID, err)
+		return
+	}
+	if len(alerts) == 0 {
+		return
+	}
+	if err := c.notifier.NotifyLowStock(alerts); err != nil {
+		log.Printf("low stock alert for transaction %d failed: %v", transactionID, err)
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"errors"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+)
+
+type LoyaltyRuleService interface {
+	Create(rule model.LoyaltyRule) (model.LoyaltyRule, error)
+	GetAll() ([]model.LoyaltyRule, error)
+	GetByID(id int) (model.LoyaltyRule, error)
+	Update(id int, rule model.LoyaltyRule) (model.LoyaltyRule, error)
+	Delete(id int) error
+}
+
+type loyaltyRuleService struct {
+	repo         repository.LoyaltyRuleRepository
+	categoryRepo repository.CategoryRepository
+}
+
+func NewLoyaltyRuleService(repo repository.LoyaltyRuleRepository, categoryRepo repository.CategoryRepository) LoyaltyRuleService {
+	return &loyaltyRuleService{repo: repo, categoryRepo: categoryRepo}
+}
+
+func (s *loyaltyRuleService) Create(rule model.LoyaltyRule) (model.LoyaltyRule, error) {
+	if err := s.validate(rule); err != nil {
+		return model.LoyaltyRule{}, err
+	}
+	return s.repo.Create(rule)
+}
+
+func (s *loyaltyRuleService) GetAll() ([]model.LoyaltyRule, error) {
+	return s.repo.GetAll()
+}
+
+func (s *loyaltyRuleService) GetByID(id int) (model.LoyaltyRule, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *loyaltyRuleService) Update(id int, rule model.LoyaltyRule) (model.LoyaltyRule, error) {
+	if err := s.validate(rule); err != nil {
+		return model.LoyaltyRule{}, err
+	}
+	return s.repo.Update(id, rule)
+}
+
+func (s *loyaltyRuleService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+func (s *loyaltyRuleService) validate(rule model.LoyaltyRule) error {
+	if rule.Name == "" {
+		return errors.New("name is required")
+	}
+	if rule.SpendAmount <= 0 || rule.Points <= 0 {
+		return errors.New("spend_amount and points must be greater than zero")
+	}
+	if rule.CategoryID != nil {
+		if _, err := s.categoryRepo.GetByID(*rule.CategoryID); err != nil {
+			return errors.New("category not found")
+		}
+	}
+	return nil
+}
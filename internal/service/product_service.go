@@ -9,6 +9,7 @@ import (
 type ProductService interface {
 	Create(product model.Product) (model.Product, error)
 	GetAll(nameFilter string) ([]model.Product, error)
+	GetLowStock() ([]model.Product, error)
 	GetByID(id int) (model.Product, error)
 	Update(id int, product model.Product) (model.Product, error)
 	Delete(id int) error
@@ -17,10 +18,11 @@ type ProductService interface {
 type productService struct {
 	repo    repository.ProductRepository
 	catRepo repository.CategoryRepository
+	taxRepo repository.TaxRateRepository
 }
 
-func NewProductService(repo repository.ProductRepository, catRepo repository.CategoryRepository) ProductService {
-	return &productService{repo: repo, catRepo: catRepo}
+func NewProductService(repo repository.ProductRepository, catRepo repository.CategoryRepository, taxRepo repository.TaxRateRepository) ProductService {
+	return &productService{repo: repo, catRepo: catRepo, taxRepo: taxRepo}
 }
 
 func (s *productService) Create(product model.Product) (model.Product, error) {
@@ -30,12 +32,21 @@ func (s *productService) Create(product model.Product) (model.Product, error) {
 	if product.Price < 0 {
 		return model.Product{}, errors.New("price cannot be negative")
 	}
+	if product.CostPrice < 0 {
+		return model.Product{}, errors.New("cost_price cannot be negative")
+	}
+	if err := validateReorderLevels(product); err != nil {
+		return model.Product{}, err
+	}
 
 	// Validate category exists
 	_, err := s.catRepo.GetByID(product.CategoryID)
 	if err != nil {
 		return model.Product{}, errors.New("category not found")
 	}
+	if err := validateTaxRateID(s.taxRepo, product.TaxRateID); err != nil {
+		return model.Product{}, err
+	}
 
 	return s.repo.Create(product)
 }
@@ -44,6 +55,10 @@ func (s *productService) GetAll(nameFilter string) ([]model.Product, error) {
 	return s.repo.GetAll(nameFilter)
 }
 
+func (s *productService) GetLowStock() ([]model.Product, error) {
+	return s.repo.GetLowStock()
+}
+
 func (s *productService) GetByID(id int) (model.Product, error) {
 	return s.repo.GetByID(id)
 }
@@ -55,6 +70,9 @@ func (s *productService) Update(id int, product model.Product) (model.Product, e
 	if product.Price < 0 {
 		return model.Product{}, errors.New("price cannot be negative")
 	}
+	if err := validateReorderLevels(product); err != nil {
+		return model.Product{}, err
+	}
 
 	// Validate category exists if category_id is being updated/set
 	if product.CategoryID != 0 {
@@ -63,6 +81,9 @@ func (s *productService) Update(id int, product model.Product) (model.Product, e
 			return model.Product{}, errors.New("category not found")
 		}
 	}
+	if err := validateTaxRateID(s.taxRepo, product.TaxRateID); err != nil {
+		return model.Product{}, err
+	}
 
 	return s.repo.Update(id, product)
 }
@@ -70,3 +91,13 @@ func (s *productService) Update(id int, product model.Product) (model.Product, e
 func (s *productService) Delete(id int) error {
 	return s.repo.Delete(id)
 }
+
+func validateReorderLevels(product model.Product) error {
+	if product.MinStock < 0 {
+		return errors.New("min_stock cannot be negative")
+	}
+	if product.ReorderQuantity < 0 {
+		return errors.New("reorder_quantity cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+	"kasir-api/internal/model"
+	"kasir-api/internal/pricing"
+	"kasir-api/internal/repository"
+)
+
+type PromotionService interface {
+	Create(promo model.Promotion) (model.Promotion, error)
+	GetAll() ([]model.Promotion, error)
+	GetByID(id int) (model.Promotion, error)
+	Update(id int, promo model.Promotion) (model.Promotion, error)
+	Delete(id int) error
+}
+
+type promotionService struct {
+	repo repository.PromotionRepository
+}
+
+func NewPromotionService(repo repository.PromotionRepository) PromotionService {
+	return &promotionService{repo: repo}
+}
+
+func (s *promotionService) Create(promo model.Promotion) (model.Promotion, error) {
+	if err := validatePromotion(promo); err != nil {
+		return model.Promotion{}, err
+	}
+	return s.repo.Create(promo)
+}
+
+func (s *promotionService) GetAll() ([]model.Promotion, error) {
+	return s.repo.GetAll()
+}
+
+func (s *promotionService) GetByID(id int) (model.Promotion, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *promotionService) Update(id int, promo model.Promotion) (model.Promotion, error) {
+	if err := validatePromotion(promo); err != nil {
+		return model.Promotion{}, err
+	}
+	return s.repo.Update(id, promo)
+}
+
+func (s *promotionService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+func validatePromotion(promo model.Promotion) error {
+	if promo.Name == "" {
+		return errors.New("name is required")
+	}
+	if promo.StartsAt != nil && promo.EndsAt != nil && !promo.EndsAt.After(*promo.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	for _, item := range promo.Items {
+		if (item.ProductID == 0) == (item.CategoryID == 0) {
+			return errors.New("each item needs either a product_id or a category_id")
+		}
+	}
+
+	switch promo.Type {
+	case model.PromotionPercentage:
+		if promo.PercentBP <= 0 || promo.PercentBP > pricing.BasisPoints {
+			return errors.New("percent_bp must be between 1 and 10000")
+		}
+	case model.PromotionFixedAmount:
+		if promo.Amount <= 0 {
+			return errors.New("amount must be greater than zero")
+		}
+	case model.PromotionBuyXGetY:
+		if promo.BuyQty <= 0 || promo.GetQty <= 0 {
+			return errors.New("buy_qty and get_qty must be greater than zero")
+		}
+	case model.PromotionBundle:
+		if promo.Amount <= 0 {
+			return errors.New("amount (the bundle price) must be greater than zero")
+		}
+		if len(promo.Items) == 0 {
+			return errors.New("a bundle needs items")
+		}
+		seen := make(map[int]bool)
+		for _, item := range promo.Items {
+			if item.ProductID == 0 || item.Quantity <= 0 {
+				return errors.New("bundle items need a product_id and a quantity")
+			}
+			if seen[item.ProductID] {
+				return errors.New("bundle items must be different products")
+			}
+			seen[item.ProductID] = true
+		}
+	case model.PromotionMinSpend:
+		if promo.MinSpend <= 0 {
+			return errors.New("min_spend must be greater than zero")
+		}
+		if (promo.PercentBP > 0) == (promo.Amount > 0) {
+			return errors.New("a minimum spend promotion needs either percent_bp or amount")
+		}
+		if promo.PercentBP > pricing.BasisPoints {
+			return errors.New("percent_bp must be between 1 and 10000")
+		}
+	default:
+		return errors.New("type must be one of percentage, fixed_amount, buy_x_get_y, bundle or min_spend")
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"fmt"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"strings"
+)
+
+type PurchaseOrderService interface {
+	Create(req model.PurchaseOrderRequest) (*model.PurchaseOrder, error)
+	GetAll(filter model.PurchaseOrderFilter) ([]model.PurchaseOrder, error)
+	GetByID(id int) (*model.PurchaseOrder, error)
+	Receive(id int, req model.GoodsReceiptRequest) (*model.GoodsReceipt, error)
+	Close(id int, req model.ClosePurchaseOrderRequest) (*model.PurchaseOrder, error)
+}
+
+type purchaseOrderService struct {
+	repo repository.PurchaseOrderRepository
+}
+
+func NewPurchaseOrderService(repo repository.PurchaseOrderRepository) PurchaseOrderService {
+	return &purchaseOrderService{repo: repo}
+}
+
+var purchaseOrderStatuses = []string{
+	model.PurchaseOrderStatusOpen,
+	model.PurchaseOrderStatusPartiallyReceived,
+	model.PurchaseOrderStatusReceived,
+	model.PurchaseOrderStatusClosed,
+}
+
+func (s *purchaseOrderService) Create(req model.PurchaseOrderRequest) (*model.PurchaseOrder, error) {
+	if req.SupplierID <= 0 {
+		return nil, fmt.Errorf("%w: supplier_id is required", repository.ErrInvalidPurchaseOrder)
+	}
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("%w: at least one item is required", repository.ErrInvalidPurchaseOrder)
+	}
+	seen := make(map[int]bool, len(req.Items))
+	for _, item := range req.Items {
+		if seen[item.ProductID] {
+			return nil, fmt.Errorf("%w: product %d is ordered more than once", repository.ErrInvalidPurchaseOrder, item.ProductID)
+		}
+		seen[item.ProductID] = true
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantity must be greater than zero", repository.ErrInvalidPurchaseOrder)
+		}
+		if item.UnitCost < 0 {
+			return nil, fmt.Errorf("%w: unit_cost cannot be negative", repository.ErrInvalidPurchaseOrder)
+		}
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	return s.repo.Create(req)
+}
+
+func (s *purchaseOrderService) GetAll(filter model.PurchaseOrderFilter) ([]model.PurchaseOrder, error) {
+	if filter.Status != "" {
+		known := false
+		for _, status := range purchaseOrderStatuses {
+			known = known || status == filter.Status
+		}
+		if !known {
+			return nil, fmt.Errorf("%w: unknown status %q", repository.ErrInvalidPurchaseOrder, filter.Status)
+		}
+	}
+	return s.repo.GetAll(filter)
+}
+
+func (s *purchaseOrderService) GetByID(id int) (*model.PurchaseOrder, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *purchaseOrderService) Receive(id int, req model.GoodsReceiptRequest) (*model.GoodsReceipt, error) {
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("%w: at least one item is required", repository.ErrInvalidPurchaseOrder)
+	}
+	seen := make(map[int]bool, len(req.Items))
+	for _, item := range req.Items {
+		if seen[item.ProductID] {
+			return nil, fmt.Errorf("%w: product %d is received more than once", repository.ErrInvalidPurchaseOrder, item.ProductID)
+		}
+		seen[item.ProductID] = true
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantity must be greater than zero", repository.ErrInvalidPurchaseOrder)
+		}
+		if item.UnitCost < 0 {
+			return nil, fmt.Errorf("%w: unit_cost cannot be negative", repository.ErrInvalidPurchaseOrder)
+		}
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	return s.repo.Receive(id, req)
+}
+
+func (s *purchaseOrderService) Close(id int, req model.ClosePurchaseOrderRequest) (*model.PurchaseOrder, error) {
+	req.Note = strings.TrimSpace(req.Note)
+	return s.repo.Close(id, req)
+}
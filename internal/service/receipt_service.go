@@ -0,0 +1,53 @@
+package service
+
+import (
+	"bytes"
+	"kasir-api/internal/businessday"
+	"kasir-api/internal/model"
+	"kasir-api/internal/receipt"
+	"kasir-api/internal/repository"
+	"time"
+)
+
+type ReceiptService interface {
+	PrintReceipt(transactionID int, req model.ReceiptRequest) ([]byte, error)
+}
+
+type receiptService struct {
+	transactions repository.TransactionRepository
+	repo         repository.ReceiptRepository
+	renderer     *receipt.Renderer
+	calendar     *businessday.Calendar
+}
+
+// NewReceiptService builds the service; receipts show times in the
+// calendar's timezone.
+func NewReceiptService(transactions repository.TransactionRepository, repo repository.ReceiptRepository, renderer *receipt.Renderer, calendar *businessday.Calendar) ReceiptService {
+	return &receiptService{transactions: transactions, repo: repo, renderer: renderer, calendar: calendar}
+}
+
+// PrintReceipt renders a receipt of the transaction. Paper receipts, text
+// and ESC/POS, are recorded and every one after the first is marked as a
+// reprint; an HTML copy for email is neither recorded nor marked.
+func (s *receiptService) PrintReceipt(transactionID int, req model.ReceiptRequest) ([]byte, error) {
+	transaction, err := s.transactions.GetTransactionByID(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	printed := &model.ReceiptPrint{TransactionID: transactionID, Format: req.Format, PrintedBy: req.PrintedBy, PrintedAt: time.Now()}
+	if req.Format != model.ReceiptFormatHTML {
+		printed, err = s.repo.RecordPrint(transactionID, req.Format, req.PrintedBy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	transaction.CreatedAt = transaction.CreatedAt.In(s.calendar.Location())
+	printed.PrintedAt = printed.PrintedAt.In(s.calendar.Location())
+
+	var buf bytes.Buffer
+	if err := s.renderer.Render(&buf, req.Format, receipt.Receipt{Transaction: *transaction, Print: *printed}, req.Width); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kasir-api/internal/auth"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"regexp"
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+type RoleService interface {
+	Create(role model.Role) (model.Role, error)
+	GetAll() ([]model.Role, error)
+	GetByName(name string) (model.Role, error)
+	Update(name string, role model.Role) (model.Role, error)
+}
+
+type roleService struct {
+	repo repository.RoleRepository
+}
+
+func NewRoleService(repo repository.RoleRepository) RoleService {
+	return &roleService{repo: repo}
+}
+
+func (s *roleService) Create(role model.Role) (model.Role, error) {
+	if !roleNamePattern.MatchString(role.Name) {
+		return model.Role{}, errors.New("name must be lowercase letters, digits or underscores")
+	}
+	permissions, err := normalizePermissions(role.Permissions)
+	if err != nil {
+		return model.Role{}, err
+	}
+	role.Permissions = permissions
+	return s.repo.Create(role)
+}
+
+func (s *roleService) GetAll() ([]model.Role, error) {
+	return s.repo.GetAll()
+}
+
+func (s *roleService) GetByName(name string) (model.Role, error) {
+	return s.repo.GetByName(name)
+}
+
+func (s *roleService) Update(name string, role model.Role) (model.Role, error) {
+	permissions, err := normalizePermissions(role.Permissions)
+	if err != nil {
+		return model.Role{}, err
+	}
+	// Keep at least one way back in: admin may never lose role management
+	if name == model.RoleAdmin && !contains(permissions, auth.PermRolesManage) {
+		return model.Role{}, fmt.Errorf("the admin role must keep the %s permission", auth.PermRolesManage)
+	}
+	role.Permissions = permissions
+	return s.repo.Update(name, role)
+}
+
+func normalizePermissions(permissions []string) ([]string, error) {
+	seen := make(map[string]bool, len(permissions))
+	normalized := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		if !auth.IsKnownPermission(p) {
+			return nil, fmt.Errorf("unknown permission %q", p)
+		}
+		if !seen[p] {
+			seen[p] = true
+			normalized = append(normalized, p)
+		}
+	}
+	return normalized, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+)
+
+type ShiftService interface {
+	Open(req model.OpenShiftRequest) (*model.Shift, error)
+	GetAll(filter model.ShiftFilter) ([]model.Shift, error)
+	GetByID(id int) (*model.Shift, error)
+	AddCashMovement(shiftID int, req model.CashMovementRequest) (*model.CashMovement, error)
+	Close(id int, req model.CloseShiftRequest) (*model.Shift, error)
+}
+
+type shiftService struct {
+	repo repository.ShiftRepository
+}
+
+func NewShiftService(repo repository.ShiftRepository) ShiftService {
+	return &shiftService{repo: repo}
+}
+
+func (s *shiftService) Open(req model.OpenShiftRequest) (*model.Shift, error) {
+	if req.TerminalID == "" {
+		return nil, fmt.Errorf("%w: log in with a terminal_id to open a shift", repository.ErrInvalidShift)
+	}
+	if req.OpeningFloat < 0 {
+		return nil, fmt.Errorf("%w: opening_float cannot be negative", repository.ErrInvalidShift)
+	}
+	return s.repo.Open(req)
+}
+
+func (s *shiftService) GetAll(filter model.ShiftFilter) ([]model.Shift, error) {
+	if filter.Status != "" && filter.Status != model.ShiftStatusOpen && filter.Status != model.ShiftStatusClosed {
+		return nil, errors.New("status must be 'open' or 'closed'")
+	}
+	return s.repo.GetAll(filter)
+}
+
+func (s *shiftService) GetByID(id int) (*model.Shift, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *shiftService) AddCashMovement(shiftID int, req model.CashMovementRequest) (*model.CashMovement, error) {
+	if req.Type != model.CashMovementPayIn && req.Type != model.CashMovementPayOut {
+		return nil, fmt.Errorf("%w: type must be 'pay_in' or 'pay_out'", repository.ErrInvalidShift)
+	}
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be greater than zero", repository.ErrInvalidShift)
+	}
+	if req.Reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", repository.ErrInvalidShift)
+	}
+	return s.repo.AddCashMovement(shiftID, req)
+}
+
+func (s *shiftService) Close(id int, req model.CloseShiftRequest) (*model.Shift, error) {
+	if req.CountedCash < 0 {
+		return nil, fmt.Errorf("%w: counted_cash cannot be negative", repository.ErrInvalidShift)
+	}
+	return s.repo.Close(id, req)
+}
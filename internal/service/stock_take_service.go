@@ -0,0 +1,74 @@
+package service
+
+import (
+	"fmt"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"strings"
+)
+
+type StockTakeService interface {
+	Open(req model.OpenStockTakeRequest) (*model.StockTake, error)
+	GetAll(filter model.StockTakeFilter) ([]model.StockTake, error)
+	GetByID(id int) (*model.StockTake, error)
+	SubmitCounts(id int, req model.StockCountRequest) (*model.StockTake, error)
+	Post(id int, userID int) (*model.StockTake, error)
+	Cancel(id int, userID int) (*model.StockTake, error)
+}
+
+type stockTakeService struct {
+	repo repository.StockTakeRepository
+}
+
+func NewStockTakeService(repo repository.StockTakeRepository) StockTakeService {
+	return &stockTakeService{repo: repo}
+}
+
+func (s *stockTakeService) Open(req model.OpenStockTakeRequest) (*model.StockTake, error) {
+	if req.CategoryID < 0 {
+		return nil, fmt.Errorf("%w: invalid category_id", repository.ErrInvalidStockTake)
+	}
+	req.Note = strings.TrimSpace(req.Note)
+	return s.repo.Open(req)
+}
+
+func (s *stockTakeService) GetAll(filter model.StockTakeFilter) ([]model.StockTake, error) {
+	switch filter.Status {
+	case "", model.StockTakeStatusOpen, model.StockTakeStatusPosted, model.StockTakeStatusCancelled:
+		return s.repo.GetAll(filter)
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", repository.ErrInvalidStockTake, filter.Status)
+	}
+}
+
+func (s *stockTakeService) GetByID(id int) (*model.StockTake, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *stockTakeService) SubmitCounts(id int, req model.StockCountRequest) (*model.StockTake, error) {
+	if req.Device == "" {
+		return nil, fmt.Errorf("%w: counts must come from a terminal or a named user", repository.ErrInvalidStockTake)
+	}
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("%w: at least one item is required", repository.ErrInvalidStockTake)
+	}
+	seen := make(map[int]bool, len(req.Items))
+	for _, item := range req.Items {
+		if seen[item.ProductID] {
+			return nil, fmt.Errorf("%w: product %d is counted more than once", repository.ErrInvalidStockTake, item.ProductID)
+		}
+		seen[item.ProductID] = true
+		if item.Quantity < 0 {
+			return nil, fmt.Errorf("%w: quantity cannot be negative", repository.ErrInvalidStockTake)
+		}
+	}
+	return s.repo.SubmitCounts(id, req)
+}
+
+func (s *stockTakeService) Post(id int, userID int) (*model.StockTake, error) {
+	return s.repo.Post(id, userID)
+}
+
+func (s *stockTakeService) Cancel(id int, userID int) (*model.StockTake, error) {
+	return s.repo.Cancel(id, userID)
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"fmt"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+	"strings"
+)
+
+type SupplierService interface {
+	Create(s model.Supplier) (*model.Supplier, error)
+	GetAll(search string) ([]model.Supplier, error)
+	GetByID(id int) (*model.Supplier, error)
+	Update(id int, s model.Supplier) (*model.Supplier, error)
+	Delete(id int) error
+}
+
+type supplierService struct {
+	repo repository.SupplierRepository
+}
+
+func NewSupplierService(repo repository.SupplierRepository) SupplierService {
+	return &supplierService{repo: repo}
+}
+
+func (s *supplierService) Create(supplier model.Supplier) (*model.Supplier, error) {
+	if err := validateSupplier(&supplier); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(supplier)
+}
+
+func (s *supplierService) GetAll(search string) ([]model.Supplier, error) {
+	return s.repo.GetAll(strings.TrimSpace(search))
+}
+
+func (s *supplierService) GetByID(id int) (*model.Supplier, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *supplierService) Update(id int, supplier model.Supplier) (*model.Supplier, error) {
+	if err := validateSupplier(&supplier); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(id, supplier)
+}
+
+func (s *supplierService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+// validateSupplier trims the supplier's details in place and checks them.
+func validateSupplier(s *model.Supplier) error {
+	s.Name = strings.TrimSpace(s.Name)
+	s.Phone = strings.TrimSpace(s.Phone)
+	s.Email = strings.TrimSpace(s.Email)
+	s.Address = strings.TrimSpace(s.Address)
+	if s.Name == "" {
+		return fmt.Errorf("%w: name is required", repository.ErrInvalidSupplier)
+	}
+	return nil
+}
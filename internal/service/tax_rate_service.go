@@ -0,0 +1,71 @@
+package service
+
+import (
+	"errors"
+	"kasir-api/internal/model"
+	"kasir-api/internal/pricing"
+	"kasir-api/internal/repository"
+)
+
+type TaxRateService interface {
+	Create(rate model.TaxRate) (model.TaxRate, error)
+	GetAll() ([]model.TaxRate, error)
+	GetByID(id int) (model.TaxRate, error)
+	Update(id int, rate model.TaxRate) (model.TaxRate, error)
+	Delete(id int) error
+}
+
+type taxRateService struct {
+	repo repository.TaxRateRepository
+}
+
+func NewTaxRateService(repo repository.TaxRateRepository) TaxRateService {
+	return &taxRateService{repo: repo}
+}
+
+func (s *taxRateService) Create(rate model.TaxRate) (model.TaxRate, error) {
+	if err := validateTaxRate(rate); err != nil {
+		return model.TaxRate{}, err
+	}
+	return s.repo.Create(rate)
+}
+
+func (s *taxRateService) GetAll() ([]model.TaxRate, error) {
+	return s.repo.GetAll()
+}
+
+func (s *taxRateService) GetByID(id int) (model.TaxRate, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *taxRateService) Update(id int, rate model.TaxRate) (model.TaxRate, error) {
+	if err := validateTaxRate(rate); err != nil {
+		return model.TaxRate{}, err
+	}
+	return s.repo.Update(id, rate)
+}
+
+func (s *taxRateService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+func validateTaxRate(rate model.TaxRate) error {
+	if rate.Name == "" {
+		return errors.New("name is required")
+	}
+	if rate.Rate < 0 || rate.Rate > pricing.BasisPoints {
+		return errors.New("rate_bp must be between 0 and 10000")
+	}
+	return nil
+}
+
+// validateTaxRateID checks that an optional tax rate reference exists.
+func validateTaxRateID(repo repository.TaxRateRepository, id *int) error {
+	if id == nil {
+		return nil
+	}
+	if _, err := repo.GetByID(*id); err != nil {
+		return errors.New("tax rate not found")
+	}
+	return nil
+}
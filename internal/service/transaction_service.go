@@ -1,27 +1,199 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+	"kasir-api/internal/businessday"
 	"kasir-api/internal/model"
 	"kasir-api/internal/repository"
+	"strings"
+	"time"
 )
 
+const (
+	defaultTransactionPageSize = 20
+	maxTransactionPageSize     = 100
+	defaultReportTop           = 5
+	maxReportTop               = 50
+	// maxHourlyReportDays bounds hourly time series, which have 24 buckets a day
+	maxHourlyReportDays = 31
+)
+
+// ErrInvalidFilter is returned when a listing or report filter is malformed.
+var ErrInvalidFilter = errors.New("invalid filter")
+
 type TransactionService interface {
-	Checkout(items []model.CheckoutItem) (*model.Transaction, error)
-	GetSalesReport(startDate, endDate string) (*repository.SalesReport, error)
+	Checkout(req model.CheckoutRequest) (*model.Transaction, error)
+	GetSalesReport(filter model.ReportFilter) (*repository.SalesReport, error)
+	GetTransactions(filter model.TransactionFilter) (*model.TransactionList, error)
+	EachTransaction(filter model.TransactionFilter, fn func(model.Transaction) error) error
+	EachTransactionDetail(filter model.TransactionFilter, fn func(model.TransactionDetail) error) error
+	EachPayment(filter model.TransactionFilter, fn func(model.Payment) error) error
+	GetTransactionByID(id int) (*model.Transaction, error)
+	Refund(transactionID int, req model.RefundRequest) (*model.Refund, error)
+	Void(transactionID int, req model.VoidRequest) (*model.Refund, error)
 }
 
 type transactionService struct {
-	repo repository.TransactionRepository
+	repo     repository.TransactionRepository
+	lowStock *LowStockChecker
+	calendar *businessday.Calendar
+}
+
+// NewTransactionService builds the service; lowStock may be nil to skip
+// low-stock alerts. Reports default to the calendar's current business day.
+func NewTransactionService(repo repository.TransactionRepository, lowStock *LowStockChecker, calendar *businessday.Calendar) TransactionService {
+	return &transactionService{repo: repo, lowStock: lowStock, calendar: calendar}
+}
+
+func (s *transactionService) Checkout(req model.CheckoutRequest) (*model.Transaction, error) {
+	if len(req.Items) == 0 {
+		return nil, fmt.Errorf("%w: items cannot be empty", repository.ErrInvalidCheckout)
+	}
+	for _, item := range req.Items {
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantity must be greater than zero", repository.ErrInvalidCheckout)
+		}
+	}
+	req.VoucherCode = normalizeVoucherCode(req.VoucherCode)
+	req.CustomerRef = strings.TrimSpace(req.CustomerRef)
+
+	transaction, err := s.repo.CreateTransaction(req)
+	if err != nil {
+		return nil, err
+	}
+	if s.lowStock != nil {
+		s.lowStock.TransactionCompleted(transaction.ID)
+	}
+	return transaction, nil
+}
+
+func (s *transactionService) GetSalesReport(filter model.ReportFilter) (*repository.SalesReport, error) {
+	if filter.Top < 1 {
+		filter.Top = defaultReportTop
+	}
+	if filter.Top > maxReportTop {
+		filter.Top = maxReportTop
+	}
+	if filter.StartDate == "" || filter.EndDate == "" {
+		today := s.calendar.Today()
+		if filter.StartDate == "" {
+			filter.StartDate = today
+		}
+		if filter.EndDate == "" {
+			filter.EndDate = today
+		}
+	}
+	if err := validateTransactionFilter(model.TransactionFilter{StartDate: filter.StartDate, EndDate: filter.EndDate}); err != nil {
+		return nil, err
+	}
+	// A single day reads best hour by hour
+	if filter.Interval == "" {
+		filter.Interval = model.ReportIntervalDay
+		if filter.StartDate == filter.EndDate {
+			filter.Interval = model.ReportIntervalHour
+		}
+	}
+	if filter.Interval == model.ReportIntervalHour {
+		start, _ := time.Parse("2006-01-02", filter.StartDate)
+		end, _ := time.Parse("2006-01-02", filter.EndDate)
+		if days := int(end.Sub(start).Hours()/24) + 1; days > maxHourlyReportDays {
+			return nil, fmt.Errorf("%w: interval=hour covers at most %d days, got %d", ErrInvalidFilter, maxHourlyReportDays, days)
+		}
+	}
+	return s.repo.GetSalesReport(filter)
+}
+
+func (s *transactionService) GetTransactions(filter model.TransactionFilter) (*model.TransactionList, error) {
+	if err := validateTransactionFilter(filter); err != nil {
+		return nil, err
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 {
+		filter.Limit = defaultTransactionPageSize
+	}
+	if filter.Limit > maxTransactionPageSize {
+		filter.Limit = maxTransactionPageSize
+	}
+
+	transactions, total, err := s.repo.GetTransactions(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TransactionList{
+		Data:  transactions,
+		Page:  filter.Page,
+		Limit: filter.Limit,
+		Total: total,
+	}, nil
+}
+
+// EachTransaction, EachTransactionDetail and EachPayment stream the whole
+// listing for exports, regardless of Page and Limit.
+func (s *transactionService) EachTransaction(filter model.TransactionFilter, fn func(model.Transaction) error) error {
+	if err := validateTransactionFilter(filter); err != nil {
+		return err
+	}
+	return s.repo.EachTransaction(filter, fn)
+}
+
+func (s *transactionService) EachTransactionDetail(filter model.TransactionFilter, fn func(model.TransactionDetail) error) error {
+	if err := validateTransactionFilter(filter); err != nil {
+		return err
+	}
+	return s.repo.EachTransactionDetail(filter, fn)
+}
+
+func (s *transactionService) EachPayment(filter model.TransactionFilter, fn func(model.Payment) error) error {
+	if err := validateTransactionFilter(filter); err != nil {
+		return err
+	}
+	return s.repo.EachPayment(filter, fn)
+}
+
+func validateTransactionFilter(filter model.TransactionFilter) error {
+	if filter.StartDate != "" {
+		if _, err := time.Parse("2006-01-02", filter.StartDate); err != nil {
+			return fmt.Errorf("%w: start_date must be in YYYY-MM-DD format", ErrInvalidFilter)
+		}
+	}
+	if filter.EndDate != "" {
+		if _, err := time.Parse("2006-01-02", filter.EndDate); err != nil {
+			return fmt.Errorf("%w: end_date must be in YYYY-MM-DD format", ErrInvalidFilter)
+		}
+	}
+	if filter.StartDate != "" && filter.EndDate != "" && filter.StartDate > filter.EndDate {
+		return fmt.Errorf("%w: start_date cannot be after end_date", ErrInvalidFilter)
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		return fmt.Errorf("%w: min_amount cannot be greater than max_amount", ErrInvalidFilter)
+	}
+	return nil
 }
 
-func NewTransactionService(repo repository.TransactionRepository) TransactionService {
-	return &transactionService{repo: repo}
+func (s *transactionService) GetTransactionByID(id int) (*model.Transaction, error) {
+	return s.repo.GetTransactionByID(id)
 }
 
-func (s *transactionService) Checkout(items []model.CheckoutItem) (*model.Transaction, error) {
-	return s.repo.CreateTransaction(items)
+func (s *transactionService) Refund(transactionID int, req model.RefundRequest) (*model.Refund, error) {
+	if req.Reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", repository.ErrInvalidRefund)
+	}
+	for _, item := range req.Items {
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantity must be greater than zero", repository.ErrInvalidRefund)
+		}
+	}
+	return s.repo.CreateRefund(transactionID, model.RefundTypeRefund, req)
 }
 
-func (s *transactionService) GetSalesReport(startDate, endDate string) (*repository.SalesReport, error) {
-	return s.repo.GetSalesReport(startDate, endDate)
+func (s *transactionService) Void(transactionID int, req model.VoidRequest) (*model.Refund, error) {
+	if req.Reason == "" {
+		return nil, fmt.Errorf("%w: reason is required", repository.ErrInvalidRefund)
+	}
+	return s.repo.CreateRefund(transactionID, model.RefundTypeVoid, model.RefundRequest{Reason: req.Reason, TerminalID: req.TerminalID, UserID: req.UserID})
 }
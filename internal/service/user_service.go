@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"kasir-api/internal/model"
+	"kasir-api/internal/repository"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const minPasswordLength = 8
+
+type UserService interface {
+	Create(req model.UserRequest) (model.User, error)
+	GetAll() ([]model.User, error)
+	GetByID(id int) (model.User, error)
+	Update(id int, req model.UserRequest) (model.User, error)
+	EnsureAdmin(username, password string) (bool, error)
+}
+
+type userService struct {
+	repo     repository.UserRepository
+	roleRepo repository.RoleRepository
+}
+
+func NewUserService(repo repository.UserRepository, roleRepo repository.RoleRepository) UserService {
+	return &userService{repo: repo, roleRepo: roleRepo}
+}
+
+func (s *userService) Create(req model.UserRequest) (model.User, error) {
+	if req.Username == "" {
+		return model.User{}, errors.New("username is required")
+	}
+	if err := s.validateRole(req.Role); err != nil {
+		return model.User{}, err
+	}
+	hash, err := hashPassword(req.Password)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	user := model.User{
+		Username:     req.Username,
+		Name:         req.Name,
+		Role:         req.Role,
+		Active:       true,
+		PasswordHash: hash,
+	}
+	if req.Active != nil {
+		user.Active = *req.Active
+	}
+	return s.repo.Create(user)
+}
+
+func (s *userService) GetAll() ([]model.User, error) {
+	return s.repo.GetAll()
+}
+
+func (s *userService) GetByID(id int) (model.User, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *userService) Update(id int, req model.UserRequest) (model.User, error) {
+	if req.Username == "" {
+		return model.User{}, errors.New("username is required")
+	}
+	if err := s.validateRole(req.Role); err != nil {
+		return model.User{}, err
+	}
+
+	user, err := s.repo.GetByID(id)
+	if err != nil {
+		return model.User{}, err
+	}
+	user.Username = req.Username
+	user.Name = req.Name
+	user.Role = req.Role
+	if req.Active != nil {
+		user.Active = *req.Active
+	}
+	if req.Password != "" {
+		user.PasswordHash, err = hashPassword(req.Password)
+		if err != nil {
+			return model.User{}, err
+		}
+	}
+
+	return s.repo.Update(id, user)
+}
+
+// EnsureAdmin creates the first admin account when the users table is empty,
+// so a fresh installation can log in. It reports whether an account was created.
+func (s *userService) EnsureAdmin(username, password string) (bool, error) {
+	count, err := s.repo.Count()
+	if err != nil {
+		return false, err
+	}
+	if count > 0 || username == "" {
+		return false, nil
+	}
+
+	_, err = s.Create(model.UserRequest{
+		Username: username,
+		Password: password,
+		Name:     "Administrator",
+		Role:     model.RoleAdmin,
+	})
+	return err == nil, err
+}
+
+func (s *userService) validateRole(role string) error {
+	if role == "" {
+		return errors.New("role is required")
+	}
+	if _, err := s.roleRepo.GetByName(role); err != nil {
+		return errors.New("role not found")
+	}
+	return nil
+}
+
+func hashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		return "", errors.New("password must be at least 8 characters")
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
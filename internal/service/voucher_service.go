@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+	"kasir-api/internal/model"
+	"kasir-api/internal/pricing"
+	"kasir-api/internal/repository"
+	"strings"
+)
+
+type VoucherService interface {
+	Create(v model.Voucher) (model.Voucher, error)
+	GetAll() ([]model.Voucher, error)
+	GetByID(id int) (model.Voucher, error)
+	Update(id int, v model.Voucher) (model.Voucher, error)
+	Delete(id int) error
+}
+
+type voucherService struct {
+	repo repository.VoucherRepository
+}
+
+func NewVoucherService(repo repository.VoucherRepository) VoucherService {
+	return &voucherService{repo: repo}
+}
+
+func (s *voucherService) Create(v model.Voucher) (model.Voucher, error) {
+	v.Code = normalizeVoucherCode(v.Code)
+	if err := validateVoucher(v); err != nil {
+		return model.Voucher{}, err
+	}
+	return s.repo.Create(v)
+}
+
+func (s *voucherService) GetAll() ([]model.Voucher, error) {
+	return s.repo.GetAll()
+}
+
+func (s *voucherService) GetByID(id int) (model.Voucher, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *voucherService) Update(id int, v model.Voucher) (model.Voucher, error) {
+	v.Code = normalizeVoucherCode(v.Code)
+	if err := validateVoucher(v); err != nil {
+		return model.Voucher{}, err
+	}
+	return s.repo.Update(id, v)
+}
+
+func (s *voucherService) Delete(id int) error {
+	return s.repo.Delete(id)
+}
+
+// normalizeVoucherCode makes codes case insensitive; printed coupons are
+// typed in by hand.
+func normalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func validateVoucher(v model.Voucher) error {
+	if v.Code == "" {
+		return errors.New("code is required")
+	}
+	if v.MinSpend < 0 || v.MaxDiscount < 0 {
+		return errors.New("min_spend and max_discount cannot be negative")
+	}
+	if v.UsageLimit < 0 || v.PerCustomerLimit < 0 {
+		return errors.New("usage_limit and per_customer_limit cannot be negative")
+	}
+
+	switch v.Type {
+	case model.VoucherPercentage:
+		if v.PercentBP <= 0 || v.PercentBP > pricing.BasisPoints {
+			return errors.New("percent_bp must be between 1 and 10000")
+		}
+	case model.VoucherFixedAmount:
+		if v.Amount <= 0 {
+			return errors.New("amount must be greater than zero")
+		}
+	default:
+		return errors.New("type must be either percentage or fixed_amount")
+	}
+	return nil
+}